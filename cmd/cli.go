@@ -0,0 +1,645 @@
+package main
+
+import (
+	"bufio"
+	"cibo/internal/pipelines"
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/cache"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+/*
+Headless mode for running pipelines from cron jobs and scripts without the TUI.
+
+	cibo run lynch -ticker AAPL -start 2015-01-01 -end 2025-01-01 -out data/
+	cibo run lynch-batch -tickersFile watchlist.txt -workers 4 -out data/
+	cibo run ps -ticker AAPL -window 10 -statistic median -out data/
+	cibo run dcf -ticker AAPL -discount 9 -terminalGrowth 2 -horizon 10 -out data/
+	cibo run dividends -ticker IBM -start 2015-01-01 -out data/
+	cibo run earnings-calendar -tickersFile watchlist.txt -name team_earnings -out data/
+
+Progress and results are written to stdout as JSON lines so other tools can consume them,
+and the process exit code tells the caller which class of failure happened.
+*/
+
+const (
+	exitOK          = 0
+	exitUnknown     = 1
+	exitUsage       = 2
+	exitConfig      = 3
+	exitFetch       = 4
+	exitParse       = 5
+	exitCalculation = 6
+	exitWrite       = 7
+	// Batch runs that finished but had at least one ticker fail.
+	exitPartialFailure = 8
+	// Interrupted by a signal or the -timeout deadline.
+	exitCancelled = 9
+)
+
+// One line of structured output. Fields that don't apply to an event are omitted.
+type cliEvent struct {
+	Time       string `json:"time"`
+	Event      string `json:"event"`
+	Stage      string `json:"stage,omitempty"`
+	Ticker     string `json:"ticker,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Guidance   string `json:"guidance,omitempty"`
+	FilePath   string `json:"file_path,omitempty"`
+	OHLCVPath  string `json:"ohlcv_file_path,omitempty"`
+	MetaPath   string `json:"metadata_file_path,omitempty"`
+	EventsPath string `json:"events_file_path,omitempty"`
+	// Only set on Lynch "result" events, the metadata as a key/value parquet table.
+	MetaParquetPath string `json:"metadata_parquet_file_path,omitempty"`
+	// Only set on Lynch "result" events run with -signals.
+	SignalsPath string `json:"signals_file_path,omitempty"`
+	// Only set on DCF "result" events.
+	SensitivityPath string `json:"sensitivity_file_path,omitempty"`
+	ICSPath         string `json:"ics_file_path,omitempty"`
+	RecordCount     int    `json:"record_count,omitempty"`
+	Succeeded       int    `json:"succeeded,omitempty"`
+	Failed          int    `json:"failed,omitempty"`
+	ExitCode        int    `json:"exit_code,omitempty"`
+	// Only set on Lynch "result" events.
+	Model *pipelines.LynchModelSummary `json:"model,omitempty"`
+	// Only set on "cache" events.
+	CacheHits      int `json:"cache_hits,omitempty"`
+	NetworkFetches int `json:"network_fetches,omitempty"`
+}
+
+// Batch runs report from several goroutines at once, so writes are serialized to keep lines whole.
+type cliReporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func newCLIReporter(w io.Writer) *cliReporter {
+	return &cliReporter{encoder: json.NewEncoder(w)}
+}
+
+func (r *cliReporter) emit(event cliEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.Time = time.Now().UTC().Format(time.RFC3339)
+	// Nothing useful can be done if stdout itself is broken, so the error is dropped.
+	_ = r.encoder.Encode(event)
+}
+
+// Progress hook for a single ticker run, emitting each update as a "progress" event.
+func (r *cliReporter) progress(ticker string) pipelines.ProgressFunc {
+	return func(stage pipelines.Stage, message string) {
+		r.emit(cliEvent{Event: "progress", Stage: string(stage), Ticker: ticker, Message: message})
+	}
+}
+
+// Progress hook for runs over several tickers, where each update says which ticker it's for.
+func (r *cliReporter) batchProgress(ticker string, stage pipelines.Stage, message string) {
+	r.emit(cliEvent{Event: "progress", Stage: string(stage), Ticker: ticker, Message: message})
+}
+
+// Emits a finished pipeline's log lines as write stage progress.
+func (r *cliReporter) logs(ticker string, logs []string) {
+	for _, msg := range logs {
+		r.emit(cliEvent{Event: "progress", Stage: string(pipelines.StageWrite), Ticker: ticker, Message: msg})
+	}
+}
+
+// Context for a headless run. Cancelled on Ctrl+C / SIGTERM and, when timeout is non zero,
+// once the deadline passes so cron jobs can't hang forever on a slow API.
+func runContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	return timeoutCtx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func runCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: cibo run <pipeline> [flags]\n\navailable pipelines:\n  lynch\n  lynch-batch\n  ps\n  dcf\n  dividends\n  earnings-calendar")
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "lynch":
+		return runLynch(args[1:], os.Stdout)
+	case "lynch-batch":
+		return runLynchBatch(args[1:], os.Stdout)
+	case "ps":
+		return runPriceToSales(args[1:], os.Stdout)
+	case "dcf":
+		return runDCF(args[1:], os.Stdout)
+	case "dividends":
+		return runDividends(args[1:], os.Stdout)
+	case "earnings-calendar":
+		return runEarningsCalendar(args[1:], os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown pipeline '%s'\n", args[0])
+		return exitUsage
+	}
+}
+
+/*
+The plumbing every run command shares: the client and -timeout flags, setting up the pipelines,
+the run context, the cache summary and reporting a failed run. Commands only add their own flags
+and run their pipeline, so they all behave the same way for the scripts calling them.
+*/
+type cliCommand struct {
+	flags      *flag.FlagSet
+	timeout    *time.Duration
+	clientOpts *clientOptions
+}
+
+func newCLICommand(name string, timeoutUsage string) *cliCommand {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	return &cliCommand{
+		flags:      flags,
+		timeout:    flags.Duration("timeout", 0, timeoutUsage),
+		clientOpts: registerClientFlags(flags),
+	}
+}
+
+// Parses the command line, false when it was invalid and the usage has been printed.
+func (c *cliCommand) parse(args []string) bool {
+	return c.flags.Parse(args) == nil
+}
+
+// False, after printing the usage, when the required -ticker wasn't given.
+func (c *cliCommand) requireTicker(ticker string) bool {
+	if ticker == "" {
+		fmt.Fprintln(os.Stderr, "the -ticker flag is required")
+		c.flags.Usage()
+		return false
+	}
+	return true
+}
+
+// The tickers from -tickers and -tickersFile, false after printing why when there are none.
+func (c *cliCommand) requireTickers(list string, file string) ([]string, bool) {
+	tickers := splitTickers(list)
+	if file != "" {
+		fileTickers, err := readTickersFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read tickers file: %v\n", err)
+			return nil, false
+		}
+		tickers = append(tickers, fileTickers...)
+	}
+	if len(pipelines.NormalizeTickers(tickers)) == 0 {
+		fmt.Fprintln(os.Stderr, "at least one ticker is required via -tickers or -tickersFile")
+		c.flags.Usage()
+		return nil, false
+	}
+	return tickers, true
+}
+
+// Emits a finished run's results and returns the exit code.
+type cliReport func() int
+
+/*
+Sets up the pipelines and run context and hands them to run. An error from run is reported as the
+run failing, against ticker when there is one. Otherwise the report it returns emits the results,
+after the cache summary.
+*/
+func (c *cliCommand) execute(
+	stdout io.Writer,
+	ticker string,
+	run func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error),
+) int {
+	reporter := newCLIReporter(stdout)
+
+	rootPipelines, startupLogs, err := newPipelines(c.clientOpts)
+	if err != nil {
+		reporter.emit(cliEvent{Event: "error", Stage: "config", Message: err.Error(), ExitCode: exitConfig})
+		return exitConfig
+	}
+	for _, msg := range startupLogs {
+		reporter.emit(cliEvent{Event: "progress", Stage: "config", Message: msg})
+	}
+
+	ctx, cancel := runContext(*c.timeout)
+	defer cancel()
+	ctx, fetchReport := cache.WithFetchReport(ctx)
+
+	report, err := run(ctx, rootPipelines, reporter)
+	emitCacheSummary(reporter, fetchReport)
+	if err != nil {
+		code := exitCodeForError(err)
+		reporter.emit(cliEvent{
+			Event:    "error",
+			Stage:    stageForError(err),
+			Ticker:   ticker,
+			Message:  err.Error(),
+			Guidance: pipelines.Guidance(err),
+			ExitCode: code,
+		})
+		return code
+	}
+	return report()
+}
+
+func runLynch(args []string, stdout io.Writer) int {
+	command := newCLICommand("run lynch", "Optional time limit for the whole run, e.g. 2m. Zero means no limit.")
+	ticker := command.flags.String("ticker", "", "Stock ticker to analyze (required).")
+	input := registerLynchFlags(command.flags, "Directory to write the parquet files to. Defaults to the current directory.")
+	if !command.parse(args) || !command.requireTicker(*ticker) {
+		return exitUsage
+	}
+
+	return command.execute(stdout, *ticker, func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		input.Ticker = *ticker
+		input.OnProgress = reporter.progress(*ticker)
+		output, err := rootPipelines.LynchFairValue.RunPipeline(ctx, *input)
+		if err != nil {
+			return nil, err
+		}
+		return func() int {
+			reporter.logs(*ticker, output.Logs)
+			reporter.emit(cliEvent{
+				Event:           "result",
+				Ticker:          *ticker,
+				FilePath:        output.FilePath,
+				OHLCVPath:       output.OHLCVFilePath,
+				SignalsPath:     output.SignalsFilePath,
+				MetaPath:        output.MetadataFilePath,
+				MetaParquetPath: output.MetadataParquetFilePath,
+				RecordCount:     output.RecordCount,
+				Model:           &output.Model,
+			})
+			return exitOK
+		}, nil
+	})
+}
+
+/*
+Lynch pipeline options shared by the single ticker and batch commands, parsed straight into the
+inputs so a new option only needs a flag here to work in both. The ticker and progress hook are
+left for the command to set.
+*/
+func registerLynchFlags(flags *flag.FlagSet, outUsage string) *pipelines.LynchFairValueInputs {
+	input := &pipelines.LynchFairValueInputs{}
+	flags.StringVar(&input.StartDate, "start", "", "Optional start date, YYYY-MM-DD.")
+	flags.StringVar(&input.EndDate, "end", "", "Optional end date, YYYY-MM-DD.")
+	flags.StringVar(&input.OutputDir, "out", "", outUsage)
+	flags.BoolVar(&input.IncludeOHLCV, "ohlcv", false, "Also write the split adjusted OHLCV history to <TICKER>_ohlcv.parquet.")
+	flags.StringVar(&input.CPIFilePath, "cpiFile", "", "CPI series CSV (FRED format). Adds the Shiller CAPE ratio and CAPE fair value series.")
+	flags.BoolVar(&input.UseTTMEPS, "ttm", false, "Build the fair value curve from trailing twelve month EPS, updated every quarter.")
+	flags.BoolVar(&input.ProjectFromEstimates, "project", false,
+		"Project the fair value forward from analyst EPS estimates. Costs one more API call per ticker.")
+	flags.BoolVar(&input.IncludePERatio, "peRatio", false, "Add the daily P/E the market paid and its mean, median and percentile bands to the output.")
+	flags.BoolVar(&input.IncludePEG, "peg", false,
+		"Add the daily PEG and dividend adjusted PEGY ratios to the output. Costs one more API call per ticker.")
+	flags.BoolVar(&input.IncludeGraham, "graham", false,
+		"Add the Graham Number and Graham intrinsic value series. Costs one more API call per ticker.")
+	flags.Float64Var(&input.AAABondYield, "aaaYield", 0,
+		"Current AAA corporate bond yield in percent for the Graham intrinsic value. 0 uses Graham's 4.4.")
+	flags.StringVar(&input.DailyFairValue, "daily", "",
+		"Add a fair value for every trading day and the close's premium over it, by 'step', 'linear' or 'ttm'.")
+	flags.BoolVar(&input.IncludeSignals, "signals", false,
+		"Write the days the close crossed into the over or undervalued band to <TICKER>_signals.parquet.")
+	flags.Float64Var(&input.SignalBands.OvervaluedPct, "overvalued", 0,
+		"Premium over the daily fair value in percent that counts as overvalued. 0 uses 20.")
+	flags.Float64Var(&input.SignalBands.UndervaluedPct, "undervalued", 0,
+		"Discount to the daily fair value in percent that counts as undervalued. 0 uses 20.")
+	registerModelFlags(flags, &input.Model)
+	return input
+}
+
+func runPriceToSales(args []string, stdout io.Writer) int {
+	command := newCLICommand("run ps", "Optional time limit for the whole run, e.g. 2m. Zero means no limit.")
+	flags := command.flags
+	ticker := flags.String("ticker", "", "Stock ticker to analyze (required).")
+	startDate := flags.String("start", "", "Optional start date, YYYY-MM-DD.")
+	endDate := flags.String("end", "", "Optional end date, YYYY-MM-DD.")
+	outputDir := flags.String("out", "", "Directory to write the parquet file to. Defaults to the current directory.")
+	windowYears := flags.Int("window", pipelines.DefaultPriceToSalesWindowYears, "Years of P/S history to take the multiple over. 0 uses the whole date range.")
+	statistic := flags.String("statistic", algos.StatisticMedian, "How to collapse the historical P/S ratios, 'median' or 'mean'.")
+	if !command.parse(args) || !command.requireTicker(*ticker) {
+		return exitUsage
+	}
+
+	return command.execute(stdout, *ticker, func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		output, err := rootPipelines.PriceToSalesFairValue.RunPipeline(ctx, pipelines.PriceToSalesFairValueInputs{
+			Ticker:      *ticker,
+			StartDate:   *startDate,
+			EndDate:     *endDate,
+			OutputDir:   *outputDir,
+			WindowYears: *windowYears,
+			Statistic:   *statistic,
+			OnProgress:  reporter.progress(*ticker),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func() int {
+			reporter.logs(*ticker, output.Logs)
+			reporter.emit(cliEvent{
+				Event:       "result",
+				Ticker:      *ticker,
+				FilePath:    output.FilePath,
+				RecordCount: output.RecordCount,
+			})
+			return exitOK
+		}, nil
+	})
+}
+
+func runDCF(args []string, stdout io.Writer) int {
+	command := newCLICommand("run dcf", "Optional time limit for the whole run, e.g. 2m. Zero means no limit.")
+	flags := command.flags
+	ticker := flags.String("ticker", "", "Stock ticker to analyze (required).")
+	startDate := flags.String("start", "", "Optional start date, YYYY-MM-DD.")
+	endDate := flags.String("end", "", "Optional end date, YYYY-MM-DD.")
+	outputDir := flags.String("out", "", "Directory to write the parquet files to. Defaults to the current directory.")
+	discountRate := flags.Float64("discount", algos.DefaultDCFDiscountRate, "Discount rate in percent, e.g. 10 for 10%.")
+	terminalGrowth := flags.Float64("terminalGrowth", algos.DefaultDCFTerminalGrowth, "Growth after the horizon in percent. Must be below the discount rate.")
+	horizonYears := flags.Int("horizon", algos.DefaultDCFHorizonYears, "Years of free cash flow to project before the terminal value.")
+	if !command.parse(args) || !command.requireTicker(*ticker) {
+		return exitUsage
+	}
+
+	return command.execute(stdout, *ticker, func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		output, err := rootPipelines.DCFFairValue.RunPipeline(ctx, pipelines.DCFFairValueInputs{
+			Ticker:    *ticker,
+			StartDate: *startDate,
+			EndDate:   *endDate,
+			OutputDir: *outputDir,
+			Params: algos.DCFParams{
+				DiscountRate:   *discountRate,
+				TerminalGrowth: *terminalGrowth,
+				HorizonYears:   *horizonYears,
+			},
+			OnProgress: reporter.progress(*ticker),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func() int {
+			reporter.logs(*ticker, output.Logs)
+			reporter.emit(cliEvent{
+				Event:           "result",
+				Ticker:          *ticker,
+				FilePath:        output.FilePath,
+				SensitivityPath: output.SensitivityFilePath,
+				RecordCount:     output.RecordCount,
+			})
+			return exitOK
+		}, nil
+	})
+}
+
+func runDividends(args []string, stdout io.Writer) int {
+	command := newCLICommand("run dividends", "Optional time limit for the whole run, e.g. 2m. Zero means no limit.")
+	flags := command.flags
+	ticker := flags.String("ticker", "", "Stock ticker to analyze (required).")
+	startDate := flags.String("start", "", "Optional start date, YYYY-MM-DD.")
+	endDate := flags.String("end", "", "Optional end date, YYYY-MM-DD.")
+	outputDir := flags.String("out", "", "Directory to write the parquet files to. Defaults to the current directory.")
+	asOf := flags.String("asOf", "", "Optional day to look up the upcoming dividend from, YYYY-MM-DD. Defaults to today.")
+	if !command.parse(args) || !command.requireTicker(*ticker) {
+		return exitUsage
+	}
+
+	return command.execute(stdout, *ticker, func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		output, err := rootPipelines.Dividends.RunPipeline(ctx, pipelines.DividendHistoryInputs{
+			Ticker:     *ticker,
+			StartDate:  *startDate,
+			EndDate:    *endDate,
+			OutputDir:  *outputDir,
+			AsOf:       *asOf,
+			OnProgress: reporter.progress(*ticker),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func() int {
+			reporter.logs(*ticker, output.Logs)
+			reporter.emit(cliEvent{
+				Event:       "result",
+				Ticker:      *ticker,
+				FilePath:    output.FilePath,
+				EventsPath:  output.EventsFilePath,
+				RecordCount: output.RecordCount,
+			})
+			return exitOK
+		}, nil
+	})
+}
+
+func runLynchBatch(args []string, stdout io.Writer) int {
+	command := newCLICommand("run lynch-batch", "Optional time limit for the whole batch, e.g. 30m. Zero means no limit.")
+	flags := command.flags
+	tickerList := flags.String("tickers", "", "Comma separated list of stock tickers.")
+	tickersFile := flags.String("tickersFile", "", "File of tickers, one per line or comma separated. Lines starting with # are ignored.")
+	template := registerLynchFlags(flags, "Directory to write the parquet files and batch summary to. Defaults to the current directory.")
+	workers := flags.Int("workers", pipelines.DefaultBatchWorkers, "Number of tickers to process at the same time.")
+	if !command.parse(args) {
+		return exitUsage
+	}
+	tickers, ok := command.requireTickers(*tickerList, *tickersFile)
+	if !ok {
+		return exitUsage
+	}
+
+	return command.execute(stdout, "", func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		output, err := rootPipelines.LynchFairValueBatch.RunBatch(ctx, pipelines.LynchBatchInputs{
+			Tickers:              tickers,
+			LynchFairValueInputs: *template,
+			Workers:              *workers,
+			OnProgress:           reporter.batchProgress,
+		})
+		// A batch reports every ticker it got through even when it was cut short, so its error is
+		// reported after the results rather than in place of them.
+		return func() int {
+			if output == nil {
+				reporter.emit(cliEvent{Event: "error", Message: err.Error(), ExitCode: exitUsage})
+				return exitUsage
+			}
+
+			for _, result := range output.Results {
+				switch {
+				case result.Success:
+					reporter.emit(cliEvent{Event: "result", Ticker: result.Ticker, FilePath: result.FilePath, OHLCVPath: result.OHLCVPath, SignalsPath: result.SignalsPath, MetaPath: result.MetadataPath, MetaParquetPath: result.MetadataParquetPath, RecordCount: result.RecordCount, Model: result.Model})
+				case result.Skipped:
+					reporter.emit(cliEvent{Event: "skipped", Ticker: result.Ticker, Message: result.Error, ExitCode: exitCancelled})
+				default:
+					reporter.emit(cliEvent{Event: "error", Stage: string(result.FailedStage), Ticker: result.Ticker, Message: result.Error, Guidance: result.Guidance})
+				}
+			}
+
+			if err != nil {
+				code := exitCodeForError(err)
+				reporter.emit(cliEvent{Event: "error", Stage: stageForError(err), Message: err.Error(), ExitCode: code})
+				return code
+			}
+
+			code := exitOK
+			if output.Failed > 0 {
+				code = exitPartialFailure
+			}
+			reporter.emit(cliEvent{
+				Event:     "summary",
+				FilePath:  output.SummaryPath,
+				Succeeded: output.Succeeded,
+				Failed:    output.Failed,
+				ExitCode:  code,
+			})
+			return code
+		}, nil
+	})
+}
+
+func runEarningsCalendar(args []string, stdout io.Writer) int {
+	command := newCLICommand("run earnings-calendar", "Optional time limit for the whole run, e.g. 5m. Zero means no limit.")
+	flags := command.flags
+	tickerList := flags.String("tickers", "", "Comma separated list of stock tickers.")
+	tickersFile := flags.String("tickersFile", "", "File of tickers, one per line or comma separated. Lines starting with # are ignored.")
+	outputDir := flags.String("out", "", "Directory to write the .ics and parquet files to. Defaults to the current directory.")
+	name := flags.String("name", "", "File name for the calendar, without extension. Defaults to <TICKER>_earnings_calendar for one ticker, earnings_calendar for several.")
+	asOf := flags.String("asOf", "", "Optional day the calendar starts from, YYYY-MM-DD. Defaults to today.")
+	if !command.parse(args) {
+		return exitUsage
+	}
+	tickers, ok := command.requireTickers(*tickerList, *tickersFile)
+	if !ok {
+		return exitUsage
+	}
+
+	return command.execute(stdout, "", func(ctx context.Context, rootPipelines *pipelines.Pipelines, reporter *cliReporter) (cliReport, error) {
+		output, err := rootPipelines.EarningsCalendar.RunPipeline(ctx, pipelines.EarningsCalendarInputs{
+			Tickers:    tickers,
+			OutputDir:  *outputDir,
+			Name:       *name,
+			AsOf:       *asOf,
+			OnProgress: reporter.batchProgress,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func() int {
+			for _, failure := range output.Failures {
+				reporter.emit(cliEvent{
+					Event:    "error",
+					Stage:    stageForError(failure.Err),
+					Ticker:   failure.Ticker,
+					Message:  failure.Err.Error(),
+					Guidance: pipelines.Guidance(failure.Err),
+				})
+			}
+			reporter.logs("", output.Logs)
+
+			// The calendar is still written without the failed tickers, but callers need to know it's partial.
+			code := exitOK
+			if len(output.Failures) > 0 {
+				code = exitPartialFailure
+			}
+			reporter.emit(cliEvent{
+				Event:       "result",
+				FilePath:    output.FilePath,
+				ICSPath:     output.ICSFilePath,
+				RecordCount: len(output.Events),
+				Failed:      len(output.Failures),
+				ExitCode:    code,
+			})
+			return code
+		}, nil
+	})
+}
+
+// Reports how many responses were served from the cache, with the hit/miss counts as their own
+// fields so scripts don't have to parse the message.
+func emitCacheSummary(reporter *cliReporter, fetchReport *cache.FetchReport) {
+	summary := fetchReport.Summary()
+	if summary == "" {
+		return
+	}
+	hits, fetches := fetchReport.Counts()
+	reporter.emit(cliEvent{Event: "cache", Stage: string(pipelines.StageFetch), Message: summary, CacheHits: hits, NetworkFetches: fetches})
+}
+
+func splitTickers(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+func readTickersFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var tickers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tickers = append(tickers, splitTickers(line)...)
+	}
+	return tickers, scanner.Err()
+}
+
+func stageForError(err error) string {
+	var stageErr *pipelines.StageError
+	if errors.As(err, &stageErr) {
+		return string(stageErr.Stage)
+	}
+	return ""
+}
+
+// Lynch model flags shared by the single ticker and batch commands.
+func registerModelFlags(flags *flag.FlagSet, params *pipelines.LynchModelParams) {
+	flags.IntVar(&params.CAGRYears, "cagrYears", 0, "Years back from the latest earnings to measure growth over. 0 uses all of them.")
+	flags.Float64Var(&params.PEOverride, "pe", 0, "Fair value P/E to use instead of deriving it from growth. Ignores -minPE and -maxPE.")
+	flags.Float64Var(&params.MinPE, "minPE", 0, "Lower bound for the growth derived fair value P/E. 0 leaves it open.")
+	flags.Float64Var(&params.MaxPE, "maxPE", 0, "Upper bound for the growth derived fair value P/E. 0 leaves it open.")
+	flags.BoolVar(&params.ClassifyEarnings, "classify", false,
+		"Classify the earnings history first, valuing cyclicals on 5 year average EPS and rejecting unprofitable companies.")
+	flags.StringVar(&params.GrowthMethod, "growth", algos.GrowthMethodEndpoints,
+		"How to measure growth, 'endpoints' for the first and last profitable year or 'regression' for a fit through all of them.")
+}
+
+// Maps a pipeline failure to the exit code for its failure class.
+func exitCodeForError(err error) int {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return exitCancelled
+	}
+
+	var stageErr *pipelines.StageError
+	if !errors.As(err, &stageErr) {
+		return exitUnknown
+	}
+
+	switch stageErr.Stage {
+	case pipelines.StageValidate:
+		return exitUsage
+	case pipelines.StageFetch:
+		return exitFetch
+	case pipelines.StageParse:
+		return exitParse
+	case pipelines.StageCalculate:
+		return exitCalculation
+	case pipelines.StageWrite:
+		return exitWrite
+	default:
+		return exitUnknown
+	}
+}
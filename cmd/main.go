@@ -3,6 +3,7 @@ package main
 import (
 	"cibo/internal/pipelines"
 	"cibo/internal/statistics/api"
+	"cibo/internal/statistics/cache"
 	"cibo/internal/statistics/config"
 	"cibo/internal/statistics/io"
 	"cibo/internal/tui"
@@ -11,6 +12,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -21,8 +24,13 @@ const (
 )
 
 func main() {
+	// Headless subcommands skip the TUI entirely, so they get routed before the TUI flags are parsed.
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runCommand(os.Args[2:]))
+	}
+
 	webModeFilePath := flag.String("webMode", "", "Arg to display in standalone web mode, followed by the path to a Parquet file .")
-	useMockAPI := flag.Bool("mockAPI", false, "Use the mock API server.")
+	clientOpts := registerClientFlags(flag.CommandLine)
 	flag.Parse()
 
 	if *webModeFilePath != "" {
@@ -40,10 +48,60 @@ func main() {
 		return
 	}
 
+	pipelines, initialLogs, err := newPipelines(clientOpts)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	p := tea.NewProgram(tui.NewModel(pipelines, initialLogs))
+
+	if _, err := p.Run(); err != nil {
+		log.Fatalf("There's been an error: %v", err)
+	}
+}
+
+// API client settings shared by the TUI and every headless subcommand.
+type clientOptions struct {
+	useMockAPI     bool
+	callsPerMinute int
+	callsPerDay    int
+	quotaFile      string
+	maxAttempts    int
+	cacheDir       string
+	cacheTTLs      string
+	noCache        bool
+	refreshCache   bool
+}
+
+func registerClientFlags(flags *flag.FlagSet) *clientOptions {
+	opts := &clientOptions{}
+	flags.BoolVar(&opts.useMockAPI, "mockAPI", false, "Use the mock API server.")
+	flags.IntVar(&opts.callsPerMinute, "callsPerMinute", api.DefaultCallsPerMinute, "Max Alpha Vantage calls per minute. 0 disables the limit.")
+	flags.IntVar(&opts.callsPerDay, "callsPerDay", api.DefaultCallsPerDay, "Max Alpha Vantage calls per day. 0 disables the limit.")
+	flags.StringVar(&opts.quotaFile, "quotaFile", defaultCachePath("alphavantage_quota.json"), "File the daily API call count is persisted to between runs.")
+	flags.IntVar(&opts.maxAttempts, "maxAttempts", api.DefaultRetryPolicy.MaxAttempts, "Max tries per API call when throttled or the network fails. 1 disables retries.")
+	flags.StringVar(&opts.cacheDir, "cacheDir", defaultCachePath("responses"), "Directory raw API responses are cached in.")
+	flags.StringVar(&opts.cacheTTLs, "cacheTTL", "", "Per endpoint cache TTL overrides, e.g. TIME_SERIES_DAILY=12h,SPLITS=720h. 0 disables caching for an endpoint.")
+	flags.BoolVar(&opts.noCache, "noCache", false, "Skip the response cache entirely.")
+	flags.BoolVar(&opts.refreshCache, "refresh", false, "Ignore cached responses and fetch everything from the API, updating the cache.")
+	return opts
+}
+
+func defaultCachePath(name string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "cibo", name)
+}
+
+// Shared setup of config, API client and writers for both the TUI and headless modes.
+// Returns the startup log messages so each mode can display them its own way.
+func newPipelines(opts *clientOptions) (*pipelines.Pipelines, []string, error) {
 	var initialLogs []string
 
 	var baseURL string
-	if *useMockAPI {
+	if opts.useMockAPI {
 		baseURL = mockAlphaVantageURL
 		initialLogs = append(initialLogs, "Using mock API server.")
 	} else {
@@ -53,24 +111,53 @@ func main() {
 
 	configPath := os.Getenv("API_KEYS_CONFIG_PATH")
 	if configPath == "" {
-		log.Fatal("Error: API_KEYS_CONFIG_PATH environment variable not set.")
+		return nil, nil, fmt.Errorf("API_KEYS_CONFIG_PATH environment variable not set")
 	}
 
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		return nil, nil, fmt.Errorf("loading configuration: %w", err)
 	}
 
 	initialLogs = append(initialLogs, fmt.Sprintf("Successfully loaded configuration from: %s", configPath))
 
-	apiClient := api.NewClient(cfg.AlphaVantageAPIKey, baseURL)
-	parquetWriter := io.NewParquetClient()
-
-	pipelines := pipelines.NewPipelines(apiClient, parquetWriter)
+	retryPolicy := api.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = opts.maxAttempts
+	clientOpts := []api.ClientOption{api.WithRetryPolicy(retryPolicy)}
+	// The mock server has no limits, so don't spend the real daily budget on it.
+	if !opts.useMockAPI {
+		limiter, err := api.NewRateLimiter(opts.callsPerMinute, opts.callsPerDay, opts.quotaFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting up API rate limiter: %w", err)
+		}
+		clientOpts = append(clientOpts, api.WithRateLimiter(limiter))
 
-	p := tea.NewProgram(tui.NewModel(pipelines, initialLogs))
+		if remaining, resetsAt := limiter.Remaining(); remaining >= 0 {
+			initialLogs = append(initialLogs, fmt.Sprintf("API budget: %d calls left today, resets at %s",
+				remaining, resetsAt.Local().Format(time.Kitchen)))
+		}
+	}
 
-	if _, err := p.Run(); err != nil {
-		log.Fatalf("There's been an error: %v", err)
+	var apiClient pipelines.APIClient = api.NewClient(cfg.AlphaVantageAPIKey, baseURL, clientOpts...)
+	if !opts.noCache && opts.cacheDir != "" {
+		ttls, err := cache.ParseTTLOverrides(opts.cacheTTLs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting up response cache: %w", err)
+		}
+		cacheDir := opts.cacheDir
+		// Mock responses are fake data, keep them from ever being served in place of real ones.
+		if opts.useMockAPI {
+			cacheDir = filepath.Join(cacheDir, "mock")
+		}
+		apiClient = cache.NewCachingClient(apiClient, cacheDir, ttls, opts.refreshCache)
+		if opts.refreshCache {
+			initialLogs = append(initialLogs, "Response cache refresh requested, fetching everything from the API.")
+		} else {
+			initialLogs = append(initialLogs, fmt.Sprintf("Caching API responses in: %s", cacheDir))
+		}
 	}
+	parquetWriter := io.NewParquetClient()
+	calendarWriter := io.NewICalendarClient()
+
+	return pipelines.NewPipelines(apiClient, parquetWriter, calendarWriter), initialLogs, nil
 }
@@ -0,0 +1,183 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/parse"
+	"cibo/internal/statistics/utils"
+	"cibo/internal/types"
+	"context"
+	"fmt"
+	"io"
+)
+
+// DCFFairValuePipeline values a company on its discounted free cash flow. It sits next to
+// LynchFairValuePipeline and writes its fair value under its own series so both can be charted
+// together, plus a sensitivity grid of the latest year at other discount rates and terminal growths.
+
+const (
+	DCFFileSuffix            = "dcf"
+	DCFSensitivityFileSuffix = "dcf_sensitivity"
+)
+
+type DCFFairValuePipeline struct {
+	apiClient     APIClient
+	parquetWriter ParquetWriter
+}
+
+type DCFFairValueInputs struct {
+	Ticker    string
+	StartDate string
+	EndDate   string
+	// Where <TICKER>_dcf.parquet and <TICKER>_dcf_sensitivity.parquet are written, see
+	// prepareOutputDir.
+	OutputDir string
+	// Discount rate, terminal growth and horizon. The zero value uses the defaults with no terminal
+	// growth, see algos.DCFParams.
+	Params algos.DCFParams
+	// Optional, see ProgressFunc.
+	OnProgress ProgressFunc
+}
+
+type DCFFairValueOutputs struct {
+	RecordCount         int
+	FilePath            string
+	SensitivityFilePath string
+	// How the latest year in the range was valued, with the parameters the run resolved to.
+	Summary           algos.DCFSummary
+	CombinedPriceData []types.CombinedPriceRecord
+	Sensitivity       []types.DCFSensitivityRecord
+	Logs              []string
+}
+
+func NewDCFFairValuePipeline(client APIClient, writer ParquetWriter) *DCFFairValuePipeline {
+	return &DCFFairValuePipeline{
+		apiClient:     client,
+		parquetWriter: writer,
+	}
+}
+
+func (p *DCFFairValuePipeline) RunPipeline(ctx context.Context, input DCFFairValueInputs) (*DCFFairValueOutputs, error) {
+	if err := utils.ValidateDateRange(input.StartDate, input.EndDate); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+	if err := input.Params.Resolve().Validate(); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+
+	input.OnProgress.report(StageFetch, fmt.Sprintf("fetching data for %s", input.Ticker))
+	dailyPricesJson, err := p.apiClient.FetchDailyPrice(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("daily prices", err)
+	}
+	cashFlowJson, err := p.apiClient.FetchCashFlow(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("cash flow", err)
+	}
+	balanceSheetJson, err := p.apiClient.FetchBalanceSheet(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("balance sheet", err)
+	}
+	stockSplitsJson, err := p.apiClient.FetchStockSplits(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("stock splits", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageParse, Err: err}
+	}
+	input.OnProgress.report(StageParse, "parsing API responses")
+	dailyPricesRecords, err := parse.ParseDailyPricesToFlat(dailyPricesJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "daily prices parsing failed: %w", err)
+	}
+	freeCashFlowRecords, err := parse.ParseFreeCashFlowToFlat(cashFlowJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "cash flow parsing failed: %w", err)
+	}
+	sharesRecords, err := parse.ParseSharesOutstandingToFlat(balanceSheetJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "balance sheet parsing failed: %w", err)
+	}
+	stockSplitRecords, err := parse.ParseStockSplitsToFlat(stockSplitsJson)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "stock splits parsing failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageCalculate, Err: err}
+	}
+	input.OnProgress.report(StageCalculate, "calculating discounted cash flow fair value history")
+	adjustedDailyPrices := utils.AdjustForStockSplits(dailyPricesRecords, stockSplitRecords)
+	filteredDailyPrices, err := utils.FilterDailyPricesWithinDateRange(adjustedDailyPrices, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter daily prices: %w", err)
+	}
+
+	// Share counts are as reported, so restate them in today's shares to match adjusted prices.
+	adjustedShares := utils.AdjustSharesForStockSplits(sharesRecords, stockSplitRecords)
+	freeCashFlowPerShare := algos.FreeCashFlowPerShare(freeCashFlowRecords, adjustedShares)
+
+	// Growth is measured from every year known by then, so only the end of the range applies before
+	// valuing and the first years in it still see the years before the start.
+	freeCashFlowToEnd, err := utils.FilterFreeCashFlowPerShareWithinDateRange(freeCashFlowPerShare, "", input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter free cash flow per share: %w", err)
+	}
+	fairValuePriceRecords, summary, err := algos.CalculateDCFFairValueHistory(freeCashFlowToEnd, input.Params)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "could not calculate DCF fair value: %w", err)
+	}
+	filteredFairValues, err := utils.FilterFairValuesWithinDateRange(fairValuePriceRecords, input.StartDate, "")
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter DCF fair values: %w", err)
+	}
+	if len(filteredFairValues) == 0 {
+		return nil, stageErrorf(StageCalculate, "%w: no fiscal year in the date range with positive free cash flow per share",
+			ErrInsufficientCashFlow)
+	}
+	sensitivity := algos.DCFSensitivity(input.Ticker, summary)
+
+	combinedData := types.DailyAndFairPriceToCombined(filteredDailyPrices, nil)
+	combinedData = append(combinedData, types.FairValueToCombined(filteredFairValues, types.SeriesDCFFairValue)...)
+
+	if err := prepareOutputDir(ctx, input.OutputDir); err != nil {
+		return nil, err
+	}
+
+	fileName := tickerFileName(input.OutputDir, input.Ticker, DCFFileSuffix)
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d records to %s", len(combinedData), fileName))
+	absPath, writeLogMessage, err := writeParquetFile(fileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteCombinedPriceDataToParquet(combinedData, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sensitivityFileName := tickerFileName(input.OutputDir, input.Ticker, DCFSensitivityFileSuffix)
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d sensitivity cells to %s", len(sensitivity), sensitivityFileName))
+	sensitivityAbsPath, sensitivityLogMessage, err := writeParquetFile(sensitivityFileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteDCFSensitivityToParquet(sensitivity, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &DCFFairValueOutputs{
+		RecordCount:         len(filteredDailyPrices),
+		FilePath:            absPath,
+		SensitivityFilePath: sensitivityAbsPath,
+		Summary:             summary,
+		CombinedPriceData:   combinedData,
+		Sensitivity:         sensitivity,
+		Logs: []string{
+			fmt.Sprintf("DCF fair value %.2f as of %s, from %.2f free cash flow per share growing %.2f%% a year for %d years, "+
+				"discounted at %.2f%% with %.2f%% terminal growth",
+				summary.FairValue, summary.FiscalDateEnding, summary.FreeCashFlowPerShare, summary.Growth*100,
+				summary.HorizonYears, summary.DiscountRate, summary.TerminalGrowth),
+			writeLogMessage,
+			sensitivityLogMessage,
+		},
+	}
+
+	return output, nil
+}
@@ -0,0 +1,125 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/types"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newDCFMockClient() *mockAPIClient {
+	client := newSplitPriceMockClient()
+	client.cashFlowResponse = []byte(`{
+		"symbol": "TEST",
+		"annualReports": [
+			{"fiscalDateEnding": "2024-12-31", "operatingCashflow": "1300", "capitalExpenditures": "100"},
+			{"fiscalDateEnding": "2023-12-31", "operatingCashflow": "1100", "capitalExpenditures": "100"}
+		]
+	}`)
+	client.balanceSheetResponse = []byte(`{
+		"symbol": "TEST",
+		"annualReports": [
+			{"fiscalDateEnding": "2024-12-31", "commonStockSharesOutstanding": "100"},
+			{"fiscalDateEnding": "2023-12-31", "commonStockSharesOutstanding": "100"}
+		]
+	}`)
+	return client
+}
+
+// Given a 2-for-1 split after both fiscal years, verify free cash flow per share is on adjusted
+// shares, each year is valued at the growth known by then, and the sensitivity grid is written to
+// its own file.
+func TestDCFFairValuePipeline_RunPipeline_Success(t *testing.T) {
+	mockWriter := &mockParquetWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewDCFFairValuePipeline(newDCFMockClient(), mockWriter)
+	output, err := pipeline.RunPipeline(context.Background(), DCFFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: outputDir,
+		Params:    algos.DCFParams{TerminalGrowth: 2},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	// Adjusted shares are 200, so FCF per share is 1000/200 = 5 and 1200/200 = 6. The first year
+	// has no growth to measure and grows at the terminal rate.
+	params := algos.DCFParams{DiscountRate: 10, TerminalGrowth: 2, HorizonYears: 10}
+	if output.Summary.FiscalDateEnding != "2024-12-31" || output.Summary.FreeCashFlowPerShare != 6 {
+		t.Errorf("Expected the summary to value 6 FCF per share in 2024, got %+v", output.Summary)
+	}
+	if output.Summary.Growth <= 0.02 || output.Summary.Growth > algos.DCFMaxGrowth {
+		t.Errorf("Expected a measured growth between the terminal growth and the cap, got %v", output.Summary.Growth)
+	}
+	expectedData := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-10", Price: 110, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 100, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-12-31", Price: algos.DCFValue(6, output.Summary.Growth, params), Series: types.SeriesDCFFairValue},
+		{Ticker: "TEST", Date: "2024-06-28", Price: 90, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2023-12-31", Price: algos.DCFValue(5, 0.02, params), Series: types.SeriesDCFFairValue},
+		{Ticker: "TEST", Date: "2023-12-29", Price: 90, Series: types.SeriesDailyPrice},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool {
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		return a.Series < b.Series
+	})
+
+	if diff := cmp.Diff(expectedData, output.CombinedPriceData, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() mismatch in CombinedPriceData (-want +got):\n%s", diff)
+	}
+	if len(mockWriter.receivedSensitivity) != 25 {
+		t.Errorf("Expected a 5x5 sensitivity grid to be written, got %d cells", len(mockWriter.receivedSensitivity))
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_dcf.parquet"), output.FilePath); diff != "" {
+		t.Errorf("RunPipeline() FilePath mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_dcf_sensitivity.parquet"), output.SensitivityFilePath); diff != "" {
+		t.Errorf("RunPipeline() SensitivityFilePath mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given terminal growth at the discount rate, verify the run fails validation before anything is fetched.
+func TestDCFFairValuePipeline_RunPipeline_InvalidParams(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewDCFFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), DCFFairValueInputs{
+		Ticker: "TEST",
+		Params: algos.DCFParams{DiscountRate: 8, TerminalGrowth: 8},
+	})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, but got: %v", err)
+	}
+	if !errors.Is(err, ErrInvalidDCFParams) || Guidance(err) == "" {
+		t.Errorf("Expected an invalid DCF params error with guidance, but got: %v", err)
+	}
+}
+
+// Given a date range that ends before the first year of cash flow, verify the run fails with an
+// insufficient cash flow error and writes nothing.
+func TestDCFFairValuePipeline_RunPipeline_InsufficientCashFlow(t *testing.T) {
+	mockWriter := &mockParquetWriter{}
+
+	pipeline := NewDCFFairValuePipeline(newDCFMockClient(), mockWriter)
+	_, err := pipeline.RunPipeline(context.Background(), DCFFairValueInputs{Ticker: "TEST", EndDate: "2023-06-30"})
+
+	if !errors.Is(err, ErrInsufficientCashFlow) {
+		t.Errorf("Expected an insufficient cash flow error, but got: %v", err)
+	}
+	if Guidance(err) == "" {
+		t.Error("Expected guidance for an insufficient cash flow error")
+	}
+	if mockWriter.wasCalled {
+		t.Error("WriteCombinedPriceDataToParquet should not be called when the calculation fails")
+	}
+}
@@ -0,0 +1,190 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/parse"
+	"cibo/internal/statistics/utils"
+	"cibo/internal/types"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DividendHistoryPipeline builds a company's dividend history: every dividend event in the date range,
+// a trailing twelve month yield for each trading day, how often it pays and what's coming up next.
+// Events and the yield series go to separate files since they have different shapes.
+
+const (
+	DividendEventsFileSuffix = "dividends"
+	DividendYieldFileSuffix  = "dividend_yield"
+)
+
+type DividendHistoryPipeline struct {
+	apiClient     APIClient
+	parquetWriter ParquetWriter
+}
+
+type DividendHistoryInputs struct {
+	Ticker    string
+	StartDate string
+	EndDate   string
+	// Where <TICKER>_dividend_yield.parquet and <TICKER>_dividends.parquet are written, see
+	// prepareOutputDir.
+	OutputDir string
+	// Day the upcoming dividend is looked up from, YYYY-MM-DD. Empty means today.
+	AsOf string
+	// Optional, see ProgressFunc.
+	OnProgress ProgressFunc
+}
+
+type DividendHistoryOutputs struct {
+	// Trading days in the yield series.
+	RecordCount int
+	// Path of the yield series file.
+	FilePath string
+	// Path of the dividend events file.
+	EventsFilePath string
+	Schedule       algos.DividendSchedule
+	// The next declared dividend that hasn't been paid yet, nil if none is declared.
+	Upcoming *types.DividendRecord
+	// Next ex-dividend date estimated from the schedule. Empty when there's no regular schedule.
+	EstimatedNextExDividendDate string
+	Dividends                   []types.DividendRecord
+	CombinedPriceData           []types.CombinedPriceRecord
+	Logs                        []string
+}
+
+func NewDividendHistoryPipeline(client APIClient, writer ParquetWriter) *DividendHistoryPipeline {
+	return &DividendHistoryPipeline{
+		apiClient:     client,
+		parquetWriter: writer,
+	}
+}
+
+func (p *DividendHistoryPipeline) RunPipeline(ctx context.Context, input DividendHistoryInputs) (*DividendHistoryOutputs, error) {
+	if err := utils.ValidateDateRange(input.StartDate, input.EndDate); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+	asOf, err := resolveAsOf(input.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	input.OnProgress.report(StageFetch, fmt.Sprintf("fetching data for %s", input.Ticker))
+	dailyPricesJson, err := p.apiClient.FetchDailyPrice(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("daily prices", err)
+	}
+	dividendsJson, err := p.apiClient.FetchDividends(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("dividends", err)
+	}
+	stockSplitsJson, err := p.apiClient.FetchStockSplits(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("stock splits", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageParse, Err: err}
+	}
+	input.OnProgress.report(StageParse, "parsing API responses")
+	dailyPricesRecords, err := parse.ParseDailyPricesToFlat(dailyPricesJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "daily prices parsing failed: %w", err)
+	}
+	dividendRecords, err := parse.ParseDividendsToFlat(dividendsJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "dividends parsing failed: %w", err)
+	}
+	stockSplitRecords, err := parse.ParseStockSplitsToFlat(stockSplitsJson)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "stock splits parsing failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageCalculate, Err: err}
+	}
+	input.OnProgress.report(StageCalculate, "calculating dividend yield history")
+	adjustedDailyPrices := utils.AdjustForStockSplits(dailyPricesRecords, stockSplitRecords)
+	filteredDailyPrices, err := utils.FilterDailyPricesWithinDateRange(adjustedDailyPrices, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter daily prices: %w", err)
+	}
+
+	adjustedDividends := utils.AdjustDividendsForStockSplits(dividendRecords, stockSplitRecords)
+	filteredDividends, err := utils.FilterDividendsWithinDateRange(adjustedDividends, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter dividends: %w", err)
+	}
+
+	// The yield, schedule and upcoming dividend use the full history, so the first year of the
+	// range still sees the dividends paid just before it and the schedule reflects today.
+	yields := algos.TrailingTwelveMonthYield(filteredDailyPrices, adjustedDividends)
+	schedule := algos.DetectDividendSchedule(adjustedDividends)
+
+	var upcoming *types.DividendRecord
+	if dividend, ok := algos.UpcomingDividend(adjustedDividends, asOf); ok {
+		upcoming = &dividend
+	}
+	estimatedNext, _ := algos.EstimateNextExDividendDate(adjustedDividends, schedule, asOf)
+
+	combinedData := types.DailyAndFairPriceToCombined(filteredDailyPrices, nil)
+	combinedData = append(combinedData, types.DividendYieldToCombined(yields)...)
+
+	if err := prepareOutputDir(ctx, input.OutputDir); err != nil {
+		return nil, err
+	}
+
+	eventsFileName := tickerFileName(input.OutputDir, input.Ticker, DividendEventsFileSuffix)
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d dividends to %s", len(filteredDividends), eventsFileName))
+	eventsAbsPath, eventsLogMessage, err := writeParquetFile(eventsFileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteDividendsToParquet(filteredDividends, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := tickerFileName(input.OutputDir, input.Ticker, DividendYieldFileSuffix)
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d records to %s", len(combinedData), fileName))
+	absPath, writeLogMessage, err := writeParquetFile(fileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteCombinedPriceDataToParquet(combinedData, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []string{fmt.Sprintf("Dividend schedule: %s", schedule.Frequency)}
+	if upcoming != nil {
+		logs = append(logs, fmt.Sprintf("Upcoming dividend: %.4f per share, ex-dividend %s, paid %s",
+			upcoming.Amount, upcoming.ExDividendDate, upcoming.PaymentDate))
+	} else if estimatedNext != "" {
+		logs = append(logs, fmt.Sprintf("No dividend declared yet, next ex-dividend date estimated around %s", estimatedNext))
+	}
+	logs = append(logs, eventsLogMessage, writeLogMessage)
+
+	output := &DividendHistoryOutputs{
+		RecordCount:                 len(yields),
+		FilePath:                    absPath,
+		EventsFilePath:              eventsAbsPath,
+		Schedule:                    schedule,
+		Upcoming:                    upcoming,
+		EstimatedNextExDividendDate: estimatedNext,
+		Dividends:                   filteredDividends,
+		CombinedPriceData:           combinedData,
+		Logs:                        logs,
+	}
+
+	return output, nil
+}
+
+// The day an "upcoming" lookup is done from, today unless the caller asked for another day.
+func resolveAsOf(asOf string) (string, error) {
+	if asOf == "" {
+		return time.Now().Format("2006-01-02"), nil
+	}
+	if _, err := time.Parse("2006-01-02", asOf); err != nil {
+		return "", stageErrorf(StageValidate, "%w: invalid as of date format: %w", ErrInvalidDateRange, err)
+	}
+	return asOf, nil
+}
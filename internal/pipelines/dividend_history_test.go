@@ -0,0 +1,130 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/types"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newDividendMockClient() *mockAPIClient {
+	client := newSplitPriceMockClient()
+	client.dividendsResponse = []byte(`{
+		"symbol": "TEST",
+		"data": [
+			{"ex_dividend_date": "2025-02-07", "declaration_date": "2025-01-25", "record_date": "2025-02-07", "payment_date": "2025-03-01", "amount": "0.50"},
+			{"ex_dividend_date": "2024-11-08", "declaration_date": "None", "record_date": "None", "payment_date": "2024-12-01", "amount": "1.00"},
+			{"ex_dividend_date": "2024-08-09", "declaration_date": "None", "record_date": "None", "payment_date": "2024-09-01", "amount": "1.00"},
+			{"ex_dividend_date": "2024-05-10", "declaration_date": "None", "record_date": "None", "payment_date": "2024-06-01", "amount": "1.00"},
+			{"ex_dividend_date": "2024-02-09", "declaration_date": "None", "record_date": "None", "payment_date": "2024-03-01", "amount": "1.00"}
+		]
+	}`)
+	return client
+}
+
+// Given a 2-for-1 split partway through a quarterly dividend history, verify dividends are
+// restated alongside prices, the yield looks back past the start of the range, and the declared
+// but unpaid dividend is reported as upcoming.
+func TestDividendHistoryPipeline_RunPipeline_Success(t *testing.T) {
+	mockWriter := &mockParquetWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewDividendHistoryPipeline(newDividendMockClient(), mockWriter)
+	output, err := pipeline.RunPipeline(context.Background(), DividendHistoryInputs{
+		Ticker:    "TEST",
+		StartDate: "2024-06-01",
+		OutputDir: outputDir,
+		AsOf:      "2025-01-30",
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	// Pre split dividends are 0.50 a quarter in today's shares. The June yield counts the February
+	// and May dividends even though February is before the start date.
+	expectedData := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-10", Price: 110, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2025-01-10", Price: 2.0 / 110, Series: types.SeriesDividendYield},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 100, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 0.02, Series: types.SeriesDividendYield},
+		{Ticker: "TEST", Date: "2024-06-28", Price: 90, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-06-28", Price: 1.0 / 90, Series: types.SeriesDividendYield},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool {
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		return a.Series < b.Series
+	})
+	if diff := cmp.Diff(expectedData, output.CombinedPriceData, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() mismatch in CombinedPriceData (-want +got):\n%s", diff)
+	}
+
+	expectedDividends := []types.DividendRecord{
+		{Ticker: "TEST", ExDividendDate: "2025-02-07", DeclarationDate: "2025-01-25", RecordDate: "2025-02-07", PaymentDate: "2025-03-01", Amount: 0.5},
+		{Ticker: "TEST", ExDividendDate: "2024-11-08", PaymentDate: "2024-12-01", Amount: 0.5},
+		{Ticker: "TEST", ExDividendDate: "2024-08-09", PaymentDate: "2024-09-01", Amount: 0.5},
+	}
+	if diff := cmp.Diff(expectedDividends, mockWriter.receivedDividends); diff != "" {
+		t.Errorf("Written dividends mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(algos.DividendSchedule{Frequency: algos.FrequencyQuarterly, IntervalDays: 91}, output.Schedule); diff != "" {
+		t.Errorf("Schedule mismatch (-want +got):\n%s", diff)
+	}
+	if output.Upcoming == nil || output.Upcoming.ExDividendDate != "2025-02-07" {
+		t.Errorf("Expected the 2025-02-07 dividend to be upcoming, got %+v", output.Upcoming)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_dividend_yield.parquet"), output.FilePath); diff != "" {
+		t.Errorf("RunPipeline() FilePath mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_dividends.parquet"), output.EventsFilePath); diff != "" {
+		t.Errorf("RunPipeline() EventsFilePath mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a company that has never paid a dividend, verify the run still succeeds with a zero yield
+// and nothing upcoming.
+func TestDividendHistoryPipeline_RunPipeline_NoDividends(t *testing.T) {
+	mockClient := newDividendMockClient()
+	mockClient.dividendsResponse = []byte(`{"symbol": "TEST", "data": []}`)
+
+	pipeline := NewDividendHistoryPipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), DividendHistoryInputs{Ticker: "TEST", OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	for _, record := range output.CombinedPriceData {
+		if record.Series == types.SeriesDividendYield && record.Price != 0 {
+			t.Errorf("Expected a zero yield on %s, got %v", record.Date, record.Price)
+		}
+	}
+	if output.Schedule.Frequency != algos.FrequencyNone {
+		t.Errorf("Expected no dividend schedule, got %s", output.Schedule.Frequency)
+	}
+	if output.Upcoming != nil || output.EstimatedNextExDividendDate != "" {
+		t.Errorf("Expected nothing upcoming, got %+v and %q", output.Upcoming, output.EstimatedNextExDividendDate)
+	}
+}
+
+// Given a malformed as of date, verify the run fails validation before anything is fetched.
+func TestDividendHistoryPipeline_RunPipeline_InvalidAsOf(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewDividendHistoryPipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), DividendHistoryInputs{Ticker: "TEST", AsOf: "01/30/2025"})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, but got: %v", err)
+	}
+	if !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("Expected an invalid date range error, but got: %v", err)
+	}
+}
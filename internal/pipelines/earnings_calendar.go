@@ -0,0 +1,167 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/parse"
+	"cibo/internal/types"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// EarningsCalendarGenerator collects the upcoming earnings reports of a list of tickers into one
+// calendar, written both as an .ics file to import into shared calendars and as a parquet table.
+// Like the batch runner, a ticker that fails is recorded and skipped so one bad symbol doesn't
+// cost the whole calendar. Tickers run one after another since each only needs two API calls.
+
+const (
+	EarningsCalendarFileSuffix = "earnings_calendar"
+	// File name used when the calendar covers more than one ticker.
+	DefaultEarningsCalendarName = "earnings_calendar"
+)
+
+type EarningsCalendarGenerator struct {
+	apiClient      APIClient
+	parquetWriter  ParquetWriter
+	calendarWriter CalendarWriter
+}
+
+type EarningsCalendarInputs struct {
+	Tickers []string
+	// Where the .ics and parquet files are written, see prepareOutputDir.
+	OutputDir string
+	// File name, without extension, for both files. Empty uses <TICKER>_earnings_calendar for a
+	// single ticker and DefaultEarningsCalendarName for several.
+	Name string
+	// Day the calendar starts from, YYYY-MM-DD. Empty means today.
+	AsOf string
+	// Optional, see TickerProgressFunc.
+	OnProgress TickerProgressFunc
+}
+
+type EarningsCalendarFailure struct {
+	Ticker string
+	Err    error
+}
+
+type EarningsCalendarOutputs struct {
+	// Events for every ticker that succeeded, ordered by report date.
+	Events []types.EarningsEventRecord
+	// Path of the parquet table.
+	FilePath string
+	// Path of the iCalendar file.
+	ICSFilePath string
+	// Tickers left out of the calendar and why, in the order they were given.
+	Failures []EarningsCalendarFailure
+	Logs     []string
+}
+
+func NewEarningsCalendarGenerator(client APIClient, writer ParquetWriter, calendarWriter CalendarWriter) *EarningsCalendarGenerator {
+	return &EarningsCalendarGenerator{
+		apiClient:      client,
+		parquetWriter:  writer,
+		calendarWriter: calendarWriter,
+	}
+}
+
+func (p *EarningsCalendarGenerator) RunPipeline(ctx context.Context, input EarningsCalendarInputs) (*EarningsCalendarOutputs, error) {
+	tickers := NormalizeTickers(input.Tickers)
+	if len(tickers) == 0 {
+		return nil, stageErrorf(StageValidate, "no tickers provided for the earnings calendar")
+	}
+	asOf, err := resolveAsOf(input.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &EarningsCalendarOutputs{}
+	for _, ticker := range tickers {
+		if err := ctx.Err(); err != nil {
+			return nil, &StageError{Stage: StageFetch, Err: err}
+		}
+
+		events, err := p.tickerEvents(ctx, ticker, asOf, input)
+		if err != nil {
+			output.Failures = append(output.Failures, EarningsCalendarFailure{Ticker: ticker, Err: err})
+			continue
+		}
+		if len(events) == 0 {
+			output.Logs = append(output.Logs, fmt.Sprintf("No upcoming earnings found for %s", ticker))
+		}
+		output.Events = append(output.Events, events...)
+	}
+
+	// An empty calendar because every ticker failed is an error, not a result.
+	if len(output.Failures) == len(tickers) {
+		return nil, output.Failures[0].Err
+	}
+
+	sort.SliceStable(output.Events, func(i, j int) bool {
+		if output.Events[i].ReportDate != output.Events[j].ReportDate {
+			return output.Events[i].ReportDate < output.Events[j].ReportDate
+		}
+		return output.Events[i].Ticker < output.Events[j].Ticker
+	})
+
+	if err := prepareOutputDir(ctx, input.OutputDir); err != nil {
+		return nil, err
+	}
+
+	name := input.Name
+	if name == "" && len(tickers) == 1 {
+		name = fmt.Sprintf("%s_%s", tickers[0], EarningsCalendarFileSuffix)
+	} else if name == "" {
+		name = DefaultEarningsCalendarName
+	}
+
+	icsFileName := filepath.Join(input.OutputDir, name+".ics")
+	input.OnProgress.report("", StageWrite, fmt.Sprintf("writing %d events to %s", len(output.Events), icsFileName))
+	icsAbsPath, icsLogMessage, err := writeTextFile(icsFileName, func(w io.Writer) (string, error) {
+		return p.calendarWriter.WriteEarningsEventsToICS(output.Events, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := tickerFileName(input.OutputDir, name, "")
+	input.OnProgress.report("", StageWrite, fmt.Sprintf("writing %d events to %s", len(output.Events), fileName))
+	absPath, writeLogMessage, err := writeParquetFile(fileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteEarningsEventsToParquet(output.Events, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output.ICSFilePath = icsAbsPath
+	output.FilePath = absPath
+	output.Logs = append(output.Logs, icsLogMessage, writeLogMessage)
+
+	return output, nil
+}
+
+func (p *EarningsCalendarGenerator) tickerEvents(ctx context.Context, ticker string, asOf string, input EarningsCalendarInputs) ([]types.EarningsEventRecord, error) {
+	input.OnProgress.report(ticker, StageFetch, fmt.Sprintf("fetching earnings history and calendar for %s", ticker))
+	earningsJson, err := p.apiClient.FetchEarnings(ctx, ticker)
+	if err != nil {
+		return nil, fetchError("earnings", err)
+	}
+	calendarCsv, err := p.apiClient.FetchEarningsCalendar(ctx, ticker)
+	if err != nil {
+		return nil, fetchError("earnings calendar", err)
+	}
+
+	input.OnProgress.report(ticker, StageParse, "parsing API responses")
+	history, err := parse.ParseQuarterlyEarningsToFlat(earningsJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "quarterly earnings parsing failed: %w", err)
+	}
+	calendar, err := parse.ParseEarningsCalendarToFlat(calendarCsv, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "earnings calendar parsing failed: %w", err)
+	}
+
+	input.OnProgress.report(ticker, StageCalculate, "scheduling upcoming earnings")
+	return algos.ScheduleEarningsEvents(calendar, history, asOf), nil
+}
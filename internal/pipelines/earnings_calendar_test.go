@@ -0,0 +1,125 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/api"
+	"cibo/internal/types"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Serves a different earnings history and calendar per ticker, rejecting tickers it doesn't know
+// the way Alpha Vantage does.
+type perTickerCalendarClient struct {
+	mockAPIClient
+	earnings  map[string]string
+	calendars map[string]string
+}
+
+func (c *perTickerCalendarClient) FetchEarnings(ctx context.Context, ticker string) ([]byte, error) {
+	body, ok := c.earnings[ticker]
+	if !ok {
+		return nil, &api.APIMessageError{Kind: api.ErrInvalidCall, Message: "Invalid API call."}
+	}
+	return []byte(body), nil
+}
+
+func (c *perTickerCalendarClient) FetchEarningsCalendar(ctx context.Context, ticker string) ([]byte, error) {
+	return []byte(c.calendars[ticker]), nil
+}
+
+type mockCalendarWriter struct {
+	receivedEvents []types.EarningsEventRecord
+}
+
+func (m *mockCalendarWriter) WriteEarningsEventsToICS(records []types.EarningsEventRecord, writer io.Writer) (string, error) {
+	m.receivedEvents = records
+	return "mock ics write success log", nil
+}
+
+func newCalendarMockClient() *perTickerCalendarClient {
+	return &perTickerCalendarClient{
+		earnings: map[string]string{
+			"AAA": `{"symbol": "AAA", "quarterlyEarnings": [
+				{"fiscalDateEnding": "2025-06-30", "reportedDate": "2025-07-24", "reportTime": "post-market", "reportedEPS": "1.00"},
+				{"fiscalDateEnding": "2025-03-31", "reportedDate": "2025-04-24", "reportTime": "post-market", "reportedEPS": "1.00"},
+				{"fiscalDateEnding": "2024-12-31", "reportedDate": "2025-01-30", "reportTime": "pre-market", "reportedEPS": "1.00"}
+			]}`,
+			"BBB": `{"symbol": "BBB", "quarterlyEarnings": [
+				{"fiscalDateEnding": "2025-06-30", "reportedDate": "2025-08-07", "reportTime": "pre-market", "reportedEPS": "1.00"}
+			]}`,
+		},
+		calendars: map[string]string{
+			"AAA": "symbol,name,reportDate,fiscalDateEnding,estimate,currency\n" +
+				"AAA,Alpha Corp,2026-01-29,2025-12-31,,USD\n" +
+				"AAA,Alpha Corp,2025-10-23,2025-09-30,1.25,USD\n",
+			// Nothing scheduled yet, just the header
+			"BBB": "symbol,name,reportDate,fiscalDateEnding,estimate,currency\n",
+		},
+	}
+}
+
+// Given one ticker with scheduled reports, one without and one unknown, verify the calendar holds
+// the scheduled and estimated events in date order and the unknown ticker is reported as failed.
+func TestEarningsCalendarGenerator_RunPipeline_Success(t *testing.T) {
+	mockWriter := &mockParquetWriter{}
+	calendarWriter := &mockCalendarWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewEarningsCalendarGenerator(newCalendarMockClient(), mockWriter, calendarWriter)
+	output, err := pipeline.RunPipeline(context.Background(), EarningsCalendarInputs{
+		Tickers:   []string{"aaa", "BBB", "BADX"},
+		OutputDir: outputDir,
+		AsOf:      "2025-10-01",
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	expectedEvents := []types.EarningsEventRecord{
+		{Ticker: "AAA", CompanyName: "Alpha Corp", ReportDate: "2025-10-23", FiscalDateEnding: "2025-09-30",
+			ReportTime: "post-market", EstimatedEPS: 1.25, Source: types.EarningsSourceCalendar},
+		{Ticker: "BBB", ReportDate: "2025-11-06", FiscalDateEnding: "2025-09-30",
+			ReportTime: "pre-market", Source: types.EarningsSourceEstimated},
+		{Ticker: "AAA", CompanyName: "Alpha Corp", ReportDate: "2026-01-29", FiscalDateEnding: "2025-12-31",
+			ReportTime: "post-market", Source: types.EarningsSourceCalendar},
+	}
+	if diff := cmp.Diff(expectedEvents, output.Events); diff != "" {
+		t.Errorf("RunPipeline() mismatch in Events (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedEvents, calendarWriter.receivedEvents); diff != "" {
+		t.Errorf("Events written to the calendar mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedEvents, mockWriter.receivedEvents); diff != "" {
+		t.Errorf("Events written to parquet mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(output.Failures) != 1 || output.Failures[0].Ticker != "BADX" || !errors.Is(output.Failures[0].Err, ErrUnknownTicker) {
+		t.Errorf("Expected BADX to fail as an unknown ticker, got %+v", output.Failures)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "earnings_calendar.ics"), output.ICSFilePath); diff != "" {
+		t.Errorf("RunPipeline() ICSFilePath mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "earnings_calendar.parquet"), output.FilePath); diff != "" {
+		t.Errorf("RunPipeline() FilePath mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given only tickers that fail, verify the run returns the failure and writes no calendar.
+func TestEarningsCalendarGenerator_RunPipeline_AllFailed(t *testing.T) {
+	calendarWriter := &mockCalendarWriter{}
+
+	pipeline := NewEarningsCalendarGenerator(newCalendarMockClient(), &mockParquetWriter{}, calendarWriter)
+	_, err := pipeline.RunPipeline(context.Background(), EarningsCalendarInputs{Tickers: []string{"BADX"}, OutputDir: t.TempDir()})
+
+	if !errors.Is(err, ErrUnknownTicker) {
+		t.Errorf("Expected an unknown ticker error, but got: %v", err)
+	}
+	if calendarWriter.receivedEvents != nil {
+		t.Error("No calendar should be written when every ticker fails")
+	}
+}
@@ -0,0 +1,106 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/api"
+	"cibo/internal/statistics/parse"
+	"cibo/internal/statistics/utils"
+	"errors"
+	"fmt"
+)
+
+/*
+Pipelines run in a fixed set of stages (validate -> fetch -> parse -> calculate -> write). Every
+error returned from a pipeline is wrapped in a StageError so callers like the headless CLI can tell
+which class of failure happened without string matching on error messages.
+
+On top of the stage, the cause is kept in the error chain so callers can branch on it with
+errors.Is, e.g. to suggest a different date range instead of just printing the error.
+*/
+
+// Failure causes a caller can act on. These are the sentinels from the packages that detect them,
+// gathered here so UI layers only need to import pipelines.
+var (
+	ErrRateLimited           = api.ErrRateLimited
+	ErrQuotaExhausted        = api.ErrQuotaExhausted
+	ErrPremiumEndpoint       = api.ErrPremiumEndpoint
+	ErrUnknownTicker         = parse.ErrUnknownTicker
+	ErrInsufficientEarnings  = algos.ErrInsufficientEarnings
+	ErrNegativeEarnings      = algos.ErrNegativeEarnings
+	ErrInsufficientRevenue   = algos.ErrInsufficientRevenue
+	ErrInsufficientCashFlow  = algos.ErrInsufficientCashFlow
+	ErrInvalidDateRange      = utils.ErrInvalidDateRange
+	ErrInvalidModelParams    = algos.ErrInvalidModelParams
+	ErrInvalidDCFParams      = algos.ErrInvalidDCFParams
+	ErrInvalidValuationBands = algos.ErrInvalidValuationBands
+)
+
+type Stage string
+
+const (
+	StageValidate  Stage = "validate"
+	StageFetch     Stage = "fetch"
+	StageParse     Stage = "parse"
+	StageCalculate Stage = "calculate"
+	StageWrite     Stage = "write"
+)
+
+type StageError struct {
+	Stage Stage
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Wraps an error with the pipeline stage it happened in, keeping the original message.
+func stageErrorf(stage Stage, format string, args ...any) error {
+	return &StageError{Stage: stage, Err: fmt.Errorf(format, args...)}
+}
+
+// Wraps a fetch failure, marking API rejections as an unknown ticker since that's by far the most
+// common reason Alpha Vantage rejects an otherwise well formed call.
+func fetchError(what string, err error) error {
+	if errors.Is(err, api.ErrInvalidCall) {
+		return stageErrorf(StageFetch, "%s API fetch failed: %w: %w", what, ErrUnknownTicker, err)
+	}
+	return stageErrorf(StageFetch, "%s API fetch failed: %w", what, err)
+}
+
+// A short, actionable suggestion for a pipeline error, or empty if there's nothing more useful
+// to say than the error itself.
+func Guidance(err error) string {
+	switch {
+	case errors.Is(err, ErrQuotaExhausted):
+		return "The daily Alpha Vantage budget is spent. Try again after the reset, or re-run tickers that are already cached."
+	case errors.Is(err, ErrRateLimited):
+		return "Alpha Vantage is throttling requests. Wait a minute and try again, or lower -callsPerMinute."
+	case errors.Is(err, ErrPremiumEndpoint):
+		return "This data needs a premium Alpha Vantage key."
+	case errors.Is(err, ErrUnknownTicker):
+		return "Check the ticker symbol is spelled correctly and is listed on a US exchange."
+	case errors.Is(err, ErrInvalidDateRange):
+		return "Dates must be YYYY-MM-DD with the start date before the end date."
+	case errors.Is(err, ErrInvalidModelParams):
+		return "The CAGR window and P/E values must be positive, with the minimum P/E below the maximum, and growth measured by endpoints or regression. Leave them empty for the defaults."
+	case errors.Is(err, ErrInvalidDCFParams):
+		return "The discount rate and horizon must be positive, with terminal growth below the discount rate. Leave them empty for the defaults."
+	case errors.Is(err, ErrInvalidValuationBands):
+		return "The overvalued band must be positive and the undervalued band between 0 and 100, both as a percentage premium over the fair value. Leave them empty for the defaults."
+	case errors.Is(err, ErrInsufficientEarnings):
+		return "There isn't enough annual earnings history in this range. Try an earlier start date or leave it empty."
+	case errors.Is(err, ErrInsufficientRevenue):
+		return "There aren't enough years of revenue and share data in this range. Try an earlier start date or a longer window."
+	case errors.Is(err, ErrInsufficientCashFlow):
+		return "DCF needs at least one year of positive free cash flow in this range. Try an earlier start date, or a different valuation model for cash burning companies."
+	case errors.Is(err, ErrNegativeEarnings):
+		return "The Lynch method needs positive earnings. Try a range where the company was profitable, classifying its earnings so a cyclical is valued on its cycle average, or a different valuation model."
+	default:
+		return ""
+	}
+}
@@ -2,6 +2,7 @@ package pipelines
 
 import (
 	"cibo/internal/types"
+	"context"
 	"io"
 )
 
@@ -26,15 +27,51 @@ import (
 */
 
 type APIClient interface {
-	FetchDailyPrice(ticker string) ([]byte, error)
-	FetchEarnings(ticker string) ([]byte, error)
-	FetchStockSplits(ticker string) ([]byte, error)
+	FetchDailyPrice(ctx context.Context, ticker string) ([]byte, error)
+	FetchEarnings(ctx context.Context, ticker string) ([]byte, error)
+	FetchStockSplits(ctx context.Context, ticker string) ([]byte, error)
+	FetchIncomeStatement(ctx context.Context, ticker string) ([]byte, error)
+	FetchBalanceSheet(ctx context.Context, ticker string) ([]byte, error)
+	FetchCashFlow(ctx context.Context, ticker string) ([]byte, error)
+	FetchDividends(ctx context.Context, ticker string) ([]byte, error)
+	FetchEarningsCalendar(ctx context.Context, ticker string) ([]byte, error)
+	FetchEarningsEstimates(ctx context.Context, ticker string) ([]byte, error)
 }
 
 type ParquetWriter interface {
 	WriteCombinedPriceDataToParquet(records []types.CombinedPriceRecord, writer io.WriteCloser) (string, error)
+	WriteDailyStockDataToParquet(records []types.DailyStockRecord, writer io.WriteCloser) (string, error)
+	WriteDividendsToParquet(records []types.DividendRecord, writer io.WriteCloser) (string, error)
+	WriteEarningsEventsToParquet(records []types.EarningsEventRecord, writer io.WriteCloser) (string, error)
+	WriteMetadataToParquet(records []types.MetadataRecord, writer io.WriteCloser) (string, error)
+	WriteDCFSensitivityToParquet(records []types.DCFSensitivityRecord, writer io.WriteCloser) (string, error)
+	WriteValuationSignalsToParquet(records []types.ValuationSignalRecord, writer io.WriteCloser) (string, error)
+}
+
+type CalendarWriter interface {
+	WriteEarningsEventsToICS(records []types.EarningsEventRecord, writer io.Writer) (string, error)
 }
 
 type FairValuePipeline interface {
-	RunPipeline(input LynchFairValueInputs) (*LynchFairValueOutputs, error)
+	RunPipeline(ctx context.Context, input LynchFairValueInputs) (*LynchFairValueOutputs, error)
+}
+
+type PriceToSalesPipeline interface {
+	RunPipeline(ctx context.Context, input PriceToSalesFairValueInputs) (*PriceToSalesFairValueOutputs, error)
+}
+
+type DCFPipeline interface {
+	RunPipeline(ctx context.Context, input DCFFairValueInputs) (*DCFFairValueOutputs, error)
+}
+
+type DividendPipeline interface {
+	RunPipeline(ctx context.Context, input DividendHistoryInputs) (*DividendHistoryOutputs, error)
+}
+
+type EarningsCalendarPipeline interface {
+	RunPipeline(ctx context.Context, input EarningsCalendarInputs) (*EarningsCalendarOutputs, error)
+}
+
+type BatchFairValuePipeline interface {
+	RunBatch(ctx context.Context, input LynchBatchInputs) (*LynchBatchOutputs, error)
 }
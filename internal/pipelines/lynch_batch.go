@@ -0,0 +1,221 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LynchBatchRunner fans a list of tickers out over a bounded pool of goroutines, each running the
+// single ticker Lynch pipeline. Failures are collected per ticker instead of stopping the batch so
+// one bad symbol in a watchlist doesn't throw away the rest of the run.
+
+const (
+	DefaultBatchWorkers = 4
+	BatchSummaryFile    = "batch_summary.json"
+)
+
+type LynchBatchRunner struct {
+	pipeline FairValuePipeline
+}
+
+type LynchBatchInputs struct {
+	Tickers []string
+	// Applied to every ticker. Its Ticker and OnProgress are set per ticker, anything set in them
+	// here is ignored.
+	LynchFairValueInputs
+	// Number of tickers processed at the same time. Zero or less uses DefaultBatchWorkers.
+	Workers int
+	// Optional progress hook, called from worker goroutines so it must be safe for concurrent use.
+	OnProgress TickerProgressFunc
+}
+
+type LynchBatchTickerResult struct {
+	Ticker    string `json:"ticker"`
+	Success   bool   `json:"success"`
+	FilePath  string `json:"file_path,omitempty"`
+	OHLCVPath string `json:"ohlcv_file_path,omitempty"`
+	// Only set when IncludeSignals was requested.
+	SignalsPath string `json:"signals_file_path,omitempty"`
+	// The run metadata, as JSON and as a key/value parquet table.
+	MetadataPath        string `json:"metadata_file_path,omitempty"`
+	MetadataParquetPath string `json:"metadata_parquet_file_path,omitempty"`
+	RecordCount         int    `json:"record_count,omitempty"`
+	// Only set on success.
+	Model       *LynchModelSummary `json:"model,omitempty"`
+	FailedStage Stage              `json:"failed_stage,omitempty"`
+	// Set when the batch was cancelled before the ticker started, so it neither succeeded nor failed.
+	// Error holds the cancellation.
+	Skipped     bool    `json:"skipped,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Guidance    string  `json:"guidance,omitempty"`
+	DurationSec float64 `json:"duration_sec"`
+}
+
+type LynchBatchOutputs struct {
+	StartedAt   time.Time                `json:"started_at"`
+	FinishedAt  time.Time                `json:"finished_at"`
+	StartDate   string                   `json:"start_date,omitempty"`
+	EndDate     string                   `json:"end_date,omitempty"`
+	Workers     int                      `json:"workers"`
+	Succeeded   int                      `json:"succeeded"`
+	Failed      int                      `json:"failed"`
+	Skipped     int                      `json:"skipped"`
+	Results     []LynchBatchTickerResult `json:"results"`
+	SummaryPath string                   `json:"-"`
+}
+
+func NewLynchBatchRunner(pipeline FairValuePipeline) *LynchBatchRunner {
+	return &LynchBatchRunner{pipeline: pipeline}
+}
+
+func (r *LynchBatchRunner) RunBatch(ctx context.Context, input LynchBatchInputs) (*LynchBatchOutputs, error) {
+	tickers := NormalizeTickers(input.Tickers)
+	if len(tickers) == 0 {
+		return nil, errors.New("no tickers provided for batch run")
+	}
+
+	workers := input.Workers
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	workers = min(workers, len(tickers))
+
+	output := &LynchBatchOutputs{
+		StartedAt: time.Now().UTC(),
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		Workers:   workers,
+		// Results are written by index so the summary keeps the order the tickers were given in.
+		Results: make([]LynchBatchTickerResult, len(tickers)),
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				output.Results[i] = r.runTicker(ctx, tickers[i], input)
+			}
+		}()
+	}
+
+	// Once the batch is cancelled the remaining tickers are still fed through so they get recorded
+	// as skipped with the context error, but no new pipeline runs are started for them.
+	for i := range tickers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	output.FinishedAt = time.Now().UTC()
+	for _, result := range output.Results {
+		switch {
+		case result.Success:
+			output.Succeeded++
+		case result.Skipped:
+			output.Skipped++
+		default:
+			output.Failed++
+		}
+	}
+
+	summaryPath, err := writeBatchSummary(output, input.OutputDir)
+	if err != nil {
+		return output, &StageError{Stage: StageWrite, Err: err}
+	}
+	output.SummaryPath = summaryPath
+
+	// The summary is still written for a cancelled batch, but callers need to know it was cut short.
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	return output, nil
+}
+
+func (r *LynchBatchRunner) runTicker(ctx context.Context, ticker string, batchInput LynchBatchInputs) LynchBatchTickerResult {
+	started := time.Now()
+	if err := ctx.Err(); err != nil {
+		return LynchBatchTickerResult{Ticker: ticker, Skipped: true, Error: err.Error()}
+	}
+
+	input := batchInput.LynchFairValueInputs
+	input.Ticker = ticker
+	input.OnProgress = nil
+	if batchInput.OnProgress != nil {
+		input.OnProgress = func(stage Stage, message string) {
+			batchInput.OnProgress(ticker, stage, message)
+		}
+	}
+
+	result := LynchBatchTickerResult{Ticker: ticker}
+	output, err := r.pipeline.RunPipeline(ctx, input)
+	result.DurationSec = time.Since(started).Seconds()
+	if err != nil {
+		result.Error = err.Error()
+		result.Guidance = Guidance(err)
+		var stageErr *StageError
+		if errors.As(err, &stageErr) {
+			result.FailedStage = stageErr.Stage
+		}
+		return result
+	}
+
+	result.Success = true
+	result.FilePath = output.FilePath
+	result.Model = &output.Model
+	result.OHLCVPath = output.OHLCVFilePath
+	result.SignalsPath = output.SignalsFilePath
+	result.MetadataPath = output.MetadataFilePath
+	result.MetadataParquetPath = output.MetadataParquetFilePath
+	result.RecordCount = output.RecordCount
+	return result
+}
+
+func writeBatchSummary(output *LynchBatchOutputs, outputDir string) (string, error) {
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create output directory '%s': %w", outputDir, err)
+		}
+	}
+
+	summaryJson, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode batch summary: %w", err)
+	}
+
+	fileName := filepath.Join(outputDir, BatchSummaryFile)
+	if err := os.WriteFile(fileName, summaryJson, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write batch summary '%s': %w", fileName, err)
+	}
+
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for '%s': %w", fileName, err)
+	}
+	return absPath, nil
+}
+
+// Upper cases, trims and de-duplicates a ticker list while keeping the original order.
+func NormalizeTickers(tickers []string) []string {
+	seen := make(map[string]bool, len(tickers))
+	normalized := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker == "" || seen[ticker] {
+			continue
+		}
+		seen[ticker] = true
+		normalized = append(normalized, ticker)
+	}
+	return normalized
+}
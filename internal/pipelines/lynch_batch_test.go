@@ -0,0 +1,238 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// --- Mock Implementations ---
+type mockBatchPipeline struct {
+	failingTickers map[string]bool
+	delay          time.Duration
+	mu             sync.Mutex
+	calledTickers  []string
+	calledInputs   []LynchFairValueInputs
+	running        atomic.Int32
+	maxRunning     atomic.Int32
+}
+
+func (m *mockBatchPipeline) RunPipeline(ctx context.Context, input LynchFairValueInputs) (*LynchFairValueOutputs, error) {
+	running := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		current := m.maxRunning.Load()
+		if running <= current || m.maxRunning.CompareAndSwap(current, running) {
+			break
+		}
+	}
+
+	m.mu.Lock()
+	m.calledTickers = append(m.calledTickers, input.Ticker)
+	m.calledInputs = append(m.calledInputs, input)
+	m.mu.Unlock()
+
+	time.Sleep(m.delay)
+
+	if m.failingTickers[input.Ticker] {
+		return nil, &StageError{Stage: StageFetch, Err: errors.New("mock fetch error")}
+	}
+	return &LynchFairValueOutputs{
+		RecordCount:             10,
+		FilePath:                filepath.Join(input.OutputDir, input.Ticker+".parquet"),
+		Model:                   LynchModelSummary{FairValuePE: 20},
+		MetadataFilePath:        filepath.Join(input.OutputDir, input.Ticker+"_metadata.json"),
+		MetadataParquetFilePath: filepath.Join(input.OutputDir, input.Ticker+"_metadata.parquet"),
+	}, nil
+}
+
+// Given a mix of good and bad tickers, verify that every ticker is run, failures are collected
+// per ticker with their stage, and results keep the input order.
+func TestLynchBatchRunner_RunBatch_MixedResults(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{failingTickers: map[string]bool{"BAD": true}}
+	outputDir := t.TempDir()
+
+	runner := NewLynchBatchRunner(mockPipeline)
+	output, err := runner.RunBatch(context.Background(), LynchBatchInputs{
+		Tickers:              []string{"AAPL", "BAD", "MSFT"},
+		LynchFairValueInputs: LynchFairValueInputs{OutputDir: outputDir},
+		Workers:              2,
+	})
+	if err != nil {
+		t.Fatalf("RunBatch() returned an unexpected error: %v", err)
+	}
+
+	success := func(ticker string) LynchBatchTickerResult {
+		return LynchBatchTickerResult{
+			Ticker:              ticker,
+			Success:             true,
+			FilePath:            filepath.Join(outputDir, ticker+".parquet"),
+			MetadataPath:        filepath.Join(outputDir, ticker+"_metadata.json"),
+			MetadataParquetPath: filepath.Join(outputDir, ticker+"_metadata.parquet"),
+			RecordCount:         10,
+			Model:               &LynchModelSummary{FairValuePE: 20},
+		}
+	}
+	expectedResults := []LynchBatchTickerResult{
+		success("AAPL"),
+		{Ticker: "BAD", Success: false, FailedStage: StageFetch, Error: "mock fetch error"},
+		success("MSFT"),
+	}
+	ignoreDuration := cmpopts.IgnoreFields(LynchBatchTickerResult{}, "DurationSec")
+	if diff := cmp.Diff(expectedResults, output.Results, ignoreDuration); diff != "" {
+		t.Errorf("RunBatch() results mismatch (-want +got):\n%s", diff)
+	}
+	if output.Succeeded != 2 || output.Failed != 1 {
+		t.Errorf("Expected 2 succeeded and 1 failed, got %d and %d", output.Succeeded, output.Failed)
+	}
+}
+
+// Given a batch with pipeline options set, verify every ticker runs with them under its own ticker.
+func TestLynchBatchRunner_RunBatch_AppliesTemplate(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{}
+	runner := NewLynchBatchRunner(mockPipeline)
+
+	template := LynchFairValueInputs{
+		Ticker:         "IGNORED",
+		StartDate:      "2020-01-01",
+		OutputDir:      t.TempDir(),
+		DailyFairValue: "ttm",
+		IncludeSignals: true,
+		SignalBands:    ValuationBands{OvervaluedPct: 30},
+		Model:          LynchModelParams{CAGRYears: 5},
+	}
+	_, err := runner.RunBatch(context.Background(), LynchBatchInputs{
+		Tickers:              []string{"AAPL", "MSFT"},
+		LynchFairValueInputs: template,
+		Workers:              1,
+	})
+	if err != nil {
+		t.Fatalf("RunBatch() returned an unexpected error: %v", err)
+	}
+
+	for _, input := range mockPipeline.calledInputs {
+		expected := template
+		expected.Ticker = input.Ticker
+		if input.Ticker == "IGNORED" {
+			t.Errorf("Expected the template's ticker to be replaced, got %s", input.Ticker)
+		}
+		if diff := cmp.Diff(expected, input, cmpopts.IgnoreFields(LynchFairValueInputs{}, "OnProgress")); diff != "" {
+			t.Errorf("RunBatch() inputs for %s mismatch (-want +got):\n%s", input.Ticker, diff)
+		}
+	}
+	if len(mockPipeline.calledInputs) != 2 {
+		t.Errorf("Expected 2 pipeline runs, got %d", len(mockPipeline.calledInputs))
+	}
+}
+
+// Given a successful batch, verify that a summary report is written alongside the ticker files
+// and that it can be read back.
+func TestLynchBatchRunner_RunBatch_WritesSummary(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{}
+	outputDir := t.TempDir()
+
+	runner := NewLynchBatchRunner(mockPipeline)
+	output, err := runner.RunBatch(context.Background(), LynchBatchInputs{Tickers: []string{"AAPL"}, LynchFairValueInputs: LynchFairValueInputs{OutputDir: outputDir}})
+	if err != nil {
+		t.Fatalf("RunBatch() returned an unexpected error: %v", err)
+	}
+
+	expectedPath := filepath.Join(outputDir, BatchSummaryFile)
+	if output.SummaryPath != expectedPath {
+		t.Errorf("Expected summary path '%s', got '%s'", expectedPath, output.SummaryPath)
+	}
+
+	summaryJson, err := os.ReadFile(expectedPath)
+	if err != nil {
+		t.Fatalf("Failed to read summary file: %v", err)
+	}
+	var summary LynchBatchOutputs
+	if err := json.Unmarshal(summaryJson, &summary); err != nil {
+		t.Fatalf("Failed to decode summary file: %v", err)
+	}
+	if summary.Succeeded != 1 || len(summary.Results) != 1 || summary.Results[0].Ticker != "AAPL" {
+		t.Errorf("Unexpected summary contents: %+v", summary)
+	}
+}
+
+// Given more tickers than workers, verify that no more than the configured number of
+// pipelines run at the same time.
+func TestLynchBatchRunner_RunBatch_BoundedWorkers(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{delay: 10 * time.Millisecond}
+
+	runner := NewLynchBatchRunner(mockPipeline)
+	_, err := runner.RunBatch(context.Background(), LynchBatchInputs{
+		Tickers:              []string{"A", "B", "C", "D", "E", "F", "G", "H"},
+		LynchFairValueInputs: LynchFairValueInputs{OutputDir: t.TempDir()},
+		Workers:              3,
+	})
+	if err != nil {
+		t.Fatalf("RunBatch() returned an unexpected error: %v", err)
+	}
+
+	if got := mockPipeline.maxRunning.Load(); got > 3 {
+		t.Errorf("Expected at most 3 concurrent pipelines, got %d", got)
+	}
+	if len(mockPipeline.calledTickers) != 8 {
+		t.Errorf("Expected 8 pipeline runs, got %d", len(mockPipeline.calledTickers))
+	}
+}
+
+// Given a batch whose context is already cancelled, verify that no pipelines run, every ticker is
+// recorded as skipped rather than failed and the cancellation is returned to the caller.
+func TestLynchBatchRunner_RunBatch_Cancelled(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := NewLynchBatchRunner(mockPipeline)
+	output, err := runner.RunBatch(ctx, LynchBatchInputs{Tickers: []string{"AAPL", "MSFT"}, LynchFairValueInputs: LynchFairValueInputs{OutputDir: t.TempDir()}})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a context cancelled error, but got: %v", err)
+	}
+	if output.Skipped != 2 || output.Failed != 0 {
+		t.Errorf("Expected both tickers to be recorded as skipped, got %d skipped and %d failed", output.Skipped, output.Failed)
+	}
+	for _, result := range output.Results {
+		if !result.Skipped || result.FailedStage != "" || result.Error != context.Canceled.Error() {
+			t.Errorf("Expected %s to be skipped with the cancellation and no failed stage, got %+v", result.Ticker, result)
+		}
+	}
+	if len(mockPipeline.calledTickers) != 0 {
+		t.Errorf("Expected no pipeline runs after cancellation, got %d", len(mockPipeline.calledTickers))
+	}
+}
+
+// Given an empty ticker list, verify that an error is returned and nothing runs.
+func TestLynchBatchRunner_RunBatch_NoTickers(t *testing.T) {
+	mockPipeline := &mockBatchPipeline{}
+
+	runner := NewLynchBatchRunner(mockPipeline)
+	_, err := runner.RunBatch(context.Background(), LynchBatchInputs{Tickers: []string{" ", ""}})
+	if err == nil {
+		t.Fatal("RunBatch() expected an error for an empty ticker list, but got none")
+	}
+	if len(mockPipeline.calledTickers) != 0 {
+		t.Errorf("Expected no pipeline runs, got %d", len(mockPipeline.calledTickers))
+	}
+}
+
+// Given messy user input, verify tickers are trimmed, upper cased and de-duplicated in order.
+func TestNormalizeTickers(t *testing.T) {
+	input := []string{" aapl", "MSFT", "", "AAPL ", "nvo"}
+	expected := []string{"AAPL", "MSFT", "NVO"}
+
+	if diff := cmp.Diff(expected, NormalizeTickers(input)); diff != "" {
+		t.Errorf("NormalizeTickers() mismatch (-want +got):\n%s", diff)
+	}
+}
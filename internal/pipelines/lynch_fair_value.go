@@ -5,10 +5,15 @@ import (
 	"cibo/internal/statistics/parse"
 	"cibo/internal/statistics/utils"
 	"cibo/internal/types"
+	"cibo/internal/version"
+	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
-
-	"github.com/xitongsys/parquet-go-source/local"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
 )
 
 // LynchFairValuePipeline orchestrates the business logic for generating fair value reports via the Lynch method
@@ -18,17 +23,81 @@ import (
 type LynchFairValuePipeline struct {
 	apiClient     APIClient
 	parquetWriter ParquetWriter
+	now           func() time.Time
 }
 
 type LynchFairValueInputs struct {
 	Ticker    string
 	StartDate string
 	EndDate   string
+	// Directory the parquet file is written to. Empty means the current working directory.
+	OutputDir string
+	// Also write the split adjusted open/high/low/close/volume history to <TICKER>_ohlcv.parquet.
+	IncludeOHLCV bool
+	// Optional CPI series CSV (FRED format). When set, Shiller CAPE and the CAPE implied fair value
+	// are added to the combined output as the cape_ratio and cape_fair_value series.
+	CPIFilePath string
+	// Build the fair value curve from trailing twelve month EPS, so it moves every quarter instead
+	// of once a year. The TTM EPS is also added to the combined output as the ttm_eps series.
+	UseTTMEPS bool
+	// Carry the fair value line forward onto analyst EPS estimates for the current and next fiscal
+	// year, as the fair_value_projected series with _high and _low bands. Costs one more API call.
+	ProjectFromEstimates bool
+	// Add the P/E the market paid each day, on the EPS known that day, as the pe_ratio series along
+	// with its mean, median and percentile bands as the pe_ratio_* series.
+	IncludePERatio bool
+	// Add the daily PEG and dividend adjusted PEGY, the P/E over the trailing EPS CAGR known that day,
+	// as the peg_ratio and pegy_ratio series. Costs one more API call for the dividends.
+	IncludePEG bool
+	// Add Benjamin Graham's fair values next to the Lynch one, as the graham_number and graham_value
+	// series. Costs one more API call for the balance sheet.
+	IncludeGraham bool
+	// Current AAA corporate bond yield in percent for the Graham intrinsic value. Zero uses Graham's
+	// own 4.4%, which leaves the value unadjusted for rates.
+	AAABondYield float64
+	// Carry the fair value onto every trading day as the fair_value_daily series, by
+	// algos.DailyFairValueStep, DailyFairValueLinear or DailyFairValueTTM, with the close's premium
+	// over it as the premium_pct series. Empty leaves them out.
+	DailyFairValue string
+	// Compare each close to the daily fair value, by DailyFairValue or step when that's empty. The
+	// distribution of their ratio goes in the run metadata, and every crossing into one of
+	// SignalBands is written to <TICKER>_signals.parquet and added as the signal_overvalued and
+	// signal_undervalued series.
+	IncludeSignals bool
+	// Over and undervalued premiums in percent, zero uses algos.DefaultValuationBandPct.
+	SignalBands ValuationBands
+	// CAGR window and P/E overrides. The zero value runs the plain model.
+	Model LynchModelParams
+	// Optional, see ProgressFunc.
+	OnProgress ProgressFunc
 }
 
+const (
+	OHLCVFileSuffix   = "ohlcv"
+	SignalsFileSuffix = "signals"
+)
+
+// Re-exported so UI layers only need to import pipelines.
+type (
+	LynchModelParams  = algos.LynchModelParams
+	LynchModelSummary = algos.LynchModelSummary
+	ValuationBands    = algos.ValuationBands
+)
+
 type LynchFairValueOutputs struct {
-	RecordCount       int
-	FilePath          string
+	RecordCount int
+	FilePath    string
+	// Only set when IncludeOHLCV was requested.
+	OHLCVFilePath string
+	// Only set when IncludeSignals was requested.
+	SignalsFilePath string
+	Signals         []types.ValuationSignalRecord
+	// The run metadata sidecar, as JSON and as a key/value parquet table.
+	MetadataFilePath        string
+	MetadataParquetFilePath string
+	Metadata                RunMetadata
+	// The parameters the fair value was calculated with and the growth rate and P/E they gave.
+	Model             LynchModelSummary
 	CombinedPriceData []types.CombinedPriceRecord
 	Logs              []string
 }
@@ -37,77 +106,446 @@ func NewLynchFairValuePipeline(client APIClient, writer ParquetWriter) *LynchFai
 	return &LynchFairValuePipeline{
 		apiClient:     client,
 		parquetWriter: writer,
+		now:           time.Now,
 	}
 }
 
-func (p *LynchFairValuePipeline) RunPipeline(input LynchFairValueInputs) (*LynchFairValueOutputs, error) {
-	dailyPricesJson, err := p.apiClient.FetchDailyPrice(input.Ticker)
+func (p *LynchFairValuePipeline) RunPipeline(ctx context.Context, input LynchFairValueInputs) (*LynchFairValueOutputs, error) {
+	// Bad dates would only be caught after the API calls are spent, so check them up front.
+	if err := utils.ValidateDateRange(input.StartDate, input.EndDate); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+	if err := input.Model.Validate(); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+	if input.AAABondYield < 0 {
+		return nil, stageErrorf(StageValidate, "AAA bond yield can't be negative, got %.2f", input.AAABondYield)
+	}
+	if input.DailyFairValue != "" && !algos.ValidDailyFairValueMethod(input.DailyFairValue) {
+		return nil, stageErrorf(StageValidate, "daily fair value must be '%s', '%s' or '%s', got '%s'",
+			algos.DailyFairValueStep, algos.DailyFairValueLinear, algos.DailyFairValueTTM, input.DailyFairValue)
+	}
+	signalBands := input.SignalBands.Resolve()
+	if input.IncludeSignals {
+		if err := signalBands.Validate(); err != nil {
+			return nil, &StageError{Stage: StageValidate, Err: err}
+		}
+	}
+	// Likewise a missing or malformed CPI file.
+	var cpiRecords []types.CPIRecord
+	if input.CPIFilePath != "" {
+		cpiCsv, err := os.ReadFile(input.CPIFilePath)
+		if err != nil {
+			return nil, stageErrorf(StageValidate, "could not read CPI file: %w", err)
+		}
+		cpiRecords, err = parse.ParseCPISeriesCSV(cpiCsv)
+		if err != nil {
+			return nil, stageErrorf(StageValidate, "CPI file %s: %w", input.CPIFilePath, err)
+		}
+	}
+
+	input.OnProgress.report(StageFetch, fmt.Sprintf("fetching data for %s", input.Ticker))
+	dailyPricesJson, err := p.apiClient.FetchDailyPrice(ctx, input.Ticker)
 	if err != nil {
-		return nil, fmt.Errorf("daily prices API fetch failed: %w", err)
+		return nil, fetchError("daily prices", err)
 	}
-	annualEarningsJson, err := p.apiClient.FetchEarnings(input.Ticker)
+	annualEarningsJson, err := p.apiClient.FetchEarnings(ctx, input.Ticker)
 	if err != nil {
-		return nil, fmt.Errorf("annual earnings API fetch failed: %w", err)
+		return nil, fetchError("annual earnings", err)
 	}
-	stockSplitsJson, err := p.apiClient.FetchStockSplits((input.Ticker))
+	stockSplitsJson, err := p.apiClient.FetchStockSplits(ctx, input.Ticker)
 	if err != nil {
-		return nil, fmt.Errorf("stock splits API fetch failed: %w", err)
+		return nil, fetchError("stock splits", err)
+	}
+	var balanceSheetJson []byte
+	if input.IncludeGraham {
+		balanceSheetJson, err = p.apiClient.FetchBalanceSheet(ctx, input.Ticker)
+		if err != nil {
+			return nil, fetchError("balance sheet", err)
+		}
+	}
+	var dividendsJson []byte
+	if input.IncludePEG {
+		dividendsJson, err = p.apiClient.FetchDividends(ctx, input.Ticker)
+		if err != nil {
+			return nil, fetchError("dividends", err)
+		}
+	}
+	var estimatesJson []byte
+	if input.ProjectFromEstimates {
+		estimatesJson, err = p.apiClient.FetchEarningsEstimates(ctx, input.Ticker)
+		if err != nil {
+			return nil, fetchError("earnings estimates", err)
+		}
 	}
 
-	dailyPricesRecords, err := parse.ParseDailyPricesToFlat(dailyPricesJson, true)
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageParse, Err: err}
+	}
+	input.OnProgress.report(StageParse, "parsing API responses")
+	dailyPricesRecords, skippedDailyPrices, err := parse.ParseDailyPricesToFlatWithSkips(dailyPricesJson, true)
 	if err != nil {
-		return nil, fmt.Errorf("daily prices parsing failed: %w", err)
+		return nil, stageErrorf(StageParse, "daily prices parsing failed: %w", err)
 	}
-	annualEarningsRecords, err := parse.ParseAnnualEarningsToFlat(annualEarningsJson, true)
+	annualEarningsRecords, skippedAnnualEarnings, err := parse.ParseAnnualEarningsToFlatWithSkips(annualEarningsJson, true)
 	if err != nil {
-		return nil, fmt.Errorf("annual earnings parsing failed: %w", err)
+		return nil, stageErrorf(StageParse, "annual earnings parsing failed: %w", err)
 	}
 	stockSplitRecords, err := parse.ParseStockSplitsToFlat(stockSplitsJson)
 	if err != nil {
-		return nil, fmt.Errorf("stock splits parsing failed: %w", err)
+		return nil, stageErrorf(StageParse, "stock splits parsing failed: %w", err)
+	}
+	var estimateRecords []types.EarningsEstimateRecord
+	if estimatesJson != nil {
+		estimateRecords, err = parse.ParseEarningsEstimatesToFlat(estimatesJson, true)
+		if err != nil {
+			return nil, stageErrorf(StageParse, "earnings estimates parsing failed: %w", err)
+		}
+	}
+	var bookValueRecords []types.BookValuePerShareRecord
+	if balanceSheetJson != nil {
+		bookValueRecords, err = parse.ParseBookValuePerShareToFlat(balanceSheetJson, true)
+		if err != nil {
+			return nil, stageErrorf(StageParse, "balance sheet parsing failed: %w", err)
+		}
+	}
+	var dividendRecords []types.DividendRecord
+	if dividendsJson != nil {
+		dividendRecords, err = parse.ParseDividendsToFlat(dividendsJson, true)
+		if err != nil {
+			return nil, stageErrorf(StageParse, "dividends parsing failed: %w", err)
+		}
+	}
+	// Quarterly earnings come in the same response as the annual ones.
+	quarterlyEarningsRecords, skippedQuarterlyEarnings, err := parse.ParseQuarterlyEarningsToFlatWithSkips(annualEarningsJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "quarterly earnings parsing failed: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageCalculate, Err: err}
+	}
+	input.OnProgress.report(StageCalculate, "calculating fair value history")
 	adjustedDailyPrices := utils.AdjustForStockSplits(dailyPricesRecords, stockSplitRecords)
 	filteredDailyPrices, err := utils.FilterDailyPricesWithinDateRange(adjustedDailyPrices, input.StartDate, input.EndDate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to filter daily prices: %w", err)
+		return nil, stageErrorf(StageCalculate, "failed to filter daily prices: %w", err)
 	}
 
-	filteredAnnualEarnings, err := utils.FilterAnnualEarningsWithinDateRange(annualEarningsRecords, input.StartDate, input.EndDate)
+	fairValueEarnings := annualEarningsRecords
+	if input.UseTTMEPS {
+		fairValueEarnings = algos.TrailingTwelveMonthEPS(quarterlyEarningsRecords)
+	}
+	filteredEarnings, err := utils.FilterAnnualEarningsWithinDateRange(fairValueEarnings, input.StartDate, input.EndDate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to filter annual earnings: %w", err)
+		return nil, stageErrorf(StageCalculate, "failed to filter earnings: %w", err)
 	}
 
-	fairValuePriceRecords, err := algos.CalculateFairValueHistory(filteredAnnualEarnings)
+	// modelEarnings are filteredEarnings normalized the way the model valued them, for cyclicals.
+	fairValuePriceRecords, modelEarnings, model, err := algos.CalculateFairValueHistory(filteredEarnings, input.Model)
 	if err != nil {
-		return nil, fmt.Errorf("could not calculate fair value: %w", err)
+		return nil, stageErrorf(StageCalculate, "could not calculate fair value: %w", err)
+	}
+
+	var logs []string
+	if model.EarningsProfile != nil {
+		logs = append(logs, fmt.Sprintf("%s classified as %s", input.Ticker, model.EarningsProfile))
+	}
+	if len(model.DroppedYears) > 0 {
+		logs = append(logs, fmt.Sprintf("No fair value for %s on %d fiscal dates without positive EPS: %s",
+			input.Ticker, len(model.DroppedYears), strings.Join(model.DroppedYears, ", ")))
 	}
 
 	combinedData := types.DailyAndFairPriceToCombined(filteredDailyPrices, fairValuePriceRecords)
+	if input.UseTTMEPS {
+		combinedData = append(combinedData, types.TTMEPSToCombined(filteredEarnings)...)
+	}
 
-	fileName := fmt.Sprintf("%s.parquet", input.Ticker)
-	fw, err := local.NewLocalFileWriter(fileName)
+	// Beat/miss markers for the reports inside the range.
+	surprises, err := utils.FilterEarningsSurprisesWithinDateRange(algos.ClassifyEarningsSurprises(quarterlyEarningsRecords), input.StartDate, input.EndDate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file '%s': %w", fileName, err)
+		return nil, stageErrorf(StageCalculate, "failed to filter earnings surprises: %w", err)
 	}
-	defer fw.Close()
+	combinedData = append(combinedData, types.EarningsSurprisesToCombined(surprises)...)
 
-	writeLogMessage, err := p.parquetWriter.WriteCombinedPriceDataToParquet(combinedData, fw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write parquet data: %w", err)
+	if input.ProjectFromEstimates {
+		projections := algos.ProjectFairValue(model.FairValuePE, fairValuePriceRecords, estimateRecords)
+		if projections == nil {
+			logs = append(logs, fmt.Sprintf("No EPS estimates ahead of the latest fair value for %s, nothing to project", input.Ticker))
+		}
+		combinedData = append(combinedData, types.ProjectedFairValueToCombined(projections)...)
+	}
+
+	var dailyFairValues []types.DailyFairValueRecord
+	switch {
+	case input.DailyFairValue == algos.DailyFairValueTTM:
+		// Looks back a year from each day, so it sees the quarters before the range.
+		dailyFairValues = algos.TTMDailyFairValue(filteredDailyPrices, quarterlyEarningsRecords, model.FairValuePE)
+	case input.DailyFairValue != "":
+		dailyFairValues = algos.InterpolateDailyFairValue(filteredDailyPrices, fairValuePriceRecords, input.DailyFairValue)
+	case input.IncludeSignals:
+		dailyFairValues = algos.InterpolateDailyFairValue(filteredDailyPrices, fairValuePriceRecords, algos.DailyFairValueStep)
+	}
+	if input.DailyFairValue != "" {
+		combinedData = append(combinedData, types.DailyFairValueToCombined(dailyFairValues)...)
+		logs = append(logs, premiumLog(input.Ticker, dailyFairValues))
 	}
 
-	absPath, err := filepath.Abs(fileName)
+	premiumStats, hasPremiumStats := algos.CalculatePremiumStatistics(dailyFairValues)
+	if hasPremiumStats {
+		logs = append(logs, fmt.Sprintf("%s closed at %.2f times its daily fair value on %s, a z-score of %.2f and at or above %.0f%% of days in the range (mean %.2f, stdev %.2f)",
+			input.Ticker, premiumStats.LatestRatio, premiumStats.LatestDate, premiumStats.LatestZScore,
+			premiumStats.LatestPercentile, premiumStats.Mean, premiumStats.Stdev))
+	}
+	var signals []types.ValuationSignalRecord
+	if input.IncludeSignals {
+		signals = algos.ValuationSignals(dailyFairValues, signalBands)
+		combinedData = append(combinedData, types.ValuationSignalsToCombined(signals)...)
+		logs = append(logs, signalsLog(input.Ticker, signals, signalBands))
+	}
+
+	var grahamNumbers, grahamValues []types.FairValuePriceRecord
+	if input.IncludeGraham {
+		// Balance sheet share counts are as reported, so book value needs restating like prices.
+		adjustedBookValues := utils.AdjustBookValueForStockSplits(bookValueRecords, stockSplitRecords)
+		grahamNumbers = algos.GrahamNumberHistory(filteredEarnings, adjustedBookValues)
+		combinedData = append(combinedData, types.FairValueToCombined(grahamNumbers, types.SeriesGrahamNumber)...)
+
+		bondYield := input.AAABondYield
+		if bondYield == 0 {
+			bondYield = algos.GrahamBaselineBondYield
+		}
+		// Growth and EPS both come from the earnings the model ran on, so a cyclical gets the same
+		// Graham value whether or not the Lynch P/E was set by hand.
+		growth := model.CAGR
+		var growthErr error
+		if model.PEOverride > 0 {
+			// The Lynch P/E was set by hand, so growth still needs measuring for Graham.
+			growth, growthErr = algos.GrowthRate(algos.EarningsWithinCAGRWindow(modelEarnings, model.CAGRYears), model.GrowthMethod)
+		}
+		if growthErr != nil {
+			logs = append(logs, fmt.Sprintf("Skipped the Graham intrinsic value for %s: %v", input.Ticker, growthErr))
+		} else {
+			grahamValues = algos.GrahamIntrinsicValueHistory(modelEarnings, growth*100, bondYield)
+			combinedData = append(combinedData, types.FairValueToCombined(grahamValues, types.SeriesGrahamValue)...)
+		}
+		logs = append(logs, grahamLog(input.Ticker, grahamNumbers, grahamValues, growth, bondYield))
+	}
+
+	if cpiRecords != nil {
+		// CAPE looks back ten years from each day, so it uses the full quarterly history rather
+		// than only the quarters inside the date range.
+		capeRecords, medianCAPE, err := algos.CalculateCAPEHistory(filteredDailyPrices, quarterlyEarningsRecords, cpiRecords)
+		switch {
+		case errors.Is(err, algos.ErrInsufficientCAPEHistory):
+			// The Lynch fair value is still good, only the overlay is missing.
+			logs = append(logs, fmt.Sprintf("Skipped CAPE for %s: %v", input.Ticker, err))
+		case err != nil:
+			return nil, stageErrorf(StageCalculate, "could not calculate CAPE: %w", err)
+		default:
+			combinedData = append(combinedData, types.CAPEToCombined(capeRecords)...)
+			logs = append(logs, fmt.Sprintf("Median CAPE for %s is %.2f over %d days", input.Ticker, medianCAPE, len(capeRecords)))
+		}
+	}
+
+	// Includes days before the range starts in the EPS lookup, so the first days of the range still
+	// see the EPS that was known then.
+	peRatios := algos.HistoricalPE(filteredDailyPrices, fairValueEarnings, quarterlyEarningsRecords)
+	peBands := algos.PERatioBands(peRatios)
+	if input.IncludePERatio {
+		combinedData = append(combinedData, types.PERatioToCombined(peRatios)...)
+		combinedData = append(combinedData, types.PERatioBandsToCombined(peBands)...)
+		if len(peRatios) > 0 {
+			latest, rank := algos.LatestPERank(peRatios)
+			logs = append(logs, fmt.Sprintf("%s traded at a P/E of %.2f on %s, at or above %.0f%% of days in the range (median %.2f)",
+				input.Ticker, latest.Ratio, latest.Date, rank, algos.Median(algos.PERatios(peRatios))))
+		} else {
+			logs = append(logs, fmt.Sprintf("No days with positive EPS for %s, no P/E to show", input.Ticker))
+		}
+	}
+
+	var pegRatios []types.PEGRecord
+	if input.IncludePEG {
+		// The yield looks back a year from each day, so it sees dividends from before the range.
+		adjustedDividends := utils.AdjustDividendsForStockSplits(dividendRecords, stockSplitRecords)
+		yields := algos.TrailingTwelveMonthYield(filteredDailyPrices, adjustedDividends)
+		pegRatios = algos.HistoricalPEG(peRatios, fairValueEarnings, quarterlyEarningsRecords, yields, model.CAGRYears, model.GrowthMethod)
+		combinedData = append(combinedData, types.PEGToCombined(pegRatios)...)
+		if latest, ok := algos.LatestPEG(pegRatios); ok {
+			logs = append(logs, fmt.Sprintf("%s traded at a PEG of %.2f and a PEGY of %.2f on %s (%.2f%% EPS growth, %.2f%% dividend yield)",
+				input.Ticker, latest.PEG, latest.PEGY, latest.Date, latest.Growth*100, latest.DividendYield*100))
+		} else {
+			logs = append(logs, fmt.Sprintf("No days with positive EPS growth for %s, no PEG to show", input.Ticker))
+		}
+	}
+
+	if err := prepareOutputDir(ctx, input.OutputDir); err != nil {
+		return nil, err
+	}
+
+	fileName := tickerFileName(input.OutputDir, input.Ticker, "")
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d records to %s", len(combinedData), fileName))
+	absPath, writeLogMessage, err := writeParquetFile(fileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteCombinedPriceDataToParquet(combinedData, fw)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", fileName, err)
+		return nil, err
 	}
 
 	output := &LynchFairValueOutputs{
 		RecordCount:       len(filteredDailyPrices),
 		FilePath:          absPath,
+		Model:             model,
 		CombinedPriceData: combinedData,
-		Logs:              []string{writeLogMessage},
+		Logs:              append(logs, writeLogMessage),
+	}
+
+	if input.IncludeOHLCV {
+		ohlcvFileName := tickerFileName(input.OutputDir, input.Ticker, OHLCVFileSuffix)
+		input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d OHLCV records to %s", len(filteredDailyPrices), ohlcvFileName))
+		ohlcvPath, ohlcvLogMessage, err := writeParquetFile(ohlcvFileName, func(fw io.WriteCloser) (string, error) {
+			return p.parquetWriter.WriteDailyStockDataToParquet(filteredDailyPrices, fw)
+		})
+		if err != nil {
+			return nil, err
+		}
+		output.OHLCVFilePath = ohlcvPath
+		output.Logs = append(output.Logs, ohlcvLogMessage)
+	}
+
+	if input.IncludeSignals {
+		signalsFileName := tickerFileName(input.OutputDir, input.Ticker, SignalsFileSuffix)
+		input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d valuation signals to %s", len(signals), signalsFileName))
+		signalsPath, signalsLogMessage, err := writeParquetFile(signalsFileName, func(fw io.WriteCloser) (string, error) {
+			return p.parquetWriter.WriteValuationSignalsToParquet(signals, fw)
+		})
+		if err != nil {
+			return nil, err
+		}
+		output.SignalsFilePath = signalsPath
+		output.Signals = signals
+		output.Logs = append(output.Logs, signalsLogMessage)
+	}
+
+	createdAt := p.now().UTC()
+	metadata := RunMetadata{
+		RunID:       newRunID(input.Ticker, createdAt),
+		Pipeline:    "lynch",
+		CiboVersion: version.String(),
+		CreatedAt:   createdAt,
+		Inputs: RunMetadataInputs{
+			Ticker:               input.Ticker,
+			StartDate:            input.StartDate,
+			EndDate:              input.EndDate,
+			CPIFilePath:          input.CPIFilePath,
+			IncludeOHLCV:         input.IncludeOHLCV,
+			UseTTMEPS:            input.UseTTMEPS,
+			ProjectFromEstimates: input.ProjectFromEstimates,
+			IncludePERatio:       input.IncludePERatio,
+			IncludePEG:           input.IncludePEG,
+			IncludeGraham:        input.IncludeGraham,
+			AAABondYield:         input.AAABondYield,
+			DailyFairValue:       input.DailyFairValue,
+			IncludeSignals:       input.IncludeSignals,
+		},
+		Model:         model,
+		PriceDates:    dateRangeOf(dailyPriceDates(filteredDailyPrices)),
+		EarningsDates: dateRangeOf(earningsDates(filteredEarnings)),
+		RecordCounts: map[string]int{
+			"daily_prices":        len(filteredDailyPrices),
+			"fair_value_earnings": len(filteredEarnings),
+			"quarterly_earnings":  len(quarterlyEarningsRecords),
+			"fair_value_points":   len(fairValuePriceRecords),
+			"pe_ratios":           len(peRatios),
+			"combined_records":    len(combinedData),
+		},
+		SkippedRecords: map[string]int{
+			"daily_prices":       skippedDailyPrices,
+			"annual_earnings":    skippedAnnualEarnings,
+			"quarterly_earnings": skippedQuarterlyEarnings,
+		},
+	}
+	if input.IncludePEG {
+		metadata.RecordCounts["dividends"] = len(dividendRecords)
+		metadata.RecordCounts["peg_ratios"] = len(pegRatios)
 	}
+	if input.DailyFairValue != "" {
+		metadata.RecordCounts["daily_fair_values"] = len(dailyFairValues)
+	}
+	if hasPremiumStats {
+		metadata.PremiumStats = &premiumStats
+	}
+	if input.IncludeSignals {
+		metadata.Inputs.SignalBands = &signalBands
+		metadata.RecordCounts["valuation_signals"] = len(signals)
+	}
+	if input.IncludeGraham {
+		metadata.RecordCounts["graham_numbers"] = len(grahamNumbers)
+		metadata.RecordCounts["graham_values"] = len(grahamValues)
+	}
+	if len(peRatios) > 0 {
+		ratios := algos.PERatios(peRatios)
+		metadata.MeanPE = algos.Mean(ratios)
+		metadata.MedianPE = algos.Median(ratios)
+		latest, rank := algos.LatestPERank(peRatios)
+		metadata.LatestPE = latest.Ratio
+		metadata.LatestPEPercentile = rank
+		metadata.PERatioBands = make(map[string]float64, len(peBands))
+		for _, band := range peBands {
+			metadata.PERatioBands[band.Series] = band.Ratio
+		}
+	}
+	metadataPath, metadataParquetPath, metadataLogs, err := writeRunMetadata(input.OutputDir, metadata, p.parquetWriter)
+	if err != nil {
+		return nil, err
+	}
+	output.Metadata = metadata
+	output.MetadataFilePath = metadataPath
+	output.MetadataParquetFilePath = metadataParquetPath
+	output.Logs = append(output.Logs, metadataLogs...)
 
 	return output, nil
 }
+
+// Latest Graham Number and intrinsic value, e.g. for spotting a balance sheet that gave no book value.
+func grahamLog(ticker string, grahamNumbers, grahamValues []types.FairValuePriceRecord, growth, bondYield float64) string {
+	latest := func(records []types.FairValuePriceRecord) string {
+		if len(records) == 0 {
+			return "none"
+		}
+		newest := records[0]
+		for _, record := range records {
+			if record.Date > newest.Date {
+				newest = record
+			}
+		}
+		return fmt.Sprintf("%.2f as of %s", newest.FairValuePrice, newest.Date)
+	}
+	return fmt.Sprintf("Graham Number for %s: %s. Graham value at %.2f%% growth and a %.2f%% AAA yield: %s",
+		ticker, latest(grahamNumbers), growth*100, bondYield, latest(grahamValues))
+}
+
+// Where the latest close sits against the daily fair value.
+func premiumLog(ticker string, dailyFairValues []types.DailyFairValueRecord) string {
+	latest, ok := algos.LatestDailyFairValue(dailyFairValues)
+	if !ok {
+		return fmt.Sprintf("No trading days with a fair value for %s, no premium to show", ticker)
+	}
+	direction := "above"
+	if latest.PremiumPct < 0 {
+		direction = "below"
+	}
+	return fmt.Sprintf("%s closed %.2f%% %s its daily fair value of %.2f on %s",
+		ticker, math.Abs(latest.PremiumPct), direction, latest.FairValuePrice, latest.Date)
+}
+
+// How many times the close crossed into a band, and the latest crossing.
+func signalsLog(ticker string, signals []types.ValuationSignalRecord, bands ValuationBands) string {
+	if len(signals) == 0 {
+		return fmt.Sprintf("%s never crossed into the +%.0f%% overvalued or -%.0f%% undervalued band",
+			ticker, bands.OvervaluedPct, bands.UndervaluedPct)
+	}
+	latest := signals[len(signals)-1]
+	return fmt.Sprintf("%s crossed into the +%.0f%% overvalued or -%.0f%% undervalued band %d times, most recently %s on %s at a %+.2f%% premium",
+		ticker, bands.OvervaluedPct, bands.UndervaluedPct, len(signals), latest.Signal, latest.Date, latest.PremiumPct)
+}
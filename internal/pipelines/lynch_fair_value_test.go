@@ -1,11 +1,20 @@
 package pipelines
 
 import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/api"
 	"cibo/internal/types"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -16,34 +25,132 @@ type mockAPIClient struct {
 	dailyPriceResponse   []byte
 	earningsResponse     []byte
 	stockSplitsResponse  []byte
+	incomeResponse       []byte
+	balanceSheetResponse []byte
+	cashFlowResponse     []byte
+	dividendsResponse    []byte
+	calendarResponse     []byte
+	estimatesResponse    []byte
 	shouldReturnFetchErr bool
+	// Returned from every fetch when set, for tests that care about the error type.
+	fetchErr error
 }
 
-func (m *mockAPIClient) FetchDailyPrice(ticker string) ([]byte, error) {
+func (m *mockAPIClient) FetchDailyPrice(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
 	if m.shouldReturnFetchErr {
 		return nil, errors.New("mock API fetch error")
 	}
 	return m.dailyPriceResponse, nil
 }
 
-func (m *mockAPIClient) FetchEarnings(ticker string) ([]byte, error) {
+func (m *mockAPIClient) FetchEarnings(ctx context.Context, ticker string) ([]byte, error) {
 	if m.shouldReturnFetchErr {
 		return nil, errors.New("mock API fetch error")
 	}
 	return m.earningsResponse, nil
 }
 
-func (m *mockAPIClient) FetchStockSplits(ticker string) ([]byte, error) {
+func (m *mockAPIClient) FetchStockSplits(ctx context.Context, ticker string) ([]byte, error) {
 	if m.shouldReturnFetchErr {
 		return nil, errors.New("mock API fetch error")
 	}
 	return m.stockSplitsResponse, nil
 }
 
+func (m *mockAPIClient) FetchIncomeStatement(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.incomeResponse, nil
+}
+
+func (m *mockAPIClient) FetchBalanceSheet(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.balanceSheetResponse, nil
+}
+
+func (m *mockAPIClient) FetchCashFlow(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.cashFlowResponse, nil
+}
+
+func (m *mockAPIClient) FetchDividends(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.dividendsResponse, nil
+}
+
+func (m *mockAPIClient) FetchEarningsCalendar(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.calendarResponse, nil
+}
+
+func (m *mockAPIClient) FetchEarningsEstimates(ctx context.Context, ticker string) ([]byte, error) {
+	if m.fetchErr != nil {
+		return nil, m.fetchErr
+	}
+	if m.shouldReturnFetchErr {
+		return nil, errors.New("mock API fetch error")
+	}
+	return m.estimatesResponse, nil
+}
+
+/*
+Closes around two fiscal year ends and a day after a 2-for-1 split on 2025-01-08, the history the
+price to sales, DCF and dividend tests share. Each test adds the endpoint its pipeline values on.
+Pre split closes are 180 on 2023-12-29, 180 on 2024-06-28 and 200 on 2024-12-31, so 90, 90 and
+100 once adjusted.
+*/
+func newSplitPriceMockClient() *mockAPIClient {
+	return &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-01-10": {"4. close": "110.00"},
+				"2024-12-31": {"4. close": "200.00"},
+				"2024-06-28": {"4. close": "180.00"},
+				"2023-12-29": {"4. close": "180.00"}
+			}
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": [{"effective_date": "2025-01-08", "split_factor": "2.0"}]}`),
+	}
+}
+
 type mockParquetWriter struct {
 	shouldReturnWriteErr bool
 	wasCalled            bool
 	receivedData         []types.CombinedPriceRecord
+	receivedDaily        []types.DailyStockRecord
+	receivedDividends    []types.DividendRecord
+	receivedEvents       []types.EarningsEventRecord
+	receivedMetadata     []types.MetadataRecord
+	receivedSensitivity  []types.DCFSensitivityRecord
+	receivedSignals      []types.ValuationSignalRecord
 }
 
 func (m *mockParquetWriter) WriteCombinedPriceDataToParquet(records []types.CombinedPriceRecord, writer io.WriteCloser) (string, error) {
@@ -56,6 +163,60 @@ func (m *mockParquetWriter) WriteCombinedPriceDataToParquet(records []types.Comb
 	return "mock write success log", nil
 }
 
+func (m *mockParquetWriter) WriteDailyStockDataToParquet(records []types.DailyStockRecord, writer io.WriteCloser) (string, error) {
+	m.receivedDaily = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock OHLCV write success log", nil
+}
+
+func (m *mockParquetWriter) WriteDividendsToParquet(records []types.DividendRecord, writer io.WriteCloser) (string, error) {
+	m.receivedDividends = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock dividends write success log", nil
+}
+
+func (m *mockParquetWriter) WriteEarningsEventsToParquet(records []types.EarningsEventRecord, writer io.WriteCloser) (string, error) {
+	m.receivedEvents = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock earnings events write success log", nil
+}
+
+func (m *mockParquetWriter) WriteMetadataToParquet(records []types.MetadataRecord, writer io.WriteCloser) (string, error) {
+	m.receivedMetadata = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock metadata write success log", nil
+}
+
+func (m *mockParquetWriter) WriteDCFSensitivityToParquet(records []types.DCFSensitivityRecord, writer io.WriteCloser) (string, error) {
+	m.receivedSensitivity = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock DCF sensitivity write success log", nil
+}
+
+func (m *mockParquetWriter) WriteValuationSignalsToParquet(records []types.ValuationSignalRecord, writer io.WriteCloser) (string, error) {
+	m.receivedSignals = records
+	if m.shouldReturnWriteErr {
+		return "", errors.New("mock parquet write error")
+	}
+
+	return "mock valuation signals write success log", nil
+}
+
 // Given that all minimum required data, verify that the pipeline runs correctly
 // and produces the expected combined data output.
 func TestLynchFairValuePipeline_RunPipeline_Success(t *testing.T) {
@@ -77,13 +238,13 @@ func TestLynchFairValuePipeline_RunPipeline_Success(t *testing.T) {
 	}
 	mockWriter := &mockParquetWriter{}
 
-	dummyFilePath := "/workspaces/cibo/internal/pipelines/TEST.parquet"
-	defer os.Remove(dummyFilePath)
+	outputDir := t.TempDir()
+	dummyFilePath := filepath.Join(outputDir, "TEST.parquet")
 
 	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
-	input := LynchFairValueInputs{Ticker: "TEST"}
+	input := LynchFairValueInputs{Ticker: "TEST", OutputDir: outputDir}
 
-	output, err := pipeline.RunPipeline(input)
+	output, err := pipeline.RunPipeline(context.Background(), input)
 
 	if err != nil {
 		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
@@ -126,7 +287,7 @@ func TestLynchFairValuePipeline_RunPipeline_APIFetchError(t *testing.T) {
 	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
 	input := LynchFairValueInputs{Ticker: "FAIL"}
 
-	output, err := pipeline.RunPipeline(input)
+	output, err := pipeline.RunPipeline(context.Background(), input)
 
 	if err == nil {
 		t.Fatal("RunPipeline() was expected to return an error, but it returned nil")
@@ -137,6 +298,104 @@ func TestLynchFairValuePipeline_RunPipeline_APIFetchError(t *testing.T) {
 	if mockWriter.wasCalled {
 		t.Error("WriteCombinedPriceDataToParquet should not be called when the API fetch fails")
 	}
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) {
+		t.Fatalf("Expected error to be a *StageError, got %T", err)
+	}
+	if stageErr.Stage != StageFetch {
+		t.Errorf("Expected error stage '%s', got '%s'", StageFetch, stageErr.Stage)
+	}
+}
+
+// Given the API rejects the call for a symbol, verify the error is reported as an unknown ticker
+// with guidance, while keeping the original API error in the chain.
+func TestLynchFairValuePipeline_RunPipeline_UnknownTicker(t *testing.T) {
+	apiErr := &api.APIMessageError{Kind: api.ErrInvalidCall, Message: "Invalid API call."}
+	mockClient := &mockAPIClient{fetchErr: apiErr}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{Ticker: "NOPE"})
+
+	if !errors.Is(err, ErrUnknownTicker) {
+		t.Errorf("Expected an unknown ticker error, but got: %v", err)
+	}
+	if !errors.Is(err, api.ErrInvalidCall) {
+		t.Errorf("Expected the API error to be kept in the chain, but got: %v", err)
+	}
+	if Guidance(err) == "" {
+		t.Error("Expected guidance for an unknown ticker")
+	}
+}
+
+// Given a start date after the end date, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_InvalidDateRange(t *testing.T) {
+	// Any fetch would fail, so reaching one would show up as a fetch stage error.
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		StartDate: "2025-01-01",
+		EndDate:   "2020-01-01",
+	})
+
+	if !errors.Is(err, ErrInvalidDateRange) {
+		t.Fatalf("Expected an invalid date range error, but got: %v", err)
+	}
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, got: %v", err)
+	}
+}
+
+// Given a single year of earnings, verify the calculation error can be identified as
+// insufficient earnings.
+func TestLynchFairValuePipeline_RunPipeline_InsufficientEarnings(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse:    []byte(`{"symbol": "TEST", "annualEarnings": [{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"}]}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{Ticker: "TEST", OutputDir: t.TempDir()})
+
+	if !errors.Is(err, ErrInsufficientEarnings) {
+		t.Errorf("Expected an insufficient earnings error, but got: %v", err)
+	}
+}
+
+// Given a run that is cancelled before it starts, verify that the pipeline stops with the context
+// error and never writes a file.
+func TestLynchFairValuePipeline_RunPipeline_Cancelled(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse:    []byte(`{"symbol": "TEST", "annualEarnings": []}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+	mockWriter := &mockParquetWriter{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
+	output, err := pipeline.RunPipeline(ctx, LynchFairValueInputs{Ticker: "TEST", OutputDir: t.TempDir()})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a context cancelled error, but got: %v", err)
+	}
+	if output != nil {
+		t.Error("RunPipeline() was expected to return a nil output on cancellation")
+	}
+	if mockWriter.wasCalled {
+		t.Error("WriteCombinedPriceDataToParquet should not be called for a cancelled run")
+	}
 }
 
 // Given that the Parquet writer returns an error, verify that the pipeline
@@ -166,7 +425,7 @@ func TestLynchFairValuePipeline_RunPipeline_ParquetWriteError(t *testing.T) {
 	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
 	input := LynchFairValueInputs{Ticker: "TEST"}
 
-	output, err := pipeline.RunPipeline(input)
+	output, err := pipeline.RunPipeline(context.Background(), input)
 
 	if err == nil {
 		t.Fatal("RunPipeline() was expected to return an error for a failed write, but it returned nil")
@@ -177,6 +436,59 @@ func TestLynchFairValuePipeline_RunPipeline_ParquetWriteError(t *testing.T) {
 	if !mockWriter.wasCalled {
 		t.Error("Expected WriteCombinedPriceDataToParquet to be called, even on failure")
 	}
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageWrite {
+		t.Errorf("Expected a write stage error, got: %v", err)
+	}
+}
+
+// Given an output directory that does not exist yet, verify that the pipeline creates it,
+// writes the file inside it and reports progress for every stage along the way.
+func TestLynchFairValuePipeline_RunPipeline_OutputDirAndProgress(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+	mockWriter := &mockParquetWriter{}
+	outputDir := filepath.Join(t.TempDir(), "nested", "out")
+
+	var stages []Stage
+	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
+	input := LynchFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: outputDir,
+		OnProgress: func(stage Stage, message string) {
+			stages = append(stages, stage)
+		},
+	}
+
+	output, err := pipeline.RunPipeline(context.Background(), input)
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST.parquet"), output.FilePath); diff != "" {
+		t.Errorf("RunPipeline() FilePath mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(outputDir); err != nil {
+		t.Errorf("Expected output directory to be created, got: %v", err)
+	}
+
+	expectedStages := []Stage{StageFetch, StageParse, StageCalculate, StageWrite}
+	if diff := cmp.Diff(expectedStages, stages); diff != "" {
+		t.Errorf("RunPipeline() progress stages mismatch (-want +got):\n%s", diff)
+	}
 }
 
 // Given data that includes a stock split, verify that the pipeline correctly adjusts
@@ -208,12 +520,9 @@ func TestLynchFairValuePipeline_RunPipeline_WithStockSplit(t *testing.T) {
 	}
 	mockWriter := &mockParquetWriter{}
 	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
-	input := LynchFairValueInputs{Ticker: "SPLIT"}
-
-	dummyFilePath := "/workspaces/cibo/internal/pipelines/SPLIT.parquet"
-	defer os.Remove(dummyFilePath)
+	input := LynchFairValueInputs{Ticker: "SPLIT", OutputDir: t.TempDir()}
 
-	output, err := pipeline.RunPipeline(input)
+	output, err := pipeline.RunPipeline(context.Background(), input)
 	if err != nil {
 		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
 	}
@@ -248,3 +557,1090 @@ func TestLynchFairValuePipeline_RunPipeline_WithStockSplit(t *testing.T) {
 		t.Error("Expected WriteCombinedPriceDataToParquet to be called, but it was not")
 	}
 }
+
+// Given a run that asks for OHLCV output, verify the split adjusted, date filtered daily records
+// are written to their own file alongside the combined one.
+func TestLynchFairValuePipeline_RunPipeline_IncludeOHLCV(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-01-10": {"1. open": "101.00", "2. high": "104.00", "3. low": "100.00", "4. close": "102.00", "5. volume": "1000"},
+				"2025-01-02": {"1. open": "200.00", "2. high": "210.00", "3. low": "190.00", "4. close": "204.00", "5. volume": "500"},
+				"2024-06-03": {"1. open": "180.00", "2. high": "182.00", "3. low": "178.00", "4. close": "181.00", "5. volume": "400"}
+			}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": [{"effective_date": "2025-01-08", "split_factor": "2.0"}]}`),
+	}
+	mockWriter := &mockParquetWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:       "TEST",
+		EndDate:      "2025-01-05",
+		OutputDir:    outputDir,
+		IncludeOHLCV: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	expectedDaily := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2025-01-02", OpenPrice: 100, HighPrice: 105, LowPrice: 95, ClosingPrice: 102, Volume: 1000},
+		{Ticker: "TEST", Date: "2024-06-03", OpenPrice: 90, HighPrice: 91, LowPrice: 89, ClosingPrice: 90.5, Volume: 800},
+	}
+	if diff := cmp.Diff(expectedDaily, mockWriter.receivedDaily); diff != "" {
+		t.Errorf("RunPipeline() OHLCV data mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_ohlcv.parquet"), output.OHLCVFilePath); diff != "" {
+		t.Errorf("RunPipeline() OHLCVFilePath mismatch (-want +got):\n%s", diff)
+	}
+	// Combined, OHLCV and the JSON and parquet metadata.
+	if len(output.Logs) != 4 {
+		t.Errorf("Expected a write log for each file, got: %v", output.Logs)
+	}
+}
+
+// Given a CPI file and a decade of quarterly earnings, verify the CAPE ratio and CAPE fair value
+// series are added alongside the Lynch fair value.
+func TestLynchFairValuePipeline_RunPipeline_WithCPI(t *testing.T) {
+	var quarters []string
+	for year := 2010; year <= 2019; year++ {
+		for _, quarterEnd := range []string{"03-31", "06-30", "09-30", "12-31"} {
+			quarters = append(quarters, fmt.Sprintf(
+				`{"fiscalDateEnding": "%d-%s", "reportedDate": "%d-%s", "reportedEPS": "1.00"}`,
+				year, quarterEnd, year, quarterEnd))
+		}
+	}
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2020-02-14": {"4. close": "80.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2019-12-31", "reportedEPS": "4.00"},
+				{"fiscalDateEnding": "2017-12-31", "reportedEPS": "3.00"}
+			],
+			"quarterlyEarnings": [` + strings.Join(quarters, ",") + `]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+	outputDir := t.TempDir()
+	// Prices doubled since the earnings were booked, so each quarter is worth 2.00 in today's dollars.
+	cpiFile := filepath.Join(outputDir, "cpi.csv")
+	if err := os.WriteFile(cpiFile, []byte("observation_date,CPIAUCSL\n2000-01-01,100\n2020-01-01,200\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:      "TEST",
+		OutputDir:   outputDir,
+		CPIFilePath: cpiFile,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var capeData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if record.Series == types.SeriesCAPERatio || record.Series == types.SeriesCAPEFairValue {
+			capeData = append(capeData, record)
+		}
+	}
+	expectedCAPE := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2020-02-14", Price: 10, Series: types.SeriesCAPERatio},
+		{Ticker: "TEST", Date: "2020-02-14", Price: 80, Series: types.SeriesCAPEFairValue},
+	}
+	if diff := cmp.Diff(expectedCAPE, capeData, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() CAPE series mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a CPI file that doesn't exist, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_MissingCPIFile(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:      "TEST",
+		CPIFilePath: filepath.Join(t.TempDir(), "missing.csv"),
+	})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, got: %v", err)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the file error to be kept in the chain, got: %v", err)
+	}
+}
+
+// Given two years of quarterly earnings, verify a TTM run dates the fair value curve at every
+// quarter end and adds the TTM EPS it was built from.
+func TestLynchFairValuePipeline_RunPipeline_UseTTMEPS(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-02": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "8.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "4.00"}
+			],
+			"quarterlyEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "2.00"},
+				{"fiscalDateEnding": "2024-09-30", "reportedEPS": "2.00"},
+				{"fiscalDateEnding": "2024-06-30", "reportedEPS": "2.00"},
+				{"fiscalDateEnding": "2024-03-31", "reportedEPS": "2.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "1.00"},
+				{"fiscalDateEnding": "2023-09-30", "reportedEPS": "1.00"},
+				{"fiscalDateEnding": "2023-06-30", "reportedEPS": "1.00"},
+				{"fiscalDateEnding": "2023-03-31", "reportedEPS": "1.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: t.TempDir(),
+		UseTTMEPS: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var fairValueDates []string
+	var ttmData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		switch record.Series {
+		case types.SeriesFairValue:
+			fairValueDates = append(fairValueDates, record.Date)
+		case types.SeriesTTMEPS:
+			ttmData = append(ttmData, record)
+		}
+	}
+
+	expectedDates := []string{"2023-12-31", "2024-03-31", "2024-06-30", "2024-09-30", "2024-12-31"}
+	if diff := cmp.Diff(expectedDates, fairValueDates); diff != "" {
+		t.Errorf("RunPipeline() fair value dates mismatch (-want +got):\n%s", diff)
+	}
+	expectedTTM := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2023-12-31", Price: 4, Series: types.SeriesTTMEPS},
+		{Ticker: "TEST", Date: "2024-03-31", Price: 5, Series: types.SeriesTTMEPS},
+		{Ticker: "TEST", Date: "2024-06-30", Price: 6, Series: types.SeriesTTMEPS},
+		{Ticker: "TEST", Date: "2024-09-30", Price: 7, Series: types.SeriesTTMEPS},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 8, Series: types.SeriesTTMEPS},
+	}
+	if diff := cmp.Diff(expectedTTM, ttmData, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() TTM EPS mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given quarterly reports with estimates inside and outside the date range, verify a marker is
+// emitted for each report in the range, in the series matching its result.
+func TestLynchFairValuePipeline_RunPipeline_EarningsSurprises(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2024-08-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "8.00"},
+				{"fiscalDateEnding": "2021-12-31", "reportedEPS": "4.00"}
+			],
+			"quarterlyEarnings": [
+				{"fiscalDateEnding": "2024-06-30", "reportedDate": "2024-07-25", "reportedEPS": "2.00",
+					"estimatedEPS": "2.20", "surprise": "-0.20", "surprisePercentage": "-9.0909"},
+				{"fiscalDateEnding": "2024-03-31", "reportedDate": "2024-04-25", "reportedEPS": "2.10",
+					"estimatedEPS": "2.00", "surprise": "0.10", "surprisePercentage": "5"},
+				{"fiscalDateEnding": "2020-12-31", "reportedDate": "2021-01-28", "reportedEPS": "1.00",
+					"estimatedEPS": "0.90", "surprise": "0.10", "surprisePercentage": "11.1111"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		StartDate: "2021-06-01",
+		OutputDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var markers []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if strings.HasPrefix(record.Series, "earnings_") {
+			markers = append(markers, record)
+		}
+	}
+	expected := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2024-04-25", Price: 5, Series: types.SeriesEarningsBeat},
+		{Ticker: "TEST", Date: "2024-07-25", Price: -9.0909, Series: types.SeriesEarningsMiss},
+	}
+	if diff := cmp.Diff(expected, markers); diff != "" {
+		t.Errorf("RunPipeline() earnings markers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given estimates for the next two fiscal years, verify the projected series start at the latest
+// fair value and carry the same fair value PE onto each estimate.
+func TestLynchFairValuePipeline_RunPipeline_ProjectFromEstimates(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-02": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "8.00"},
+				{"fiscalDateEnding": "2021-12-31", "reportedEPS": "4.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+		estimatesResponse: []byte(`{
+			"symbol": "TEST",
+			"estimates": [
+				{"date": "2026-12-31", "horizon": "next fiscal year", "eps_estimate_average": "10.00",
+					"eps_estimate_high": "11.00", "eps_estimate_low": "9.00"},
+				{"date": "2025-12-31", "horizon": "current fiscal year", "eps_estimate_average": "9.00",
+					"eps_estimate_high": "9.50", "eps_estimate_low": "8.50"}
+			]
+		}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:               "TEST",
+		OutputDir:            t.TempDir(),
+		ProjectFromEstimates: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var latestFairValue float64
+	projected := map[string][]float64{}
+	for _, record := range output.CombinedPriceData {
+		switch record.Series {
+		case types.SeriesFairValue:
+			if record.Date == "2024-12-31" {
+				latestFairValue = record.Price
+			}
+		case types.SeriesFairValueProjected, types.SeriesFairValueProjectedHigh, types.SeriesFairValueProjectedLow:
+			projected[record.Series] = append(projected[record.Series], record.Price)
+		}
+	}
+
+	// The fair value PE is the latest fair value over its EPS.
+	pe := latestFairValue / 8
+	expected := map[string][]float64{
+		types.SeriesFairValueProjected:     {latestFairValue, 9 * pe, 10 * pe},
+		types.SeriesFairValueProjectedHigh: {latestFairValue, 9.5 * pe, 11 * pe},
+		types.SeriesFairValueProjectedLow:  {latestFairValue, 8.5 * pe, 9 * pe},
+	}
+	if diff := cmp.Diff(expected, projected, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() projected series mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a P/E override, verify the fair value uses it and the output records how it was run.
+func TestLynchFairValuePipeline_RunPipeline_ModelParams(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: t.TempDir(),
+		Model:     LynchModelParams{PEOverride: 15},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	expectedModel := LynchModelSummary{LynchModelParams: LynchModelParams{PEOverride: 15}, FairValuePE: 15}
+	if diff := cmp.Diff(expectedModel, output.Model); diff != "" {
+		t.Errorf("RunPipeline() Model mismatch (-want +got):\n%s", diff)
+	}
+	for _, record := range output.CombinedPriceData {
+		if record.Series == types.SeriesFairValue && record.Date == "2024-12-31" && record.Price != 150 {
+			t.Errorf("Expected the overridden P/E to give a fair value of 150, got %.2f", record.Price)
+		}
+	}
+}
+
+// Given a minimum P/E above the maximum, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_InvalidModelParams(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker: "TEST",
+		Model:  LynchModelParams{MinPE: 30, MaxPE: 10},
+	})
+
+	if !errors.Is(err, ErrInvalidModelParams) {
+		t.Fatalf("Expected an invalid model params error, but got: %v", err)
+	}
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, got: %v", err)
+	}
+	if Guidance(err) == "" {
+		t.Error("Expected guidance for invalid model params")
+	}
+}
+
+// Given the regression growth method, verify the fit's diagnostics land in the run metadata.
+func TestLynchFairValuePipeline_RunPipeline_RegressionGrowth(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "12.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "-1.00"},
+				{"fiscalDateEnding": "2022-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2021-12-31", "reportedEPS": "9.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: t.TempDir(),
+		Model:     LynchModelParams{GrowthMethod: algos.GrowthMethodRegression},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	fit := output.Metadata.Model.GrowthFit
+	if fit == nil {
+		t.Fatal("Expected the run metadata to carry the growth fit")
+	}
+	if fit.Points != 3 || fit.DroppedYears != 1 {
+		t.Errorf("Expected 3 points with the loss year dropped, got %d points and %d dropped", fit.Points, fit.DroppedYears)
+	}
+	keyValues, err := output.Metadata.KeyValues()
+	if err != nil {
+		t.Fatalf("KeyValues() returned an unexpected error: %v", err)
+	}
+	found := map[string]bool{}
+	for _, record := range keyValues {
+		found[record.Key] = true
+	}
+	for _, key := range []string{"model.growth_method", "model.growth_fit.r_squared", "model.growth_fit.cagr_low", "model.growth_fit.cagr_high"} {
+		if !found[key] {
+			t.Errorf("Expected the metadata to contain %s", key)
+		}
+	}
+}
+
+// Given a cyclical that lost money in its latest year, verify classifying values it instead of
+// failing, and the classification and dropped years are logged and kept in the run metadata.
+func TestLynchFairValuePipeline_RunPipeline_ClassifyEarnings(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-01": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "-1.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "6.00"},
+				{"fiscalDateEnding": "2022-12-31", "reportedEPS": "5.00"},
+				{"fiscalDateEnding": "2021-12-31", "reportedEPS": "-8.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	if _, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{Ticker: "TEST", OutputDir: t.TempDir()}); !errors.Is(err, ErrNegativeEarnings) {
+		t.Fatalf("Expected the plain model to fail on the latest loss, got: %v", err)
+	}
+
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:    "TEST",
+		OutputDir: t.TempDir(),
+		Model:     LynchModelParams{ClassifyEarnings: true},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	profile := output.Metadata.Model.EarningsProfile
+	if profile == nil || profile.Class != algos.EarningsCyclical {
+		t.Fatalf("Expected the run metadata to classify the company as cyclical, got %+v", profile)
+	}
+	// Normalized EPS is -8, -1.5, 1 and 0.5, so the first two years have no fair value.
+	if diff := cmp.Diff([]string{"2021-12-31", "2022-12-31"}, output.Metadata.Model.DroppedYears); diff != "" {
+		t.Errorf("RunPipeline() DroppedYears mismatch (-want +got):\n%s", diff)
+	}
+	for _, expected := range []string{
+		"TEST classified as cyclical with 2 loss years, valued on 5 year average EPS",
+		"No fair value for TEST on 2 fiscal dates without positive EPS: 2021-12-31, 2022-12-31",
+	} {
+		if !slices.Contains(output.Logs, expected) {
+			t.Errorf("Expected logs to contain %q, got %v", expected, output.Logs)
+		}
+	}
+}
+
+// Given a run with a malformed price and an annual report that came out a month after its fiscal
+// year end, verify the metadata files are written with the point in time P/E statistics, the skipped
+// record and a run ID from the run time.
+func TestLynchFairValuePipeline_RunPipeline_Metadata(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-02-03": {"4. close": "200.00"},
+				"2025-01-03": {"4. close": "bad"},
+				"2025-01-02": {"4. close": "150.00"},
+				"2024-06-03": {"4. close": "100.00"}
+			}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			],
+			"quarterlyEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedDate": "2025-01-30", "reportedEPS": "3.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+	mockWriter := &mockParquetWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewLynchFairValuePipeline(mockClient, mockWriter)
+	pipeline.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{Ticker: "TEST", OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	// The 2024 EPS of 10 is only known from 2025-01-30, so 2025-01-02 is still priced on 5.
+	// P/Es are 20, 30 and 20.
+	expected := RunMetadata{
+		RunID:       "TEST_1740830400",
+		Pipeline:    "lynch",
+		CiboVersion: output.Metadata.CiboVersion,
+		CreatedAt:   time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Inputs:      RunMetadataInputs{Ticker: "TEST"},
+		Model:       output.Model,
+		MeanPE:      70.0 / 3,
+		MedianPE:    20,
+		// 2025-02-03 is at a P/E of 20, same as one other day.
+		LatestPE:           20,
+		LatestPEPercentile: 200.0 / 3,
+		PERatioBands: map[string]float64{
+			"pe_ratio_mean":   70.0 / 3,
+			"pe_ratio_median": 20,
+			"pe_ratio_p10":    20,
+			"pe_ratio_p25":    20,
+			"pe_ratio_p75":    25,
+			"pe_ratio_p90":    28,
+		},
+		PriceDates:    DateRange{Start: "2024-06-03", End: "2025-02-03"},
+		EarningsDates: DateRange{Start: "2023-12-31", End: "2024-12-31"},
+		RecordCounts: map[string]int{
+			"daily_prices":        3,
+			"fair_value_earnings": 2,
+			"quarterly_earnings":  1,
+			"fair_value_points":   2,
+			"pe_ratios":           3,
+			"combined_records":    5,
+		},
+		SkippedRecords: map[string]int{"daily_prices": 1, "annual_earnings": 0, "quarterly_earnings": 0},
+	}
+	if diff := cmp.Diff(expected, output.Metadata, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() Metadata mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_metadata.json"), output.MetadataFilePath); diff != "" {
+		t.Errorf("RunPipeline() MetadataFilePath mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_metadata.parquet"), output.MetadataParquetFilePath); diff != "" {
+		t.Errorf("RunPipeline() MetadataParquetFilePath mismatch (-want +got):\n%s", diff)
+	}
+	written, err := os.ReadFile(output.MetadataFilePath)
+	if err != nil {
+		t.Fatalf("Expected the metadata JSON file to be written, got: %v", err)
+	}
+	if !strings.Contains(string(written), `"run_id": "TEST_1740830400"`) {
+		t.Errorf("Expected the metadata JSON to contain the run ID, got:\n%s", written)
+	}
+
+	keyValues := make(map[string]string, len(mockWriter.receivedMetadata))
+	for _, record := range mockWriter.receivedMetadata {
+		keyValues[record.Key] = record.Value
+	}
+	expectedKeyValues := map[string]string{
+		"run_id":                       `"TEST_1740830400"`,
+		"inputs.ticker":                `"TEST"`,
+		"median_pe":                    "20",
+		"record_counts.daily_prices":   "3",
+		"skipped_records.daily_prices": "1",
+	}
+	for key, value := range expectedKeyValues {
+		if keyValues[key] != value {
+			t.Errorf("Expected metadata key %s to be %s, got %q", key, value, keyValues[key])
+		}
+	}
+}
+
+// Given a run that asks for the P/E series, verify each day gets its P/E and every band gets a
+// point at both ends of the range, and the latest P/E is logged.
+func TestLynchFairValuePipeline_RunPipeline_IncludePERatio(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-01-02": {"4. close": "150.00"},
+				"2024-06-03": {"4. close": "100.00"}
+			}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:         "TEST",
+		OutputDir:      t.TempDir(),
+		IncludePERatio: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var peData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if strings.HasPrefix(record.Series, types.SeriesPERatio) {
+			peData = append(peData, record)
+		}
+	}
+
+	// P/Es of 20 on 5 EPS, then 15 once the 10 EPS year has closed.
+	pe := func(date string, ratio float64, series string) types.CombinedPriceRecord {
+		return types.CombinedPriceRecord{Ticker: "TEST", Date: date, Price: ratio, Series: series}
+	}
+	expected := []types.CombinedPriceRecord{
+		pe("2025-01-02", 15, types.SeriesPERatio),
+		pe("2024-06-03", 20, types.SeriesPERatio),
+	}
+	for _, band := range []struct {
+		series string
+		ratio  float64
+	}{
+		{types.SeriesPERatioMean, 17.5},
+		{types.SeriesPERatioMedian, 17.5},
+		{types.SeriesPERatioP10, 15.5},
+		{types.SeriesPERatioP25, 16.25},
+		{types.SeriesPERatioP75, 18.75},
+		{types.SeriesPERatioP90, 19.5},
+	} {
+		expected = append(expected, pe("2024-06-03", band.ratio, band.series), pe("2025-01-02", band.ratio, band.series))
+	}
+
+	if diff := cmp.Diff(expected, peData, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() P/E data mismatch (-want +got):\n%s", diff)
+	}
+	expectedLog := "TEST traded at a P/E of 15.00 on 2025-01-02, at or above 50% of days in the range (median 17.50)"
+	if !slices.Contains(output.Logs, expectedLog) {
+		t.Errorf("Expected logs to contain %q, got: %v", expectedLog, output.Logs)
+	}
+}
+
+// Given a balance sheet for both fiscal years and a split between them, verify the Graham series
+// pair each year's EPS with its split adjusted book value and the intrinsic value is scaled to the
+// AAA yield.
+func TestLynchFairValuePipeline_RunPipeline_IncludeGraham(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-02": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		balanceSheetResponse: []byte(`{
+			"symbol": "TEST",
+			"annualReports": [
+				{"fiscalDateEnding": "2024-12-31", "totalShareholderEquity": "4000", "commonStockSharesOutstanding": "100"},
+				{"fiscalDateEnding": "2023-12-31", "totalShareholderEquity": "1000", "commonStockSharesOutstanding": "50"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": [{"effective_date": "2024-06-03", "split_factor": "2.0"}]}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:        "TEST",
+		OutputDir:     t.TempDir(),
+		IncludeGraham: true,
+		AAABondYield:  5.5,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var grahamData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if strings.HasPrefix(record.Series, "graham_") {
+			grahamData = append(grahamData, record)
+		}
+	}
+
+	// Book values of 40 and, restated for the split, 10. Growth is the CAGR the Lynch model measured.
+	growthPercent := output.Model.CAGR * 100
+	expected := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2024-12-31", Price: math.Sqrt(22.5 * 10 * 40), Series: types.SeriesGrahamNumber},
+		{Ticker: "TEST", Date: "2023-12-31", Price: math.Sqrt(22.5 * 5 * 10), Series: types.SeriesGrahamNumber},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 10 * (8.5 + 2*growthPercent) * 4.4 / 5.5, Series: types.SeriesGrahamValue},
+		{Ticker: "TEST", Date: "2023-12-31", Price: 5 * (8.5 + 2*growthPercent) * 4.4 / 5.5, Series: types.SeriesGrahamValue},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool {
+		if a.Series != b.Series {
+			return a.Series < b.Series
+		}
+		return a.Date > b.Date
+	})
+	if diff := cmp.Diff(expected, grahamData, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() Graham data mismatch (-want +got):\n%s", diff)
+	}
+	if output.Metadata.Inputs.AAABondYield != 5.5 || output.Metadata.RecordCounts["graham_values"] != 2 {
+		t.Errorf("Expected the metadata to record the AAA yield and Graham values, got %+v", output.Metadata)
+	}
+}
+
+// Given a classified cyclical, verify the Graham intrinsic value grows normalized EPS at the growth
+// measured on them, and comes out the same whether or not the Lynch P/E was set by hand.
+func TestLynchFairValuePipeline_RunPipeline_IncludeGrahamCyclical(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-02": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "2.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "8.00"},
+				{"fiscalDateEnding": "2022-12-31", "reportedEPS": "4.00"}
+			]
+		}`),
+		balanceSheetResponse: []byte(`{
+			"symbol": "TEST",
+			"annualReports": [
+				{"fiscalDateEnding": "2024-12-31", "totalShareholderEquity": "4000", "commonStockSharesOutstanding": "100"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	grahamValues := func(model LynchModelParams) ([]types.CombinedPriceRecord, LynchModelSummary) {
+		pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+		output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+			Ticker:        "TEST",
+			OutputDir:     t.TempDir(),
+			IncludeGraham: true,
+			Model:         model,
+		})
+		if err != nil {
+			t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+		}
+		var values []types.CombinedPriceRecord
+		for _, record := range output.CombinedPriceData {
+			if record.Series == types.SeriesGrahamValue {
+				values = append(values, record)
+			}
+		}
+		return values, output.Model
+	}
+
+	derived, model := grahamValues(LynchModelParams{ClassifyEarnings: true})
+	overridden, _ := grahamValues(LynchModelParams{ClassifyEarnings: true, PEOverride: 15})
+
+	// Normalized EPS is 4, 6 and 14/3, growing from 4 to 14/3 over the 731 days where raw EPS halved.
+	growthPercent := (math.Pow(14.0/3/4, 365.25/731) - 1) * 100
+	if math.Abs(model.CAGR*100-growthPercent) > 1e-6 {
+		t.Fatalf("Expected the model to measure %.4f%% growth on normalized EPS, got %.4f%%", growthPercent, model.CAGR*100)
+	}
+	multiple := 8.5 + 2*growthPercent
+	expected := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2024-12-31", Price: 14.0 / 3 * multiple, Series: types.SeriesGrahamValue},
+		{Ticker: "TEST", Date: "2023-12-31", Price: 6 * multiple, Series: types.SeriesGrahamValue},
+		{Ticker: "TEST", Date: "2022-12-31", Price: 4 * multiple, Series: types.SeriesGrahamValue},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool { return a.Date > b.Date })
+	if diff := cmp.Diff(expected, derived, sorter, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+		t.Errorf("RunPipeline() Graham values mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(derived, overridden, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() Graham values changed with a P/E override (-derived +overridden):\n%s", diff)
+	}
+}
+
+// Given a negative AAA bond yield, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_NegativeAAABondYield(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:        "TEST",
+		IncludeGraham: true,
+		AAABondYield:  -1,
+	})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, got: %v", err)
+	}
+}
+
+// Given a dividend in the trailing year, verify the PEG divides the P/E by the growth the model
+// measured and the PEGY adds the yield to it.
+func TestLynchFairValuePipeline_RunPipeline_IncludePEG(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {"2025-01-02": {"4. close": "150.00"}}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		dividendsResponse: []byte(`{
+			"symbol": "TEST",
+			"data": [
+				{"ex_dividend_date": "2024-11-08", "declaration_date": "None", "record_date": "None", "payment_date": "2024-12-01", "amount": "3.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:     "TEST",
+		OutputDir:  t.TempDir(),
+		IncludePEG: true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var pegData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if record.Series == types.SeriesPEGRatio || record.Series == types.SeriesPEGYRatio {
+			pegData = append(pegData, record)
+		}
+	}
+
+	// A P/E of 15 and a 2% yield from the 3.00 dividend on a 150 close.
+	growthPercent := output.Model.CAGR * 100
+	expected := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 15 / growthPercent, Series: types.SeriesPEGRatio},
+		{Ticker: "TEST", Date: "2025-01-02", Price: 15 / (growthPercent + 2), Series: types.SeriesPEGYRatio},
+	}
+	if diff := cmp.Diff(expected, pegData, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() PEG data mismatch (-want +got):\n%s", diff)
+	}
+	if !slices.ContainsFunc(output.Logs, func(log string) bool { return strings.HasPrefix(log, "TEST traded at a PEG of") }) {
+		t.Errorf("Expected a PEG log, got: %v", output.Logs)
+	}
+	if output.Metadata.RecordCounts["peg_ratios"] != 1 {
+		t.Errorf("Expected the metadata to count 1 PEG day, got %v", output.Metadata.RecordCounts)
+	}
+}
+
+// Given a step daily fair value, verify every trading day after the first fiscal year end gets the
+// latest yearly fair value and its premium, and the latest premium is logged.
+func TestLynchFairValuePipeline_RunPipeline_DailyFairValue(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-01-02": {"4. close": "165.00"},
+				"2024-06-03": {"4. close": "60.00"},
+				"2023-06-01": {"4. close": "50.00"}
+			}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:         "TEST",
+		OutputDir:      t.TempDir(),
+		DailyFairValue: algos.DailyFairValueStep,
+		Model:          LynchModelParams{PEOverride: 15},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	var dailyData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		if record.Series == types.SeriesDailyFairValue || record.Series == types.SeriesPremiumPct {
+			dailyData = append(dailyData, record)
+		}
+	}
+
+	// Fair values of 75 and 150 at the two year ends, 2023-06-01 comes before either.
+	expected := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 150, Series: types.SeriesDailyFairValue},
+		{Ticker: "TEST", Date: "2025-01-02", Price: 10, Series: types.SeriesPremiumPct},
+		{Ticker: "TEST", Date: "2024-06-03", Price: 75, Series: types.SeriesDailyFairValue},
+		{Ticker: "TEST", Date: "2024-06-03", Price: -20, Series: types.SeriesPremiumPct},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool {
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		return a.Series < b.Series
+	})
+	if diff := cmp.Diff(expected, dailyData, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() daily fair value mismatch (-want +got):\n%s", diff)
+	}
+	expectedLog := "TEST closed 10.00% above its daily fair value of 150.00 on 2025-01-02"
+	if !slices.Contains(output.Logs, expectedLog) {
+		t.Errorf("Expected logs to contain %q, got %v", expectedLog, output.Logs)
+	}
+	if output.Metadata.Inputs.DailyFairValue != algos.DailyFairValueStep || output.Metadata.RecordCounts["daily_fair_values"] != 2 {
+		t.Errorf("Expected the metadata to record the step method and 2 days, got %+v", output.Metadata)
+	}
+}
+
+// Given flat and shrinking EPS, whose growth gives a fair value P/E of zero and below, verify the run
+// still writes its metadata, with no daily fair value days or premium statistics to show.
+func TestLynchFairValuePipeline_RunPipeline_DailyFairValueNonPositive(t *testing.T) {
+	tests := []struct {
+		name     string
+		earnings string
+	}{
+		{"flat", `[
+			{"fiscalDateEnding": "2024-12-31", "reportedEPS": "5.00"},
+			{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+		]`},
+		{"declining", `[
+			{"fiscalDateEnding": "2024-12-31", "reportedEPS": "4.00"},
+			{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+		]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAPIClient{
+				dailyPriceResponse: []byte(`{
+					"Meta Data": {"2. Symbol": "TEST"},
+					"Time Series (Daily)": {
+						"2025-01-02": {"4. close": "165.00"},
+						"2024-06-03": {"4. close": "60.00"}
+					}
+				}`),
+				earningsResponse:    []byte(`{"symbol": "TEST", "annualEarnings": ` + tt.earnings + `}`),
+				stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+			}
+
+			writer := &mockParquetWriter{}
+			pipeline := NewLynchFairValuePipeline(mockClient, writer)
+			output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+				Ticker:         "TEST",
+				OutputDir:      t.TempDir(),
+				DailyFairValue: algos.DailyFairValueStep,
+				IncludeSignals: true,
+			})
+			if err != nil {
+				t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+			}
+
+			for _, record := range output.CombinedPriceData {
+				if record.Series == types.SeriesDailyFairValue || record.Series == types.SeriesPremiumPct {
+					t.Errorf("Expected no daily fair value days, got %+v", record)
+				}
+			}
+			if len(writer.receivedSignals) != 0 || output.Metadata.PremiumStats != nil {
+				t.Errorf("Expected no signals or premium statistics, got %+v and %+v", writer.receivedSignals, output.Metadata.PremiumStats)
+			}
+			if _, err := os.Stat(output.MetadataFilePath); err != nil {
+				t.Errorf("Expected the metadata to be written, got %v", err)
+			}
+		})
+	}
+}
+
+// Given an unknown daily fair value method, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_InvalidDailyFairValue(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{Ticker: "TEST", DailyFairValue: "cubic"})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, but got: %v", err)
+	}
+}
+
+// Given a close that starts undervalued, recovers, then crosses both bands, verify the crossings land
+// in the signals file and the combined output, and the premium statistics in the metadata.
+func TestLynchFairValuePipeline_RunPipeline_IncludeSignals(t *testing.T) {
+	mockClient := &mockAPIClient{
+		dailyPriceResponse: []byte(`{
+			"Meta Data": {"2. Symbol": "TEST"},
+			"Time Series (Daily)": {
+				"2025-02-03": {"4. close": "195.00"},
+				"2025-01-02": {"4. close": "165.00"},
+				"2024-11-01": {"4. close": "56.25"},
+				"2024-09-03": {"4. close": "75.00"},
+				"2024-06-03": {"4. close": "60.00"}
+			}
+		}`),
+		earningsResponse: []byte(`{
+			"symbol": "TEST",
+			"annualEarnings": [
+				{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"},
+				{"fiscalDateEnding": "2023-12-31", "reportedEPS": "5.00"}
+			]
+		}`),
+		stockSplitsResponse: []byte(`{"symbol": "TEST", "data": []}`),
+	}
+
+	writer := &mockParquetWriter{}
+	pipeline := NewLynchFairValuePipeline(mockClient, writer)
+	output, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:         "TEST",
+		OutputDir:      t.TempDir(),
+		IncludeSignals: true,
+		SignalBands:    ValuationBands{OvervaluedPct: 25},
+		Model:          LynchModelParams{PEOverride: 15},
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	// Step fair values of 75 and 150. 2024-06-03 starts 20% under, which isn't a crossing.
+	expectedSignals := []types.ValuationSignalRecord{
+		{Ticker: "TEST", Date: "2024-11-01", Price: 56.25, FairValuePrice: 75, PremiumPct: -25, Signal: types.SignalUndervalued},
+		{Ticker: "TEST", Date: "2025-02-03", Price: 195, FairValuePrice: 150, PremiumPct: 30, Signal: types.SignalOvervalued},
+	}
+	if diff := cmp.Diff(expectedSignals, writer.receivedSignals, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() signals mismatch (-want +got):\n%s", diff)
+	}
+	if filepath.Base(output.SignalsFilePath) != "TEST_signals.parquet" {
+		t.Errorf("Expected the signals to be written to TEST_signals.parquet, got %s", output.SignalsFilePath)
+	}
+
+	var signalData []types.CombinedPriceRecord
+	for _, record := range output.CombinedPriceData {
+		switch record.Series {
+		case types.SeriesSignalOvervalued, types.SeriesSignalUndervalued:
+			signalData = append(signalData, record)
+		case types.SeriesDailyFairValue:
+			t.Errorf("Expected no daily fair value series without a daily fair value method, got %+v", record)
+		}
+	}
+	expectedMarkers := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2024-11-01", Price: 56.25, Series: types.SeriesSignalUndervalued},
+		{Ticker: "TEST", Date: "2025-02-03", Price: 195, Series: types.SeriesSignalOvervalued},
+	}
+	if diff := cmp.Diff(expectedMarkers, signalData); diff != "" {
+		t.Errorf("RunPipeline() signal markers mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedLog := "TEST crossed into the +25% overvalued or -20% undervalued band 2 times, most recently overvalued on 2025-02-03 at a +30.00% premium"
+	if !slices.Contains(output.Logs, expectedLog) {
+		t.Errorf("Expected logs to contain %q, got %v", expectedLog, output.Logs)
+	}
+
+	stats := output.Metadata.PremiumStats
+	if stats == nil || stats.Days != 5 || stats.LatestDate != "2025-02-03" || math.Abs(stats.LatestRatio-1.3) > 1e-9 {
+		t.Errorf("Expected premium statistics over 5 days ending at a 1.3 ratio, got %+v", stats)
+	}
+	bands := output.Metadata.Inputs.SignalBands
+	if bands == nil || *bands != (ValuationBands{OvervaluedPct: 25, UndervaluedPct: 20}) || output.Metadata.RecordCounts["valuation_signals"] != 2 {
+		t.Errorf("Expected the metadata to record the resolved bands and 2 signals, got %+v", output.Metadata)
+	}
+}
+
+// Given an undervalued band a close can never reach, verify the run fails validation before any fetch.
+func TestLynchFairValuePipeline_RunPipeline_InvalidSignalBands(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewLynchFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), LynchFairValueInputs{
+		Ticker:         "TEST",
+		IncludeSignals: true,
+		SignalBands:    ValuationBands{UndervaluedPct: 120},
+	})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate || !errors.Is(err, ErrInvalidValuationBands) {
+		t.Errorf("Expected a validate stage error for the bands, but got: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/xitongsys/parquet-go-source/local"
+)
+
+// Shared plumbing for pipelines: progress reporting and the files they write. File errors are
+// returned as write stage errors so every pipeline reports them the same way.
+
+// Optional hook for reporting progress to a UI layer as a pipeline moves through its stages.
+type ProgressFunc func(stage Stage, message string)
+
+// Same as ProgressFunc for pipelines working through several tickers, saying which one it's for.
+type TickerProgressFunc func(ticker string, stage Stage, message string)
+
+func (f ProgressFunc) report(stage Stage, message string) {
+	if f != nil {
+		f(stage, message)
+	}
+}
+
+func (f TickerProgressFunc) report(ticker string, stage Stage, message string) {
+	if f != nil {
+		f(ticker, stage, message)
+	}
+}
+
+// Creates outputDir, empty meaning the current working directory. It's the last chance to bail out
+// before anything touches the disk, so a cancelled ctx fails here.
+func prepareOutputDir(ctx context.Context, outputDir string) error {
+	if err := ctx.Err(); err != nil {
+		return &StageError{Stage: StageWrite, Err: err}
+	}
+	if outputDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return stageErrorf(StageWrite, "failed to create output directory '%s': %w", outputDir, err)
+	}
+	return nil
+}
+
+// Creates fileName, hands it to write and closes it. Returns the absolute path of the file and
+// the log message from the writer.
+func writeParquetFile(fileName string, write func(io.WriteCloser) (string, error)) (string, string, error) {
+	fw, err := local.NewLocalFileWriter(fileName)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to create file '%s': %w", fileName, err)
+	}
+	defer fw.Close()
+
+	writeLogMessage, err := write(fw)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to write parquet data: %w", err)
+	}
+
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to get absolute path for '%s': %w", fileName, err)
+	}
+
+	return absPath, writeLogMessage, nil
+}
+
+// Same as writeParquetFile for plain text formats that only need an io.Writer.
+func writeTextFile(fileName string, write func(io.Writer) (string, error)) (string, string, error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to create file '%s': %w", fileName, err)
+	}
+	defer f.Close()
+
+	writeLogMessage, err := write(f)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to write '%s': %w", fileName, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to close '%s': %w", fileName, err)
+	}
+
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", "", stageErrorf(StageWrite, "failed to get absolute path for '%s': %w", fileName, err)
+	}
+
+	return absPath, writeLogMessage, nil
+}
+
+// Output file for a ticker, e.g. AAPL.parquet or AAPL_ohlcv.parquet with a suffix.
+func tickerFileName(outputDir string, ticker string, suffix string) string {
+	if suffix != "" {
+		return filepath.Join(outputDir, fmt.Sprintf("%s_%s.parquet", ticker, suffix))
+	}
+	return filepath.Join(outputDir, fmt.Sprintf("%s.parquet", ticker))
+}
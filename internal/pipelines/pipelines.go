@@ -5,13 +5,24 @@ package pipelines
 // individually in the struct
 
 type Pipelines struct {
-	LynchFairValue FairValuePipeline
+	LynchFairValue        FairValuePipeline
+	LynchFairValueBatch   BatchFairValuePipeline
+	PriceToSalesFairValue PriceToSalesPipeline
+	DCFFairValue          DCFPipeline
+	Dividends             DividendPipeline
+	EarningsCalendar      EarningsCalendarPipeline
 	// Add new pipelines here in the future
 }
 
-func NewPipelines(client APIClient, writer ParquetWriter) *Pipelines {
+func NewPipelines(client APIClient, writer ParquetWriter, calendarWriter CalendarWriter) *Pipelines {
+	lynchFairValue := NewLynchFairValuePipeline(client, writer)
 	return &Pipelines{
-		LynchFairValue: NewLynchFairValuePipeline(client, writer),
+		LynchFairValue:        lynchFairValue,
+		LynchFairValueBatch:   NewLynchBatchRunner(lynchFairValue),
+		PriceToSalesFairValue: NewPriceToSalesFairValuePipeline(client, writer),
+		DCFFairValue:          NewDCFFairValuePipeline(client, writer),
+		Dividends:             NewDividendHistoryPipeline(client, writer),
+		EarningsCalendar:      NewEarningsCalendarGenerator(client, writer, calendarWriter),
 		// Add new pipelines here in the future
 	}
 }
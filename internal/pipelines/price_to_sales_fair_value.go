@@ -0,0 +1,159 @@
+package pipelines
+
+import (
+	"cibo/internal/statistics/algos"
+	"cibo/internal/statistics/parse"
+	"cibo/internal/statistics/utils"
+	"cibo/internal/types"
+	"context"
+	"fmt"
+	"io"
+)
+
+// PriceToSalesFairValuePipeline generates fair value reports from a company's historical
+// price to sales multiple. It mirrors LynchFairValuePipeline, swapping earnings for revenue per
+// share, and writes its fair value under its own series so both can be charted together.
+
+const (
+	PriceToSalesFileSuffix = "ps"
+	// Ten years covers a full market cycle without reaching back to a very different company.
+	DefaultPriceToSalesWindowYears = 10
+)
+
+type PriceToSalesFairValuePipeline struct {
+	apiClient     APIClient
+	parquetWriter ParquetWriter
+}
+
+type PriceToSalesFairValueInputs struct {
+	Ticker    string
+	StartDate string
+	EndDate   string
+	// Where <TICKER>_ps.parquet is written, see prepareOutputDir.
+	OutputDir string
+	// Years of P/S history the multiple is taken over, counted back from the latest fiscal year.
+	// Zero or less uses every year in the date range.
+	WindowYears int
+	// algos.StatisticMedian or algos.StatisticMean. Empty uses the median.
+	Statistic string
+	// Optional, see ProgressFunc.
+	OnProgress ProgressFunc
+}
+
+type PriceToSalesFairValueOutputs struct {
+	RecordCount int
+	FilePath    string
+	// The historical P/S multiple the fair value was calculated with.
+	PriceToSalesMultiple float64
+	CombinedPriceData    []types.CombinedPriceRecord
+	Logs                 []string
+}
+
+func NewPriceToSalesFairValuePipeline(client APIClient, writer ParquetWriter) *PriceToSalesFairValuePipeline {
+	return &PriceToSalesFairValuePipeline{
+		apiClient:     client,
+		parquetWriter: writer,
+	}
+}
+
+func (p *PriceToSalesFairValuePipeline) RunPipeline(ctx context.Context, input PriceToSalesFairValueInputs) (*PriceToSalesFairValueOutputs, error) {
+	if err := utils.ValidateDateRange(input.StartDate, input.EndDate); err != nil {
+		return nil, &StageError{Stage: StageValidate, Err: err}
+	}
+	if input.Statistic != "" && input.Statistic != algos.StatisticMean && input.Statistic != algos.StatisticMedian {
+		return nil, stageErrorf(StageValidate, "unknown statistic '%s', expected '%s' or '%s'",
+			input.Statistic, algos.StatisticMean, algos.StatisticMedian)
+	}
+
+	input.OnProgress.report(StageFetch, fmt.Sprintf("fetching data for %s", input.Ticker))
+	dailyPricesJson, err := p.apiClient.FetchDailyPrice(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("daily prices", err)
+	}
+	incomeStatementJson, err := p.apiClient.FetchIncomeStatement(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("income statement", err)
+	}
+	balanceSheetJson, err := p.apiClient.FetchBalanceSheet(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("balance sheet", err)
+	}
+	stockSplitsJson, err := p.apiClient.FetchStockSplits(ctx, input.Ticker)
+	if err != nil {
+		return nil, fetchError("stock splits", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageParse, Err: err}
+	}
+	input.OnProgress.report(StageParse, "parsing API responses")
+	dailyPricesRecords, err := parse.ParseDailyPricesToFlat(dailyPricesJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "daily prices parsing failed: %w", err)
+	}
+	revenueRecords, err := parse.ParseAnnualRevenueToFlat(incomeStatementJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "income statement parsing failed: %w", err)
+	}
+	sharesRecords, err := parse.ParseSharesOutstandingToFlat(balanceSheetJson, true)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "balance sheet parsing failed: %w", err)
+	}
+	stockSplitRecords, err := parse.ParseStockSplitsToFlat(stockSplitsJson)
+	if err != nil {
+		return nil, stageErrorf(StageParse, "stock splits parsing failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &StageError{Stage: StageCalculate, Err: err}
+	}
+	input.OnProgress.report(StageCalculate, "calculating price to sales fair value history")
+	adjustedDailyPrices := utils.AdjustForStockSplits(dailyPricesRecords, stockSplitRecords)
+	filteredDailyPrices, err := utils.FilterDailyPricesWithinDateRange(adjustedDailyPrices, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter daily prices: %w", err)
+	}
+
+	// Share counts are as reported, so restate them in today's shares to match adjusted prices.
+	adjustedShares := utils.AdjustSharesForStockSplits(sharesRecords, stockSplitRecords)
+	revenuePerShare := algos.RevenuePerShare(revenueRecords, adjustedShares)
+	filteredRevenuePerShare, err := utils.FilterRevenuePerShareWithinDateRange(revenuePerShare, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "failed to filter revenue per share: %w", err)
+	}
+
+	fairValuePriceRecords, multiple, err := algos.CalculatePriceToSalesFairValueHistory(
+		filteredDailyPrices, filteredRevenuePerShare, input.WindowYears, input.Statistic)
+	if err != nil {
+		return nil, stageErrorf(StageCalculate, "could not calculate price to sales fair value: %w", err)
+	}
+
+	combinedData := types.DailyAndFairPriceToCombined(filteredDailyPrices, nil)
+	combinedData = append(combinedData, types.FairValueToCombined(fairValuePriceRecords, types.SeriesFairValuePS)...)
+
+	if err := prepareOutputDir(ctx, input.OutputDir); err != nil {
+		return nil, err
+	}
+
+	fileName := tickerFileName(input.OutputDir, input.Ticker, PriceToSalesFileSuffix)
+	input.OnProgress.report(StageWrite, fmt.Sprintf("writing %d records to %s", len(combinedData), fileName))
+	absPath, writeLogMessage, err := writeParquetFile(fileName, func(fw io.WriteCloser) (string, error) {
+		return p.parquetWriter.WriteCombinedPriceDataToParquet(combinedData, fw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &PriceToSalesFairValueOutputs{
+		RecordCount:          len(filteredDailyPrices),
+		FilePath:             absPath,
+		PriceToSalesMultiple: multiple,
+		CombinedPriceData:    combinedData,
+		Logs: []string{
+			fmt.Sprintf("Historical P/S multiple: %.2f", multiple),
+			writeLogMessage,
+		},
+	}
+
+	return output, nil
+}
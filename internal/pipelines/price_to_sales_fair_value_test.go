@@ -0,0 +1,107 @@
+package pipelines
+
+import (
+	"cibo/internal/types"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newPriceToSalesMockClient() *mockAPIClient {
+	client := newSplitPriceMockClient()
+	client.incomeResponse = []byte(`{
+		"symbol": "TEST",
+		"annualReports": [
+			{"fiscalDateEnding": "2024-12-31", "totalRevenue": "1000"},
+			{"fiscalDateEnding": "2023-12-31", "totalRevenue": "800"}
+		]
+	}`)
+	client.balanceSheetResponse = []byte(`{
+		"symbol": "TEST",
+		"annualReports": [
+			{"fiscalDateEnding": "2024-12-31", "commonStockSharesOutstanding": "100"},
+			{"fiscalDateEnding": "2023-12-31", "commonStockSharesOutstanding": "100"}
+		]
+	}`)
+	return client
+}
+
+// Given a 2-for-1 split after both fiscal years, verify prices and share counts are both restated
+// so revenue per share lines up with adjusted prices, and the fair value is written under its own
+// series to its own file.
+func TestPriceToSalesFairValuePipeline_RunPipeline_Success(t *testing.T) {
+	mockWriter := &mockParquetWriter{}
+	outputDir := t.TempDir()
+
+	pipeline := NewPriceToSalesFairValuePipeline(newPriceToSalesMockClient(), mockWriter)
+	output, err := pipeline.RunPipeline(context.Background(), PriceToSalesFairValueInputs{Ticker: "TEST", OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("RunPipeline() returned an unexpected error: %v", err)
+	}
+
+	// Adjusted shares are 200, so revenue per share is 5 and 4. P/S ratios are 100/5 = 20 and
+	// 90/4 = 22.5, giving a median multiple of 21.25.
+	expectedData := []types.CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-10", Price: 110, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 100, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 106.25, Series: types.SeriesFairValuePS},
+		{Ticker: "TEST", Date: "2024-06-28", Price: 90, Series: types.SeriesDailyPrice},
+		{Ticker: "TEST", Date: "2023-12-31", Price: 85, Series: types.SeriesFairValuePS},
+		{Ticker: "TEST", Date: "2023-12-29", Price: 90, Series: types.SeriesDailyPrice},
+	}
+	sorter := cmpopts.SortSlices(func(a, b types.CombinedPriceRecord) bool {
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		return a.Series < b.Series
+	})
+
+	if diff := cmp.Diff(expectedData, output.CombinedPriceData, sorter, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("RunPipeline() mismatch in CombinedPriceData (-want +got):\n%s", diff)
+	}
+	if output.PriceToSalesMultiple != 21.25 {
+		t.Errorf("Expected a P/S multiple of 21.25, got %v", output.PriceToSalesMultiple)
+	}
+	if diff := cmp.Diff(filepath.Join(outputDir, "TEST_ps.parquet"), output.FilePath); diff != "" {
+		t.Errorf("RunPipeline() FilePath mismatch (-want +got):\n%s", diff)
+	}
+	if !mockWriter.wasCalled {
+		t.Error("Expected WriteCombinedPriceDataToParquet to be called, but it was not")
+	}
+}
+
+// Given an unknown statistic, verify the run fails validation before anything is fetched.
+func TestPriceToSalesFairValuePipeline_RunPipeline_InvalidStatistic(t *testing.T) {
+	mockClient := &mockAPIClient{shouldReturnFetchErr: true}
+
+	pipeline := NewPriceToSalesFairValuePipeline(mockClient, &mockParquetWriter{})
+	_, err := pipeline.RunPipeline(context.Background(), PriceToSalesFairValueInputs{Ticker: "TEST", Statistic: "mode"})
+
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != StageValidate {
+		t.Errorf("Expected a validate stage error, but got: %v", err)
+	}
+}
+
+// Given only one fiscal year of revenue, verify the run fails with an insufficient revenue error.
+func TestPriceToSalesFairValuePipeline_RunPipeline_InsufficientRevenue(t *testing.T) {
+	mockClient := newPriceToSalesMockClient()
+	mockWriter := &mockParquetWriter{}
+
+	pipeline := NewPriceToSalesFairValuePipeline(mockClient, mockWriter)
+	_, err := pipeline.RunPipeline(context.Background(), PriceToSalesFairValueInputs{Ticker: "TEST", StartDate: "2024-01-01"})
+
+	if !errors.Is(err, ErrInsufficientRevenue) {
+		t.Errorf("Expected an insufficient revenue error, but got: %v", err)
+	}
+	if Guidance(err) == "" {
+		t.Error("Expected guidance for an insufficient revenue error")
+	}
+	if mockWriter.wasCalled {
+		t.Error("WriteCombinedPriceDataToParquet should not be called when the calculation fails")
+	}
+}
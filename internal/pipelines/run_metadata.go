@@ -0,0 +1,178 @@
+package pipelines
+
+import (
+	"bytes"
+	"cibo/internal/statistics/algos"
+	"cibo/internal/types"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// A record of what a run did and the single point statistics that don't fit the long price
+// format, written next to the parquet output as <TICKER>_metadata.json and as a key/value
+// <TICKER>_metadata.parquet table with one row per leaf field, e.g. model.fair_value_pe.
+
+const MetadataFileSuffix = "metadata"
+
+type RunMetadata struct {
+	// TICKER_UNIXTIME, unique enough to tell runs of the same ticker apart.
+	RunID       string            `json:"run_id"`
+	Pipeline    string            `json:"pipeline"`
+	CiboVersion string            `json:"cibo_version"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Inputs      RunMetadataInputs `json:"inputs"`
+	Model       LynchModelSummary `json:"model"`
+	// P/E the market paid over the date range, on the EPS known each day. Left out when no day had
+	// positive EPS.
+	MeanPE   float64 `json:"mean_pe,omitempty"`
+	MedianPE float64 `json:"median_pe,omitempty"`
+	// The last day's P/E and the percentage of days at or below it.
+	LatestPE           float64 `json:"latest_pe,omitempty"`
+	LatestPEPercentile float64 `json:"latest_pe_percentile,omitempty"`
+	// Keyed by series name, e.g. pe_ratio_p90.
+	PERatioBands map[string]float64 `json:"pe_ratio_bands,omitempty"`
+	// The close over the daily fair value across the range. Left out without a daily fair value.
+	PremiumStats  *algos.PremiumStatistics `json:"premium_stats,omitempty"`
+	PriceDates    DateRange                `json:"price_dates"`
+	EarningsDates DateRange                `json:"earnings_dates"`
+	// Keyed by what was counted, e.g. daily_prices.
+	RecordCounts map[string]int `json:"record_counts"`
+	// Records dropped while parsing the API responses because they were malformed.
+	SkippedRecords map[string]int `json:"skipped_records"`
+}
+
+// The inputs that change a run's output, without the UI plumbing.
+type RunMetadataInputs struct {
+	Ticker               string `json:"ticker"`
+	StartDate            string `json:"start_date,omitempty"`
+	EndDate              string `json:"end_date,omitempty"`
+	CPIFilePath          string `json:"cpi_file,omitempty"`
+	IncludeOHLCV         bool   `json:"include_ohlcv"`
+	UseTTMEPS            bool   `json:"use_ttm_eps"`
+	ProjectFromEstimates bool   `json:"project_from_estimates"`
+	IncludePERatio       bool   `json:"include_pe_ratio"`
+	IncludePEG           bool   `json:"include_peg"`
+	IncludeGraham        bool   `json:"include_graham"`
+	// Zero when left to the default.
+	AAABondYield float64 `json:"aaa_bond_yield,omitempty"`
+	// Empty when no daily fair value was requested.
+	DailyFairValue string `json:"daily_fair_value,omitempty"`
+	IncludeSignals bool   `json:"include_signals"`
+	// Resolved bands, only set when signals were requested.
+	SignalBands *ValuationBands `json:"signal_bands,omitempty"`
+}
+
+// First and last date of a series, empty when the series is.
+type DateRange struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+func newRunID(ticker string, createdAt time.Time) string {
+	return fmt.Sprintf("%s_%d", ticker, createdAt.Unix())
+}
+
+func dailyPriceDates(records []types.DailyStockRecord) []string {
+	dates := make([]string, len(records))
+	for i, record := range records {
+		dates[i] = record.Date
+	}
+	return dates
+}
+
+func earningsDates(records []types.AnnualEarningRecord) []string {
+	dates := make([]string, len(records))
+	for i, record := range records {
+		dates[i] = record.FiscalDateEnding
+	}
+	return dates
+}
+
+func dateRangeOf(dates []string) DateRange {
+	var dateRange DateRange
+	for _, date := range dates {
+		if dateRange.Start == "" || date < dateRange.Start {
+			dateRange.Start = date
+		}
+		if date > dateRange.End {
+			dateRange.End = date
+		}
+	}
+	return dateRange
+}
+
+/*
+Flattens the metadata into key/value rows sorted by key. Nested fields are joined with dots and
+every value is JSON encoded, so a string keeps its quotes and can't be mistaken for a number.
+*/
+func (m RunMetadata) KeyValues() ([]types.MetadataRecord, error) {
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var tree map[string]any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	var records []types.MetadataRecord
+	var flatten func(prefix string, value any) error
+	flatten = func(prefix string, value any) error {
+		if object, ok := value.(map[string]any); ok {
+			for key, child := range object {
+				if err := flatten(prefix+"."+key, child); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		leaf, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		records = append(records, types.MetadataRecord{Key: prefix[1:], Value: string(leaf)})
+		return nil
+	}
+	if err := flatten("", tree); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	return records, nil
+}
+
+// Writes the JSON and parquet metadata files, returning their absolute paths and write logs.
+func writeRunMetadata(outputDir string, metadata RunMetadata, writer ParquetWriter) (string, string, []string, error) {
+	jsonFileName := filepath.Join(outputDir, fmt.Sprintf("%s_%s.json", metadata.Inputs.Ticker, MetadataFileSuffix))
+	jsonPath, jsonLogMessage, err := writeTextFile(jsonFileName, func(w io.Writer) (string, error) {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(metadata); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Successfully wrote run metadata %s to JSON file", metadata.RunID), nil
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	keyValues, err := metadata.KeyValues()
+	if err != nil {
+		return "", "", nil, stageErrorf(StageWrite, "failed to flatten run metadata: %w", err)
+	}
+	parquetFileName := tickerFileName(outputDir, metadata.Inputs.Ticker, MetadataFileSuffix)
+	parquetPath, parquetLogMessage, err := writeParquetFile(parquetFileName, func(fw io.WriteCloser) (string, error) {
+		return writer.WriteMetadataToParquet(keyValues, fw)
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return jsonPath, parquetPath, []string{jsonLogMessage, parquetLogMessage}, nil
+}
@@ -0,0 +1,138 @@
+package algos
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Shiller's cyclically adjusted P/E (CAPE) compares today's price to ten years of earnings rather
+than one, with each year's earnings restated in today's dollars so inflation doesn't make old
+profits look small. Averaging over a full business cycle smooths out booms and busts that make a
+plain P/E swing around.
+
+For a trading day t:
+
+	RealEPS(q)     = EPS(q) * CPI(t) / CPI(q)                    for each quarter q in (t - 10 years, t]
+	AverageRealEPS = Mean(RealEPS) * 4                           annualized
+	CAPE(t)        = ClosingPrice(t) / AverageRealEPS
+	FairValue(t)   = Median(CAPE over the whole series) * AverageRealEPS(t)
+
+Quarters only count once they've been reported, so a day never sees earnings the market didn't
+know about yet. The fair value assumes the company trades back to its own typical CAPE.
+*/
+
+var ErrInsufficientCAPEHistory = errors.New("not enough quarterly earnings history for a ten year CAPE")
+
+const (
+	capeYears = 10
+	// Quarters needed in the ten year window. A few short of 40 so one or two quarters missing from
+	// Alpha Vantage's history doesn't blank out years of the series.
+	capeMinQuarters = 36
+)
+
+type realEarningsQuarter struct {
+	fiscalDate string
+	// The day the quarter became public, the fiscal date when the report date isn't known.
+	availableDate string
+	eps           float64
+	cpi           float64
+}
+
+/*
+Calculates CAPE and the CAPE implied fair value for every daily price that has ten years of
+reported quarters before it and positive average earnings. Returns the records in the order of
+dailyPrices along with the median CAPE the fair values were taken from.
+*/
+func CalculateCAPEHistory(
+	dailyPrices []types.DailyStockRecord,
+	quarterlyEarnings []types.QuarterlyEarningRecord,
+	cpi []types.CPIRecord,
+) ([]types.CAPERecord, float64, error) {
+	sortedCPI := make([]types.CPIRecord, len(cpi))
+	copy(sortedCPI, cpi)
+	sort.Slice(sortedCPI, func(i, j int) bool { return sortedCPI[i].Date < sortedCPI[j].Date })
+
+	quarters := make([]realEarningsQuarter, 0, len(quarterlyEarnings))
+	for _, earnings := range quarterlyEarnings {
+		quarterCPI, ok := cpiOnOrBefore(sortedCPI, earnings.FiscalDateEnding)
+		if !ok {
+			continue
+		}
+		available := earnings.ReportedDate
+		if available == "" {
+			available = earnings.FiscalDateEnding
+		}
+		quarters = append(quarters, realEarningsQuarter{
+			fiscalDate:    earnings.FiscalDateEnding,
+			availableDate: available,
+			eps:           earnings.ReportedEPS,
+			cpi:           quarterCPI,
+		})
+	}
+
+	records := make([]types.CAPERecord, 0, len(dailyPrices))
+	for _, price := range dailyPrices {
+		date, err := time.Parse("2006-01-02", price.Date)
+		if err != nil || price.ClosingPrice <= 0 {
+			continue
+		}
+		todayCPI, ok := cpiOnOrBefore(sortedCPI, price.Date)
+		if !ok {
+			continue
+		}
+		windowStart := date.AddDate(-capeYears, 0, 0).Format("2006-01-02")
+
+		realEPSSum := 0.0
+		count := 0
+		for _, quarter := range quarters {
+			if quarter.fiscalDate > windowStart && quarter.availableDate <= price.Date {
+				realEPSSum += quarter.eps * todayCPI / quarter.cpi
+				count++
+			}
+		}
+		if count < capeMinQuarters {
+			continue
+		}
+
+		averageRealEPS := realEPSSum / float64(count) * 4
+		// A negative decade of earnings has no meaningful multiple.
+		if averageRealEPS <= 0 {
+			continue
+		}
+
+		records = append(records, types.CAPERecord{
+			Ticker:         price.Ticker,
+			Date:           price.Date,
+			Ratio:          price.ClosingPrice / averageRealEPS,
+			AverageRealEPS: averageRealEPS,
+		})
+	}
+
+	if len(records) == 0 {
+		return nil, 0, ErrInsufficientCAPEHistory
+	}
+
+	ratios := make([]float64, len(records))
+	for i, record := range records {
+		ratios[i] = record.Ratio
+	}
+	medianCAPE := Median(ratios)
+	for i := range records {
+		records[i].FairValuePrice = medianCAPE * records[i].AverageRealEPS
+	}
+
+	return records, medianCAPE, nil
+}
+
+// The latest CPI reading on or before date. sortedCPI must be oldest first.
+func cpiOnOrBefore(sortedCPI []types.CPIRecord, date string) (float64, bool) {
+	i := sort.Search(len(sortedCPI), func(i int) bool { return sortedCPI[i].Date > date })
+	if i == 0 {
+		return 0, false
+	}
+	return sortedCPI[i-1].Value, true
+}
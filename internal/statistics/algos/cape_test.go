@@ -0,0 +1,72 @@
+package algos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Ten years of quarters from 2010 through 2019, each reported 30 days after the quarter ends.
+func tenYearsOfQuarters(eps float64) []types.QuarterlyEarningRecord {
+	quarters := make([]types.QuarterlyEarningRecord, 0, 40)
+	for year := 2010; year <= 2019; year++ {
+		for month := time.March; month <= time.December; month += 3 {
+			fiscal := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			quarters = append(quarters, types.QuarterlyEarningRecord{
+				Ticker:           "TEST",
+				FiscalDateEnding: fiscal.Format("2006-01-02"),
+				ReportedDate:     fiscal.AddDate(0, 0, 30).Format("2006-01-02"),
+				ReportedEPS:      eps,
+			})
+		}
+	}
+	return quarters
+}
+
+var mockCPI = []types.CPIRecord{
+	{Date: "2020-01-01", Value: 200},
+	{Date: "2000-01-01", Value: 100},
+}
+
+// Given a decade of flat earnings and prices before and after the CPI doubles, verify old earnings
+// are restated in the day's dollars, unreported quarters are left out, days with too little
+// history are skipped and fair value comes from the median CAPE.
+func TestCalculateCAPEHistory(t *testing.T) {
+	prices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2012-01-03", ClosingPrice: 20}, // Only two years of quarters
+		{Ticker: "TEST", Date: "2019-12-31", ClosingPrice: 60}, // Q4 2019 not reported yet
+		{Ticker: "TEST", Date: "2020-02-14", ClosingPrice: 80},
+	}
+
+	expected := []types.CAPERecord{
+		{Ticker: "TEST", Date: "2019-12-31", Ratio: 15, AverageRealEPS: 4, FairValuePrice: 50},
+		{Ticker: "TEST", Date: "2020-02-14", Ratio: 10, AverageRealEPS: 8, FairValuePrice: 100},
+	}
+
+	result, medianCAPE, err := CalculateCAPEHistory(prices, tenYearsOfQuarters(1), mockCPI)
+	if err != nil {
+		t.Fatalf("CalculateCAPEHistory() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CalculateCAPEHistory() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(12.5, medianCAPE, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CalculateCAPEHistory() median mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a decade of losses, verify no CAPE is produced rather than a negative multiple.
+func TestCalculateCAPEHistory_NegativeEarnings(t *testing.T) {
+	prices := []types.DailyStockRecord{{Ticker: "TEST", Date: "2020-02-14", ClosingPrice: 80}}
+
+	_, _, err := CalculateCAPEHistory(prices, tenYearsOfQuarters(-1), mockCPI)
+
+	if !errors.Is(err, ErrInsufficientCAPEHistory) {
+		t.Errorf("Expected ErrInsufficientCAPEHistory, but got: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package algos
+
+import (
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+The fair value carried onto every trading day, so it can be read off against the close on any day
+instead of only at fiscal year ends, along with the premium the market paid over it:
+
+	Premium(t) = (ClosingPrice(t) / FairValue(t) − 1) × 100
+
+Three ways to fill in the days:
+
+	step    each fiscal year's fair value holds until the next one
+	linear  straight lines between fiscal year ends, holding flat after the last one
+	ttm     TTM EPS known on the day × the fair value P/E, moving on every quarterly report
+
+step and linear are dated by fiscal year end like the yearly points they come from, and linear draws
+towards a value that isn't known until the next year ends, so both suit the chart better than a
+backtest. ttm only uses reports out by each day, the same point in time EPS as the daily P/E.
+Days before the first fair value, or without a positive one, have no point. Flat or shrinking EPS
+give the model a P/E of zero or below, and there's no premium over a fair value like that.
+*/
+
+const (
+	DailyFairValueStep   = "step"
+	DailyFairValueLinear = "linear"
+	DailyFairValueTTM    = "ttm"
+)
+
+func ValidDailyFairValueMethod(method string) bool {
+	return method == DailyFairValueStep || method == DailyFairValueLinear || method == DailyFairValueTTM
+}
+
+func dailyFairValueRecord(price types.DailyStockRecord, fairValue float64) types.DailyFairValueRecord {
+	return types.DailyFairValueRecord{
+		Ticker:         price.Ticker,
+		Date:           price.Date,
+		Price:          price.ClosingPrice,
+		FairValuePrice: fairValue,
+		PremiumPct:     (price.ClosingPrice/fairValue - 1) * 100,
+	}
+}
+
+/*
+Carries yearly fair values onto each of dailyPrices by step or linear, see above. Returns the records
+in the order of dailyPrices.
+*/
+func InterpolateDailyFairValue(
+	dailyPrices []types.DailyStockRecord,
+	fairValues []types.FairValuePriceRecord,
+	method string,
+) []types.DailyFairValueRecord {
+	sorted := make([]types.FairValuePriceRecord, len(fairValues))
+	copy(sorted, fairValues)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	records := make([]types.DailyFairValueRecord, 0, len(dailyPrices))
+	for _, price := range dailyPrices {
+		i := sort.Search(len(sorted), func(i int) bool { return sorted[i].Date > price.Date }) - 1
+		if i < 0 || price.ClosingPrice <= 0 {
+			continue
+		}
+		fairValue := sorted[i].FairValuePrice
+		if method == DailyFairValueLinear && i+1 < len(sorted) {
+			fairValue = interpolate(sorted[i], sorted[i+1], price.Date)
+		}
+		if fairValue <= 0 {
+			continue
+		}
+		records = append(records, dailyFairValueRecord(price, fairValue))
+	}
+	return records
+}
+
+// The fair value on date along the line from before to after. Falls back to before on a bad date.
+func interpolate(before, after types.FairValuePriceRecord, date string) float64 {
+	start, errStart := time.Parse("2006-01-02", before.Date)
+	end, errEnd := time.Parse("2006-01-02", after.Date)
+	day, errDay := time.Parse("2006-01-02", date)
+	if errStart != nil || errEnd != nil || errDay != nil || !end.After(start) {
+		return before.FairValuePrice
+	}
+	progress := day.Sub(start).Hours() / end.Sub(start).Hours()
+	return before.FairValuePrice + (after.FairValuePrice-before.FairValuePrice)*progress
+}
+
+/*
+The fair value P/E applied to the TTM EPS known on each of dailyPrices, with quarterly giving both
+the TTM EPS and when each quarter was reported. Returns the records in the order of dailyPrices.
+*/
+func TTMDailyFairValue(
+	dailyPrices []types.DailyStockRecord,
+	quarterly []types.QuarterlyEarningRecord,
+	fairValuePE float64,
+) []types.DailyFairValueRecord {
+	known := knownEarnings(TrailingTwelveMonthEPS(quarterly), quarterly)
+
+	records := make([]types.DailyFairValueRecord, 0, len(dailyPrices))
+	for _, price := range dailyPrices {
+		i := latestKnownEPS(known, price.Date)
+		if i < 0 || price.ClosingPrice <= 0 {
+			continue
+		}
+		fairValue := known[i].earning.ReportedEPS * fairValuePE
+		if fairValue <= 0 {
+			continue
+		}
+		records = append(records, dailyFairValueRecord(price, fairValue))
+	}
+	return records
+}
+
+// The most recent daily fair value, false when there are none.
+func LatestDailyFairValue(records []types.DailyFairValueRecord) (types.DailyFairValueRecord, bool) {
+	if len(records) == 0 {
+		return types.DailyFairValueRecord{}, false
+	}
+	latest := records[0]
+	for _, record := range records {
+		if record.Date > latest.Date {
+			latest = record
+		}
+	}
+	return latest, true
+}
@@ -0,0 +1,117 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var dailyFairValuePrices = []types.DailyStockRecord{
+	{Ticker: "TEST", Date: "2025-01-10", ClosingPrice: 220},
+	{Ticker: "TEST", Date: "2024-07-01", ClosingPrice: 150},
+	{Ticker: "TEST", Date: "2023-12-31", ClosingPrice: 110},
+	{Ticker: "TEST", Date: "2023-06-01", ClosingPrice: 90},
+}
+
+var yearlyFairValues = []types.FairValuePriceRecord{
+	{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 200},
+	{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: 100},
+}
+
+// Given two yearly fair values, verify each method fills in the days between them, holds the last
+// one flat and leaves the days before the first without a point.
+func TestInterpolateDailyFairValue(t *testing.T) {
+	tests := []struct {
+		method   string
+		expected []types.DailyFairValueRecord
+	}{
+		{DailyFairValueStep, []types.DailyFairValueRecord{
+			{Ticker: "TEST", Date: "2025-01-10", Price: 220, FairValuePrice: 200, PremiumPct: 10},
+			{Ticker: "TEST", Date: "2024-07-01", Price: 150, FairValuePrice: 100, PremiumPct: 50},
+			{Ticker: "TEST", Date: "2023-12-31", Price: 110, FairValuePrice: 100, PremiumPct: 10},
+		}},
+		// 2024-07-01 is 183 of the 366 days between the two fiscal year ends.
+		{DailyFairValueLinear, []types.DailyFairValueRecord{
+			{Ticker: "TEST", Date: "2025-01-10", Price: 220, FairValuePrice: 200, PremiumPct: 10},
+			{Ticker: "TEST", Date: "2024-07-01", Price: 150, FairValuePrice: 150, PremiumPct: 0},
+			{Ticker: "TEST", Date: "2023-12-31", Price: 110, FairValuePrice: 100, PremiumPct: 10},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result := InterpolateDailyFairValue(dailyFairValuePrices, yearlyFairValues, tt.method)
+			if diff := cmp.Diff(tt.expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("InterpolateDailyFairValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Given the zero fair values of flat EPS and the negative ones of shrinking EPS, verify those days
+// have no point, while a linear line climbing out of them has one once it turns positive.
+func TestInterpolateDailyFairValue_NonPositiveFairValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		fairValues []types.FairValuePriceRecord
+		method     string
+		expected   []types.DailyFairValueRecord
+	}{
+		{"flat", []types.FairValuePriceRecord{
+			{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 0},
+			{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: 0},
+		}, DailyFairValueStep, []types.DailyFairValueRecord{}},
+		{"declining", []types.FairValuePriceRecord{
+			{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: -50},
+			{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: -20},
+		}, DailyFairValueLinear, []types.DailyFairValueRecord{}},
+		// Halfway from -100 to 300 is 100 on 2024-07-01.
+		{"recovering", []types.FairValuePriceRecord{
+			{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 300},
+			{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: -100},
+		}, DailyFairValueLinear, []types.DailyFairValueRecord{
+			{Ticker: "TEST", Date: "2025-01-10", Price: 220, FairValuePrice: 300, PremiumPct: 220.0/300*100 - 100},
+			{Ticker: "TEST", Date: "2024-07-01", Price: 150, FairValuePrice: 100, PremiumPct: 50},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := InterpolateDailyFairValue(dailyFairValuePrices, tt.fairValues, tt.method)
+			if diff := cmp.Diff(tt.expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("InterpolateDailyFairValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Given quarterly reports, verify each day is valued on the TTM EPS reported by then.
+func TestTTMDailyFairValue(t *testing.T) {
+	dailyPrices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2025-05-01", ClosingPrice: 120},
+		{Ticker: "TEST", Date: "2025-02-03", ClosingPrice: 100},
+		{Ticker: "TEST", Date: "2025-01-10", ClosingPrice: 100},
+	}
+	quarterly := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2025-03-31", ReportedDate: "2025-04-25", ReportedEPS: 2},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedDate: "2025-01-30", ReportedEPS: 1},
+		{Ticker: "TEST", FiscalDateEnding: "2024-09-30", ReportedDate: "2024-10-30", ReportedEPS: 1},
+		{Ticker: "TEST", FiscalDateEnding: "2024-06-30", ReportedDate: "2024-07-30", ReportedEPS: 1},
+		{Ticker: "TEST", FiscalDateEnding: "2024-03-31", ReportedDate: "2024-04-30", ReportedEPS: 1},
+	}
+
+	// TTM EPS is 4 once the 2024 year end is reported and 5 after the next quarter. 2025-01-10 is
+	// before the first full year was reported.
+	expected := []types.DailyFairValueRecord{
+		{Ticker: "TEST", Date: "2025-05-01", Price: 120, FairValuePrice: 100, PremiumPct: 20},
+		{Ticker: "TEST", Date: "2025-02-03", Price: 100, FairValuePrice: 80, PremiumPct: 25},
+	}
+
+	result := TTMDailyFairValue(dailyPrices, quarterly, 20)
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("TTMDailyFairValue() mismatch (-want +got):\n%s", diff)
+	}
+}
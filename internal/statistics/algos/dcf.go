@@ -0,0 +1,236 @@
+package algos
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"cibo/internal/types"
+)
+
+/*
+Discounted cash flow (DCF) fair value. Where the Lynch and P/S models ask what the market has
+usually paid for a company, this one asks what the cash it generates is worth today, so it doesn't
+lean on the market having been right before.
+
+Two stage model on free cash flow (FCF) per share, operating cash flow minus capital expenditures:
+
+	FCF(k) = FCF(0) × (1 + g)^k                      for k = 1..N
+	Terminal Value = FCF(N) × (1 + gT) / (r − gT)
+	Fair Value Price = Σ FCF(k) / (1 + r)^k + Terminal Value / (1 + r)^N
+
+r is the discount rate, gT the terminal growth and N the horizon in years, all user inputs
+(DCFParams). g is the growth over the horizon, the CAGR of FCF per share known by each fiscal
+year, clamped between gT and DCFMaxGrowth since a couple of strong years would otherwise compound
+into an absurd value. When it can't be measured, e.g. the first year or a negative starting year,
+the horizon grows at gT too.
+
+Years with zero or negative FCF have no value, there's nothing to discount. The terminal value is
+usually most of the result, which is why the sensitivity grid varies r and gT rather than g.
+*/
+
+const (
+	DefaultDCFDiscountRate   = 10.0
+	DefaultDCFTerminalGrowth = 2.5
+	DefaultDCFHorizonYears   = 10
+	// Cap on the measured horizon growth, as a fraction.
+	DCFMaxGrowth = 0.20
+)
+
+var (
+	// No fiscal year with positive free cash flow per share to value.
+	ErrInsufficientCashFlow = errors.New("insufficient free cash flow history")
+	// The DCF parameters can't be used together or are out of range.
+	ErrInvalidDCFParams = errors.New("invalid DCF parameters")
+)
+
+/*
+User knobs for the DCF model, rates in percent, e.g. 10 for 10%. A zero DiscountRate or
+HorizonYears uses the default, a zero TerminalGrowth means no growth after the horizon.
+*/
+type DCFParams struct {
+	DiscountRate   float64 `json:"discount_rate"`
+	TerminalGrowth float64 `json:"terminal_growth"`
+	HorizonYears   int     `json:"horizon_years"`
+}
+
+// Fills in the defaults for zero values.
+func (p DCFParams) Resolve() DCFParams {
+	if p.DiscountRate == 0 {
+		p.DiscountRate = DefaultDCFDiscountRate
+	}
+	if p.HorizonYears == 0 {
+		p.HorizonYears = DefaultDCFHorizonYears
+	}
+	return p
+}
+
+// Validates resolved parameters.
+func (p DCFParams) Validate() error {
+	switch {
+	case p.DiscountRate <= 0:
+		return fmt.Errorf("%w: discount rate must be positive, got %.2f", ErrInvalidDCFParams, p.DiscountRate)
+	case p.HorizonYears < 0:
+		return fmt.Errorf("%w: horizon must be a positive number of years, got %d", ErrInvalidDCFParams, p.HorizonYears)
+	case p.TerminalGrowth >= p.DiscountRate:
+		return fmt.Errorf("%w: terminal growth %.2f must be below the discount rate %.2f",
+			ErrInvalidDCFParams, p.TerminalGrowth, p.DiscountRate)
+	}
+	return nil
+}
+
+// How the latest fiscal year was valued, so the fair value can be traced back to its inputs.
+type DCFSummary struct {
+	DCFParams
+	FiscalDateEnding     string  `json:"fiscal_date_ending"`
+	FreeCashFlowPerShare float64 `json:"free_cash_flow_per_share"`
+	// Horizon growth as a fraction, after clamping.
+	Growth    float64 `json:"growth"`
+	FairValue float64 `json:"fair_value"`
+}
+
+/*
+Joins free cash flow and shares outstanding on fiscal date. Years missing either side, or with no
+shares, are skipped since a per share number can't be made for them.
+*/
+func FreeCashFlowPerShare(flows []types.FreeCashFlowRecord, shares []types.SharesOutstandingRecord) []types.FreeCashFlowPerShareRecord {
+	sharesByDate := make(map[string]float64, len(shares))
+	for _, record := range shares {
+		sharesByDate[record.FiscalDateEnding] = record.SharesOutstanding
+	}
+
+	var perShare []types.FreeCashFlowPerShareRecord
+	for _, flow := range flows {
+		shareCount, ok := sharesByDate[flow.FiscalDateEnding]
+		if !ok || shareCount <= 0 {
+			continue
+		}
+		perShare = append(perShare, types.FreeCashFlowPerShareRecord{
+			Ticker:               flow.Ticker,
+			FiscalDateEnding:     flow.FiscalDateEnding,
+			FreeCashFlowPerShare: flow.FreeCashFlow / shareCount,
+		})
+	}
+
+	sort.Slice(perShare, func(i, j int) bool {
+		return perShare[i].FiscalDateEnding < perShare[j].FiscalDateEnding
+	})
+
+	return perShare
+}
+
+// Present value of the two stage model for one year's FCF per share. params must be resolved,
+// growth is a fraction.
+func DCFValue(freeCashFlowPerShare, growth float64, params DCFParams) float64 {
+	r := params.DiscountRate / 100
+	terminalGrowth := params.TerminalGrowth / 100
+
+	value := 0.0
+	flow := freeCashFlowPerShare
+	for k := 1; k <= params.HorizonYears; k++ {
+		flow *= 1 + growth
+		value += flow / math.Pow(1+r, float64(k))
+	}
+	terminalValue := flow * (1 + terminalGrowth) / (r - terminalGrowth)
+	return value + terminalValue/math.Pow(1+r, float64(params.HorizonYears))
+}
+
+/*
+Horizon growth for the last year of history, the CAGR of FCF per share from its first positive year
+clamped between the terminal growth and DCFMaxGrowth. history must be sorted by date and end on a
+positive year. The CAGR is measured the same way as the Lynch model's on EPS.
+*/
+func dcfGrowth(history []types.FreeCashFlowPerShareRecord, params DCFParams) float64 {
+	floor := params.TerminalGrowth / 100
+	end := history[len(history)-1]
+	for _, start := range history {
+		if start.FreeCashFlowPerShare <= 0 {
+			continue
+		}
+		cagr, ok := compoundGrowth(
+			datedValue{date: start.FiscalDateEnding, value: start.FreeCashFlowPerShare},
+			datedValue{date: end.FiscalDateEnding, value: end.FreeCashFlowPerShare},
+		)
+		if !ok || math.IsNaN(cagr) {
+			return floor
+		}
+		return min(max(cagr, floor), max(DCFMaxGrowth, floor))
+	}
+	return floor
+}
+
+/*
+Calculates the DCF fair value for every fiscal year with positive FCF per share, each one growing
+at the rate measured from the years up to it. Returns the summary of the latest valued year.
+*/
+func CalculateDCFFairValueHistory(
+	freeCashFlowPerShare []types.FreeCashFlowPerShareRecord,
+	params DCFParams,
+) ([]types.FairValuePriceRecord, DCFSummary, error) {
+	params = params.Resolve()
+	if err := params.Validate(); err != nil {
+		return nil, DCFSummary{}, err
+	}
+
+	history := make([]types.FreeCashFlowPerShareRecord, len(freeCashFlowPerShare))
+	copy(history, freeCashFlowPerShare)
+	sort.Slice(history, func(i, j int) bool { return history[i].FiscalDateEnding < history[j].FiscalDateEnding })
+
+	var fairValueHistory []types.FairValuePriceRecord
+	summary := DCFSummary{DCFParams: params}
+	for i, record := range history {
+		if record.FreeCashFlowPerShare <= 0 {
+			continue
+		}
+		growth := dcfGrowth(history[:i+1], params)
+		fairValue := DCFValue(record.FreeCashFlowPerShare, growth, params)
+		fairValueHistory = append(fairValueHistory, types.FairValuePriceRecord{
+			Ticker:         record.Ticker,
+			FairValuePrice: fairValue,
+			Date:           record.FiscalDateEnding,
+		})
+		summary.FiscalDateEnding = record.FiscalDateEnding
+		summary.FreeCashFlowPerShare = record.FreeCashFlowPerShare
+		summary.Growth = growth
+		summary.FairValue = fairValue
+	}
+
+	if len(fairValueHistory) == 0 {
+		return nil, DCFSummary{}, fmt.Errorf("%w: no fiscal year with positive free cash flow per share", ErrInsufficientCashFlow)
+	}
+	return fairValueHistory, summary, nil
+}
+
+// Discount rate and terminal growth offsets of the sensitivity grid, in percentage points.
+var (
+	dcfSensitivityDiscountSteps = []float64{-2, -1, 0, 1, 2}
+	dcfSensitivityGrowthSteps   = []float64{-1, -0.5, 0, 0.5, 1}
+)
+
+/*
+Revalues the latest year of summary at discount rates and terminal growths around the ones it was
+run with, keeping its horizon growth. Cells where the terminal growth isn't below the discount rate
+have no value and are left out.
+*/
+func DCFSensitivity(ticker string, summary DCFSummary) []types.DCFSensitivityRecord {
+	var grid []types.DCFSensitivityRecord
+	for _, discountStep := range dcfSensitivityDiscountSteps {
+		for _, growthStep := range dcfSensitivityGrowthSteps {
+			params := summary.DCFParams
+			params.DiscountRate += discountStep
+			params.TerminalGrowth += growthStep
+			if params.Validate() != nil {
+				continue
+			}
+			grid = append(grid, types.DCFSensitivityRecord{
+				Ticker:           ticker,
+				FiscalDateEnding: summary.FiscalDateEnding,
+				DiscountRate:     params.DiscountRate,
+				TerminalGrowth:   params.TerminalGrowth,
+				FairValuePrice:   DCFValue(summary.FreeCashFlowPerShare, summary.Growth, params),
+			})
+		}
+	}
+	return grid
+}
@@ -0,0 +1,130 @@
+package algos
+
+import (
+	"errors"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given no growth at all, verify the value is the plain perpetuity FCF / r whatever the horizon, and
+// given horizon growth, verify each year and the terminal value are discounted separately.
+func TestDCFValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fcf      float64
+		growth   float64
+		params   DCFParams
+		expected float64
+	}{
+		{"perpetuity", 10, 0, DCFParams{DiscountRate: 10, HorizonYears: 10}, 100},
+		// 11 / 1.1 + 12.1 / 1.21 for the horizon, then 12.1 / 0.1 discounted two years.
+		{"two stage", 10, 0.10, DCFParams{DiscountRate: 10, HorizonYears: 2}, 120},
+		// The terminal value grows, 10 × 1.02 / (0.1 − 0.02) discounted a year, plus the year itself.
+		{"terminal growth", 10, 0, DCFParams{DiscountRate: 10, TerminalGrowth: 2, HorizonYears: 1}, 10/1.1 + 127.5/1.1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := DCFValue(tc.fcf, tc.growth, tc.params)
+			if diff := cmp.Diff(tc.expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("DCFValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Given a first year, a negative year and a year after fast growth, verify the first year grows at the
+// terminal rate, the negative year has no value and the fast growth is capped.
+func TestCalculateDCFFairValueHistory(t *testing.T) {
+	history := []types.FreeCashFlowPerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", FreeCashFlowPerShare: 8},
+		{Ticker: "TEST", FiscalDateEnding: "2021-12-31", FreeCashFlowPerShare: -1},
+		{Ticker: "TEST", FiscalDateEnding: "2020-12-31", FreeCashFlowPerShare: 2},
+	}
+	params := DCFParams{TerminalGrowth: 2.5}
+	resolved := DCFParams{DiscountRate: 10, TerminalGrowth: 2.5, HorizonYears: 10}
+
+	expected := []types.FairValuePriceRecord{
+		{Ticker: "TEST", Date: "2020-12-31", FairValuePrice: DCFValue(2, 0.025, resolved)},
+		// 2 to 8 over four years is a 41% CAGR, capped to 20%.
+		{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: DCFValue(8, DCFMaxGrowth, resolved)},
+	}
+	expectedSummary := DCFSummary{
+		DCFParams:            resolved,
+		FiscalDateEnding:     "2024-12-31",
+		FreeCashFlowPerShare: 8,
+		Growth:               DCFMaxGrowth,
+		FairValue:            DCFValue(8, DCFMaxGrowth, resolved),
+	}
+
+	result, summary, err := CalculateDCFFairValueHistory(history, params)
+	if err != nil {
+		t.Fatalf("CalculateDCFFairValueHistory() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CalculateDCFFairValueHistory() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedSummary, summary, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CalculateDCFFairValueHistory() summary mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given parameters the model can't value with or no positive cash flow, verify the matching error.
+func TestCalculateDCFFairValueHistory_Errors(t *testing.T) {
+	positive := []types.FreeCashFlowPerShareRecord{{Ticker: "TEST", FiscalDateEnding: "2024-12-31", FreeCashFlowPerShare: 8}}
+	negative := []types.FreeCashFlowPerShareRecord{{Ticker: "TEST", FiscalDateEnding: "2024-12-31", FreeCashFlowPerShare: -8}}
+
+	testCases := []struct {
+		name     string
+		history  []types.FreeCashFlowPerShareRecord
+		params   DCFParams
+		expected error
+	}{
+		{"terminal growth at the discount rate", positive, DCFParams{DiscountRate: 5, TerminalGrowth: 5}, ErrInvalidDCFParams},
+		{"negative discount rate", positive, DCFParams{DiscountRate: -1}, ErrInvalidDCFParams},
+		{"negative horizon", positive, DCFParams{HorizonYears: -1}, ErrInvalidDCFParams},
+		{"no positive cash flow", negative, DCFParams{}, ErrInsufficientCashFlow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := CalculateDCFFairValueHistory(tc.history, tc.params)
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// Given the default parameters, verify the grid is complete with the run's own value in the middle,
+// and given a discount rate near the terminal growth, verify the cells it can't value are left out.
+func TestDCFSensitivity(t *testing.T) {
+	summary := DCFSummary{
+		DCFParams:            DCFParams{DiscountRate: 10, TerminalGrowth: 2.5, HorizonYears: 10},
+		FiscalDateEnding:     "2024-12-31",
+		FreeCashFlowPerShare: 8,
+		Growth:               0.05,
+	}
+	summary.FairValue = DCFValue(8, 0.05, summary.DCFParams)
+
+	grid := DCFSensitivity("TEST", summary)
+	if len(grid) != 25 {
+		t.Fatalf("Expected a 5 × 5 grid, got %d cells", len(grid))
+	}
+	center := grid[12]
+	expectedCenter := types.DCFSensitivityRecord{
+		Ticker: "TEST", FiscalDateEnding: "2024-12-31", DiscountRate: 10, TerminalGrowth: 2.5, FairValuePrice: summary.FairValue,
+	}
+	if diff := cmp.Diff(expectedCenter, center, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("DCFSensitivity() center cell mismatch (-want +got):\n%s", diff)
+	}
+
+	summary.DCFParams = DCFParams{DiscountRate: 3, TerminalGrowth: 2, HorizonYears: 10}
+	if grid := DCFSensitivity("TEST", summary); len(grid) != 16 {
+		t.Errorf("Expected 16 cells with the terminal growth below the discount rate, got %d", len(grid))
+	}
+}
@@ -0,0 +1,170 @@
+package algos
+
+import (
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Dividend history statistics. All amounts are expected to be split adjusted the same way as the
+prices they are compared against.
+
+Trailing twelve month (TTM) yield for a given trading day:
+
+	Yield(t) = Sum of dividends with an ex-dividend date in (t - 1 year, t] / ClosingPrice(t)
+
+The ex-dividend date is used rather than the payment date since that's the day the price drops
+by the dividend, which is what a buyer on that day is actually paying for.
+*/
+
+type DividendFrequency string
+
+const (
+	FrequencyMonthly    DividendFrequency = "monthly"
+	FrequencyQuarterly  DividendFrequency = "quarterly"
+	FrequencySemiAnnual DividendFrequency = "semi_annual"
+	FrequencyAnnual     DividendFrequency = "annual"
+	FrequencyIrregular  DividendFrequency = "irregular"
+	// Fewer than two dividends, so there is no gap to measure.
+	FrequencyNone DividendFrequency = "none"
+)
+
+// How many of the most recent dividends the schedule is detected from. Two years of quarterly
+// payments, recent enough to pick up a company that changed its schedule.
+const dividendScheduleLookback = 8
+
+type DividendSchedule struct {
+	Frequency DividendFrequency
+	// Median days between the recent ex-dividend dates the frequency was detected from.
+	IntervalDays int
+}
+
+/*
+Calculates the trailing twelve month dividend yield for every daily price. Days with no dividend
+in the trailing year get a yield of 0. Inputs can be in any order, the output follows dailyPrices.
+*/
+func TrailingTwelveMonthYield(dailyPrices []types.DailyStockRecord, dividends []types.DividendRecord) []types.DividendYieldRecord {
+	yields := make([]types.DividendYieldRecord, 0, len(dailyPrices))
+	for _, price := range dailyPrices {
+		if price.ClosingPrice <= 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", price.Date)
+		if err != nil {
+			continue
+		}
+		yearAgo := date.AddDate(-1, 0, 0).Format("2006-01-02")
+
+		trailingDividends := 0.0
+		for _, dividend := range dividends {
+			if dividend.ExDividendDate > yearAgo && dividend.ExDividendDate <= price.Date {
+				trailingDividends += dividend.Amount
+			}
+		}
+
+		yields = append(yields, types.DividendYieldRecord{
+			Ticker: price.Ticker,
+			Date:   price.Date,
+			Yield:  trailingDividends / price.ClosingPrice,
+		})
+	}
+	return yields
+}
+
+/*
+Detects how often a company pays dividends from the median gap between its most recent ex-dividend
+dates. The median keeps a single special dividend from throwing off a regular schedule.
+*/
+func DetectDividendSchedule(dividends []types.DividendRecord) DividendSchedule {
+	dates := sortedExDividendDates(dividends)
+	if len(dates) > dividendScheduleLookback {
+		dates = dates[len(dates)-dividendScheduleLookback:]
+	}
+	if len(dates) < 2 {
+		return DividendSchedule{Frequency: FrequencyNone}
+	}
+
+	gaps := make([]float64, 0, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		gaps = append(gaps, dates[i].Sub(dates[i-1]).Hours()/24)
+	}
+	interval := int(Median(gaps) + 0.5)
+
+	// Ranges are wide since ex-dividend dates drift by a few weeks from year to year.
+	var frequency DividendFrequency
+	switch {
+	case interval <= 45:
+		frequency = FrequencyMonthly
+	case interval <= 135:
+		frequency = FrequencyQuarterly
+	case interval <= 250:
+		frequency = FrequencySemiAnnual
+	case interval <= 420:
+		frequency = FrequencyAnnual
+	default:
+		frequency = FrequencyIrregular
+	}
+
+	return DividendSchedule{Frequency: frequency, IntervalDays: interval}
+}
+
+/*
+Finds the next dividend that hasn't been paid yet as of the given date. Companies declare dividends
+weeks ahead, so this is the one a buyer today could still collect or is about to receive.
+*/
+func UpcomingDividend(dividends []types.DividendRecord, asOf string) (types.DividendRecord, bool) {
+	var upcoming types.DividendRecord
+	found := false
+	for _, dividend := range dividends {
+		// Some declarations don't have a payment date yet.
+		date := dividend.PaymentDate
+		if date == "" {
+			date = dividend.ExDividendDate
+		}
+		if date < asOf {
+			continue
+		}
+		if !found || dividend.ExDividendDate < upcoming.ExDividendDate {
+			upcoming = dividend
+			found = true
+		}
+	}
+	return upcoming, found
+}
+
+/*
+Estimates the next ex-dividend date by stepping the latest one forward by the schedule's interval.
+Returns false for companies without a regular schedule, or whose estimate is already in the past
+as of the given date, which usually means the dividend was suspended.
+*/
+func EstimateNextExDividendDate(dividends []types.DividendRecord, schedule DividendSchedule, asOf string) (string, bool) {
+	if schedule.Frequency == FrequencyNone || schedule.Frequency == FrequencyIrregular {
+		return "", false
+	}
+	dates := sortedExDividendDates(dividends)
+	if len(dates) == 0 {
+		return "", false
+	}
+
+	estimate := dates[len(dates)-1].AddDate(0, 0, schedule.IntervalDays).Format("2006-01-02")
+	if estimate < asOf {
+		return "", false
+	}
+	return estimate, true
+}
+
+// Parsed ex-dividend dates, oldest first. Records without a usable date are skipped.
+func sortedExDividendDates(dividends []types.DividendRecord) []time.Time {
+	dates := make([]time.Time, 0, len(dividends))
+	for _, dividend := range dividends {
+		date, err := time.Parse("2006-01-02", dividend.ExDividendDate)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
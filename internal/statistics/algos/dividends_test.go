@@ -0,0 +1,114 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var mockQuarterlyDividends = []types.DividendRecord{
+	{Ticker: "TEST", ExDividendDate: "2024-11-08", PaymentDate: "2024-12-10", Amount: 0.5},
+	{Ticker: "TEST", ExDividendDate: "2024-02-09", PaymentDate: "2024-03-10", Amount: 0.5},
+	{Ticker: "TEST", ExDividendDate: "2024-08-09", PaymentDate: "2024-09-10", Amount: 0.5},
+	{Ticker: "TEST", ExDividendDate: "2024-05-10", PaymentDate: "2024-06-10", Amount: 0.5},
+}
+
+// Given prices on and around ex-dividend dates, verify the trailing window includes the day itself,
+// excludes dividends exactly a year old and skips days without a usable price.
+func TestTrailingTwelveMonthYield(t *testing.T) {
+	prices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2024-02-08", ClosingPrice: 50},
+		{Ticker: "TEST", Date: "2024-02-09", ClosingPrice: 50},
+		{Ticker: "TEST", Date: "2024-12-31", ClosingPrice: 100},
+		{Ticker: "TEST", Date: "2025-02-09", ClosingPrice: 75},
+		{Ticker: "TEST", Date: "2025-02-10", ClosingPrice: 0}, // No price to divide by
+	}
+
+	expected := []types.DividendYieldRecord{
+		{Ticker: "TEST", Date: "2024-02-08", Yield: 0},
+		{Ticker: "TEST", Date: "2024-02-09", Yield: 0.01},
+		{Ticker: "TEST", Date: "2024-12-31", Yield: 0.02},
+		{Ticker: "TEST", Date: "2025-02-09", Yield: 0.02},
+	}
+
+	result := TrailingTwelveMonthYield(prices, mockQuarterlyDividends)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("TrailingTwelveMonthYield() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given dividend histories on different schedules, verify the frequency is detected from the
+// median gap, so a single special dividend doesn't change the answer.
+func TestDetectDividendSchedule(t *testing.T) {
+	dividendsOn := func(dates ...string) []types.DividendRecord {
+		dividends := make([]types.DividendRecord, len(dates))
+		for i, date := range dates {
+			dividends[i] = types.DividendRecord{ExDividendDate: date}
+		}
+		return dividends
+	}
+
+	tests := []struct {
+		name      string
+		dividends []types.DividendRecord
+		expected  DividendSchedule
+	}{
+		{"quarterly", mockQuarterlyDividends, DividendSchedule{Frequency: FrequencyQuarterly, IntervalDays: 91}},
+		{"quarterly with a special dividend", dividendsOn("2024-02-09", "2024-05-10", "2024-06-14", "2024-08-09", "2024-11-08", "2025-02-07"),
+			DividendSchedule{Frequency: FrequencyQuarterly, IntervalDays: 91}},
+		{"monthly", dividendsOn("2024-01-15", "2024-02-15", "2024-03-15"), DividendSchedule{Frequency: FrequencyMonthly, IntervalDays: 30}},
+		{"semi annual", dividendsOn("2023-03-01", "2023-09-01", "2024-03-01"), DividendSchedule{Frequency: FrequencySemiAnnual, IntervalDays: 183}},
+		{"annual", dividendsOn("2022-06-01", "2023-06-01", "2024-06-01"), DividendSchedule{Frequency: FrequencyAnnual, IntervalDays: 366}},
+		{"irregular", dividendsOn("2018-06-01", "2020-06-01", "2023-06-01"), DividendSchedule{Frequency: FrequencyIrregular, IntervalDays: 913}},
+		{"single dividend", dividendsOn("2024-06-01"), DividendSchedule{Frequency: FrequencyNone}},
+		{"no dividends", nil, DividendSchedule{Frequency: FrequencyNone}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, DetectDividendSchedule(tt.dividends)); diff != "" {
+				t.Errorf("DetectDividendSchedule() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Given a declared dividend without a payment date yet, verify it's found as upcoming and that
+// nothing is upcoming once every dividend has been paid.
+func TestUpcomingDividend(t *testing.T) {
+	declared := types.DividendRecord{Ticker: "TEST", ExDividendDate: "2025-02-07", DeclarationDate: "2025-01-25", Amount: 0.5}
+	dividends := append([]types.DividendRecord{declared}, mockQuarterlyDividends...)
+
+	upcoming, ok := UpcomingDividend(dividends, "2025-01-30")
+	if !ok {
+		t.Fatal("Expected an upcoming dividend")
+	}
+	if diff := cmp.Diff(declared, upcoming); diff != "" {
+		t.Errorf("UpcomingDividend() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := UpcomingDividend(mockQuarterlyDividends, "2025-01-30"); ok {
+		t.Error("Expected no upcoming dividend when every dividend has been paid")
+	}
+}
+
+// Given a quarterly schedule, verify the next ex-dividend date is one interval after the latest,
+// and that no estimate is made once that date has passed.
+func TestEstimateNextExDividendDate(t *testing.T) {
+	schedule := DetectDividendSchedule(mockQuarterlyDividends)
+
+	estimate, ok := EstimateNextExDividendDate(mockQuarterlyDividends, schedule, "2024-12-01")
+	if !ok || estimate != "2025-02-07" {
+		t.Errorf("Expected an estimate of 2025-02-07, got %q (found: %v)", estimate, ok)
+	}
+	if _, ok := EstimateNextExDividendDate(mockQuarterlyDividends, schedule, "2025-06-01"); ok {
+		t.Error("Expected no estimate for a schedule that has lapsed")
+	}
+	if _, ok := EstimateNextExDividendDate(mockQuarterlyDividends, DividendSchedule{Frequency: FrequencyIrregular}, "2024-12-01"); ok {
+		t.Error("Expected no estimate for an irregular schedule")
+	}
+}
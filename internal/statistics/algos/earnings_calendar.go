@@ -0,0 +1,128 @@
+package algos
+
+import (
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Upcoming earnings report dates. The Alpha Vantage calendar is the source of truth when it has a
+date, the company's own report history fills in what the calendar leaves out:
+
+  - Report time (pre or post market), taken as the most common time over the last year of reports.
+  - The next report date itself when the calendar has nothing scheduled yet, projected 13 weeks
+    after the latest report. Companies tend to report on the same weekday each quarter, and 13
+    weeks lands on the same weekday.
+*/
+
+// Reports looked back over when deciding the usual report time, one year of quarters.
+const reportTimeLookback = 4
+
+// Weeks between quarterly reports when estimating the next report date.
+const quarterWeeks = 13
+
+/*
+Builds the upcoming earnings events for one company as of the given date. Calendar events before
+asOf are dropped. When the calendar has no upcoming events an estimate from history is used
+instead, if the company has reported recently enough for one to make sense.
+*/
+func ScheduleEarningsEvents(
+	calendar []types.EarningsEventRecord,
+	history []types.QuarterlyEarningRecord,
+	asOf string,
+) []types.EarningsEventRecord {
+	reportTime := TypicalReportTime(history)
+
+	events := make([]types.EarningsEventRecord, 0, len(calendar))
+	for _, event := range calendar {
+		if event.ReportDate < asOf {
+			continue
+		}
+		if event.ReportTime == "" {
+			event.ReportTime = reportTime
+		}
+		events = append(events, event)
+	}
+
+	if len(events) == 0 {
+		if estimate, ok := EstimateNextEarningsEvent(history, asOf); ok {
+			events = append(events, estimate)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ReportDate < events[j].ReportDate })
+	return events
+}
+
+// The report time the company used most over its latest reports, empty if it's never been
+// reported. Ties go to the most recent report's time.
+func TypicalReportTime(history []types.QuarterlyEarningRecord) string {
+	recent := reportsNewestFirst(history)
+	if len(recent) > reportTimeLookback {
+		recent = recent[:reportTimeLookback]
+	}
+
+	counts := make(map[string]int)
+	typical := ""
+	for _, report := range recent {
+		if report.ReportTime == "" {
+			continue
+		}
+		counts[report.ReportTime]++
+		if typical == "" || counts[report.ReportTime] > counts[typical] {
+			typical = report.ReportTime
+		}
+	}
+	return typical
+}
+
+/*
+Projects the next earnings report 13 weeks after the latest one, for the quarter after the
+latest fiscal quarter. Returns false when there's no history, or when the projection is already
+in the past as of the given date, which means the company has missed a quarter or stopped
+reporting and a guess would be misleading.
+*/
+func EstimateNextEarningsEvent(history []types.QuarterlyEarningRecord, asOf string) (types.EarningsEventRecord, bool) {
+	recent := reportsNewestFirst(history)
+	if len(recent) == 0 {
+		return types.EarningsEventRecord{}, false
+	}
+	latest := recent[0]
+
+	reportedDate, err := time.Parse("2006-01-02", latest.ReportedDate)
+	if err != nil {
+		return types.EarningsEventRecord{}, false
+	}
+	nextReport := reportedDate.AddDate(0, 0, 7*quarterWeeks).Format("2006-01-02")
+	if nextReport < asOf {
+		return types.EarningsEventRecord{}, false
+	}
+
+	nextFiscal := ""
+	if fiscalDate, err := time.Parse("2006-01-02", latest.FiscalDateEnding); err == nil {
+		// Last day of the month three months on, day 0 of a month is the last day of the one before.
+		nextFiscal = time.Date(fiscalDate.Year(), fiscalDate.Month()+4, 0, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+
+	return types.EarningsEventRecord{
+		Ticker:           latest.Ticker,
+		ReportDate:       nextReport,
+		FiscalDateEnding: nextFiscal,
+		ReportTime:       TypicalReportTime(history),
+		Source:           types.EarningsSourceEstimated,
+	}, true
+}
+
+// Reports with a known report date, newest first.
+func reportsNewestFirst(history []types.QuarterlyEarningRecord) []types.QuarterlyEarningRecord {
+	reports := make([]types.QuarterlyEarningRecord, 0, len(history))
+	for _, report := range history {
+		if report.ReportedDate != "" {
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ReportedDate > reports[j].ReportedDate })
+	return reports
+}
@@ -0,0 +1,73 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var mockReportHistory = []types.QuarterlyEarningRecord{
+	{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedDate: "2025-01-30", ReportTime: "pre-market"},
+	{Ticker: "TEST", FiscalDateEnding: "2025-06-30", ReportedDate: "2025-07-24", ReportTime: "post-market"},
+	{Ticker: "TEST", FiscalDateEnding: "2025-03-31", ReportedDate: "2025-04-24", ReportTime: "post-market"},
+	{Ticker: "TEST", FiscalDateEnding: "2024-09-30", ReportedDate: "2024-10-24", ReportTime: "pre-market"},
+	{Ticker: "TEST", FiscalDateEnding: "2024-06-30", ReportedDate: "2024-07-25", ReportTime: "pre-market"},
+}
+
+// Given a company that moved from pre to post market reporting, verify only the last year of
+// reports counts and ties go to the most recent report.
+func TestTypicalReportTime(t *testing.T) {
+	if got := TypicalReportTime(mockReportHistory); got != "post-market" {
+		t.Errorf("Expected post-market, got %q", got)
+	}
+	if got := TypicalReportTime(nil); got != "" {
+		t.Errorf("Expected no report time without history, got %q", got)
+	}
+}
+
+// Given a report history, verify the next report is projected 13 weeks on for the next fiscal
+// quarter, and not at all once that projection has passed.
+func TestEstimateNextEarningsEvent(t *testing.T) {
+	expected := types.EarningsEventRecord{
+		Ticker:           "TEST",
+		ReportDate:       "2025-10-23",
+		FiscalDateEnding: "2025-09-30",
+		ReportTime:       "post-market",
+		Source:           types.EarningsSourceEstimated,
+	}
+
+	estimate, ok := EstimateNextEarningsEvent(mockReportHistory, "2025-10-01")
+	if !ok {
+		t.Fatal("Expected an estimate")
+	}
+	if diff := cmp.Diff(expected, estimate); diff != "" {
+		t.Errorf("EstimateNextEarningsEvent() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := EstimateNextEarningsEvent(mockReportHistory, "2025-12-01"); ok {
+		t.Error("Expected no estimate once the projected date has passed")
+	}
+}
+
+// Given calendar events either side of the as of date, verify past ones are dropped, missing
+// report times are filled in from history and no estimate is added.
+func TestScheduleEarningsEvents(t *testing.T) {
+	calendar := []types.EarningsEventRecord{
+		{Ticker: "TEST", ReportDate: "2026-01-29", Source: types.EarningsSourceCalendar},
+		{Ticker: "TEST", ReportDate: "2025-10-23", ReportTime: "pre-market", Source: types.EarningsSourceCalendar},
+		{Ticker: "TEST", ReportDate: "2025-07-24", Source: types.EarningsSourceCalendar},
+	}
+
+	expected := []types.EarningsEventRecord{
+		{Ticker: "TEST", ReportDate: "2025-10-23", ReportTime: "pre-market", Source: types.EarningsSourceCalendar},
+		{Ticker: "TEST", ReportDate: "2026-01-29", ReportTime: "post-market", Source: types.EarningsSourceCalendar},
+	}
+
+	result := ScheduleEarningsEvents(calendar, mockReportHistory, "2025-10-01")
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("ScheduleEarningsEvents() mismatch (-want +got):\n%s", diff)
+	}
+}
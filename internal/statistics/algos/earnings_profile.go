@@ -0,0 +1,146 @@
+package algos
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+The Lynch model assumes steady growth, which only fits some companies. Sorting a history into one
+of four shapes says whether the fair value can be trusted and how to value it:
+
+	profitable    every year made money and EPS never fell by half from a peak
+	turnaround    losses only before the first profitable year, the CAGR starts after them
+	cyclical      a loss or a halving of EPS after the company had been profitable
+	unprofitable  no profitable year, or a cyclical still losing money averaged over the cycle
+
+A cyclical's EPS at the peak or trough of its cycle says little about its earning power, so it is
+valued on normalized EPS instead, each year's average over the trailing DefaultNormalizationYears.
+The fair value line then follows the cycle average rather than every swing, and the CAGR is measured
+between averages rather than between a trough and a peak.
+*/
+
+const (
+	EarningsProfitable   = "profitable"
+	EarningsTurnaround   = "turnaround"
+	EarningsCyclical     = "cyclical"
+	EarningsUnprofitable = "unprofitable"
+
+	DefaultNormalizationYears = 5
+	// Fall from a previous peak EPS, as a fraction, that marks a company as cyclical.
+	cyclicalDrawdown = 0.5
+)
+
+// How a company's earnings history was classified.
+type EarningsProfile struct {
+	Class string `json:"class"`
+	// Fiscal dates with zero or negative EPS.
+	LossYears []string `json:"loss_years,omitempty"`
+	// Years normalized EPS is averaged over, only set for cyclicals.
+	NormalizationYears int `json:"normalization_years,omitempty"`
+}
+
+func sortedByFiscalDate(earnings []types.AnnualEarningRecord) []types.AnnualEarningRecord {
+	sorted := make([]types.AnnualEarningRecord, len(earnings))
+	copy(sorted, earnings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FiscalDateEnding < sorted[j].FiscalDateEnding })
+	return sorted
+}
+
+// Sorts an earnings history into one of the four classes above.
+func ClassifyEarnings(earnings []types.AnnualEarningRecord) EarningsProfile {
+	sorted := sortedByFiscalDate(earnings)
+
+	var profile EarningsProfile
+	firstProfit := -1
+	cyclical := false
+	peak := 0.0
+	for i, earning := range sorted {
+		if earning.ReportedEPS <= 0 {
+			profile.LossYears = append(profile.LossYears, earning.FiscalDateEnding)
+			if firstProfit >= 0 {
+				cyclical = true
+			}
+			continue
+		}
+		if firstProfit < 0 {
+			firstProfit = i
+		}
+		if earning.ReportedEPS < peak*(1-cyclicalDrawdown) {
+			cyclical = true
+		}
+		peak = max(peak, earning.ReportedEPS)
+	}
+
+	switch {
+	case firstProfit < 0:
+		profile.Class = EarningsUnprofitable
+	case cyclical:
+		normalized := NormalizedEPS(sorted, DefaultNormalizationYears)
+		if normalized[len(normalized)-1].ReportedEPS <= 0 {
+			profile.Class = EarningsUnprofitable
+		} else {
+			profile.Class = EarningsCyclical
+			profile.NormalizationYears = DefaultNormalizationYears
+		}
+	case firstProfit > 0:
+		profile.Class = EarningsTurnaround
+	default:
+		profile.Class = EarningsProfitable
+	}
+	return profile
+}
+
+// e.g. "cyclical with 2 loss years, valued on 5 year average EPS"
+func (p EarningsProfile) String() string {
+	switch p.Class {
+	case EarningsCyclical:
+		return fmt.Sprintf("cyclical with %d loss years, valued on %d year average EPS", len(p.LossYears), p.NormalizationYears)
+	case EarningsTurnaround:
+		return fmt.Sprintf("a turnaround after %d loss years", len(p.LossYears))
+	case EarningsUnprofitable:
+		return fmt.Sprintf("unprofitable with %d loss years", len(p.LossYears))
+	default:
+		return "consistently profitable"
+	}
+}
+
+/*
+Replaces each EPS with its average over the given number of years up to and including it, losses
+included. Early years average over what history there is. Returns the records sorted by date.
+*/
+func NormalizedEPS(earnings []types.AnnualEarningRecord, years int) []types.AnnualEarningRecord {
+	sorted := sortedByFiscalDate(earnings)
+
+	normalized := make([]types.AnnualEarningRecord, len(sorted))
+	for i, earning := range sorted {
+		// An unparseable date averages over everything before it.
+		windowStart := ""
+		if date, err := time.Parse("2006-01-02", earning.FiscalDateEnding); err == nil {
+			windowStart = date.AddDate(-years, 0, 0).Format("2006-01-02")
+		}
+		var window []float64
+		for _, previous := range sorted[:i+1] {
+			if previous.FiscalDateEnding > windowStart {
+				window = append(window, previous.ReportedEPS)
+			}
+		}
+		normalized[i] = earning
+		normalized[i].ReportedEPS = Mean(window)
+	}
+	return normalized
+}
+
+// Fiscal dates FairValuePriceHistory leaves without a fair value, because EPS wasn't positive.
+func DroppedFairValueYears(earnings []types.AnnualEarningRecord) []string {
+	var dropped []string
+	for _, earning := range sortedByFiscalDate(earnings) {
+		if earning.ReportedEPS <= 0 {
+			dropped = append(dropped, earning.FiscalDateEnding)
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,100 @@
+package algos
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func earningsOf(eps ...float64) []types.AnnualEarningRecord {
+	dates := []string{"2017-12-31", "2018-12-31", "2019-12-31", "2020-12-31", "2021-12-31", "2022-12-31", "2023-12-31", "2024-12-31"}
+	earnings := make([]types.AnnualEarningRecord, len(eps))
+	for i, value := range eps {
+		earnings[i] = types.AnnualEarningRecord{Ticker: "TEST", FiscalDateEnding: dates[i], ReportedEPS: value}
+	}
+	return earnings
+}
+
+// Given a history of each shape, verify it is classified accordingly with its loss years.
+func TestClassifyEarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		earnings []types.AnnualEarningRecord
+		expected EarningsProfile
+	}{
+		{"steady growth", earningsOf(1, 1.2, 1.1, 1.5, 2), EarningsProfile{Class: EarningsProfitable}},
+		{"early losses", mockEarnings, EarningsProfile{Class: EarningsTurnaround, LossYears: []string{"2017-12-31", "2018-12-31"}}},
+		{"loss after profits", earningsOf(3, 4, -1, 2, 5), EarningsProfile{
+			Class: EarningsCyclical, LossYears: []string{"2019-12-31"}, NormalizationYears: DefaultNormalizationYears,
+		}},
+		{"halved without a loss", earningsOf(4, 6, 2, 5), EarningsProfile{Class: EarningsCyclical, NormalizationYears: DefaultNormalizationYears}},
+		{"never profitable", earningsOf(-2, -1, -0.5), EarningsProfile{
+			Class: EarningsUnprofitable, LossYears: []string{"2017-12-31", "2018-12-31", "2019-12-31"},
+		}},
+		{"losing over the cycle", earningsOf(1, -3, -4, 0.5), EarningsProfile{
+			Class: EarningsUnprofitable, LossYears: []string{"2018-12-31", "2019-12-31"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, ClassifyEarnings(tt.earnings)); diff != "" {
+				t.Errorf("ClassifyEarnings() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Given more years than the window, verify each year averages only the window up to it, losses included.
+func TestNormalizedEPS(t *testing.T) {
+	normalized := NormalizedEPS(earningsOf(2, 4, -3, 5), 2)
+
+	expected := earningsOf(2, 3, 0.5, 1)
+	if diff := cmp.Diff(expected, normalized, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("NormalizedEPS() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a cyclical with a loss in its last year, verify classifying values it on normalized EPS
+// instead of failing, and the years still without a fair value are reported.
+func TestCalculateFairValueHistory_ClassifyCyclical(t *testing.T) {
+	earnings := earningsOf(-2, 3, 4, 5, 6, -1)
+
+	if _, _, _, err := CalculateFairValueHistory(earnings, LynchModelParams{}); !errors.Is(err, ErrNegativeEarnings) {
+		t.Fatalf("Expected the plain model to fail on the latest loss, got: %v", err)
+	}
+
+	history, modelEarnings, summary, err := CalculateFairValueHistory(earnings, LynchModelParams{ClassifyEarnings: true})
+	if err != nil {
+		t.Fatalf("CalculateFairValueHistory() returned an unexpected error: %v", err)
+	}
+	if summary.EarningsProfile == nil || summary.EarningsProfile.Class != EarningsCyclical {
+		t.Fatalf("Expected a cyclical classification, got %+v", summary.EarningsProfile)
+	}
+	// Normalized EPS is -2, 0.5, 5/3, 2.5, 3.2 and 3.4, so only the first year has no fair value.
+	if diff := cmp.Diff([]string{"2017-12-31"}, summary.DroppedYears); diff != "" {
+		t.Errorf("CalculateFairValueHistory() DroppedYears mismatch (-want +got):\n%s", diff)
+	}
+	if len(history) != 5 {
+		t.Errorf("Expected 5 fair values, got %d", len(history))
+	}
+	if len(modelEarnings) != 6 || math.Abs(modelEarnings[5].ReportedEPS-3.4) > 1e-9 {
+		t.Errorf("Expected the normalized EPS back, ending at 3.4, got %+v", modelEarnings)
+	}
+	if summary.CAGRStartDate != "2018-12-31" || summary.CAGREndDate != "2022-12-31" {
+		t.Errorf("Expected the CAGR between normalized 2018 and 2022, got %s to %s", summary.CAGRStartDate, summary.CAGREndDate)
+	}
+}
+
+// Given a company that never made money, verify classifying fails with the classification.
+func TestCalculateFairValueHistory_ClassifyUnprofitable(t *testing.T) {
+	_, _, _, err := CalculateFairValueHistory(earningsOf(-2, -1, -0.5), LynchModelParams{ClassifyEarnings: true})
+	if !errors.Is(err, ErrNegativeEarnings) {
+		t.Errorf("Expected ErrNegativeEarnings, got: %v", err)
+	}
+}
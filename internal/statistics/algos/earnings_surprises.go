@@ -0,0 +1,58 @@
+package algos
+
+import (
+	"math"
+	"sort"
+
+	"cibo/internal/types"
+)
+
+/*
+Beat, miss or inline for each past quarterly report, from Alpha Vantage's consensus estimate and
+surprise. Reports are called inline when the surprise rounds to zero cents, the way "met estimates"
+is reported in the news. Anything larger counts, since for a company earning cents a share a
+penny is a real miss.
+*/
+
+// Surprises smaller than half a cent round to a match.
+const inlineSurpriseThreshold = 0.005
+
+/*
+Classifies every reported quarter that had an estimate, oldest first. Quarters without a report
+date are left out since a marker needs the day the news came out, as are quarters with no
+estimate, which Alpha Vantage leaves as zero estimate and zero surprise.
+*/
+func ClassifyEarningsSurprises(quarterly []types.QuarterlyEarningRecord) []types.EarningsSurpriseRecord {
+	surprises := make([]types.EarningsSurpriseRecord, 0, len(quarterly))
+	for _, quarter := range quarterly {
+		if quarter.ReportedDate == "" {
+			continue
+		}
+		if quarter.EstimatedEPS == 0 && quarter.Surprise == 0 {
+			continue
+		}
+
+		result := types.EarningsInline
+		if math.Abs(quarter.Surprise) >= inlineSurpriseThreshold {
+			if quarter.Surprise > 0 {
+				result = types.EarningsBeat
+			} else {
+				result = types.EarningsMiss
+			}
+		}
+
+		surprises = append(surprises, types.EarningsSurpriseRecord{
+			Ticker:             quarter.Ticker,
+			ReportedDate:       quarter.ReportedDate,
+			FiscalDateEnding:   quarter.FiscalDateEnding,
+			ReportedEPS:        quarter.ReportedEPS,
+			EstimatedEPS:       quarter.EstimatedEPS,
+			Surprise:           quarter.Surprise,
+			SurprisePercentage: quarter.SurprisePercentage,
+			Result:             result,
+		})
+	}
+
+	sort.Slice(surprises, func(i, j int) bool { return surprises[i].ReportedDate < surprises[j].ReportedDate })
+	return surprises
+}
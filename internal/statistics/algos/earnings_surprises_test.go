@@ -0,0 +1,36 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Given quarters newest first with a beat, a miss, a match to the cent and quarters missing an
+// estimate or report date, verify the results are classified oldest first and the rest left out.
+func TestClassifyEarningsSurprises(t *testing.T) {
+	quarterly := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2025-06-30", ReportedDate: "2025-07-23", ReportedEPS: 2.8, EstimatedEPS: 2.5, Surprise: 0.3, SurprisePercentage: 12},
+		{Ticker: "TEST", FiscalDateEnding: "2025-03-31", ReportedDate: "2025-04-24", ReportedEPS: 1.9, EstimatedEPS: 2, Surprise: -0.1, SurprisePercentage: -5},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedDate: "2025-01-29", ReportedEPS: 2, EstimatedEPS: 2.001, Surprise: -0.001, SurprisePercentage: -0.05},
+		{Ticker: "TEST", FiscalDateEnding: "2024-09-30", ReportedDate: "", ReportedEPS: 1.5, EstimatedEPS: 1.4, Surprise: 0.1, SurprisePercentage: 7.1},
+		{Ticker: "TEST", FiscalDateEnding: "1999-06-30", ReportedDate: "1999-07-20", ReportedEPS: 0.4},
+	}
+
+	expected := []types.EarningsSurpriseRecord{
+		{Ticker: "TEST", ReportedDate: "2025-01-29", FiscalDateEnding: "2024-12-31", ReportedEPS: 2, EstimatedEPS: 2.001,
+			Surprise: -0.001, SurprisePercentage: -0.05, Result: types.EarningsInline},
+		{Ticker: "TEST", ReportedDate: "2025-04-24", FiscalDateEnding: "2025-03-31", ReportedEPS: 1.9, EstimatedEPS: 2,
+			Surprise: -0.1, SurprisePercentage: -5, Result: types.EarningsMiss},
+		{Ticker: "TEST", ReportedDate: "2025-07-23", FiscalDateEnding: "2025-06-30", ReportedEPS: 2.8, EstimatedEPS: 2.5,
+			Surprise: 0.3, SurprisePercentage: 12, Result: types.EarningsBeat},
+	}
+
+	result := ClassifyEarningsSurprises(quarterly)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("ClassifyEarningsSurprises() mismatch (-want +got):\n%s", diff)
+	}
+}
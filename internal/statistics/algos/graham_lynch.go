@@ -1,6 +1,7 @@
 package algos
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -18,10 +19,10 @@ future earnings.
 Fair Value Price = Earnings Per Share (EPS) × Fair Value P/E Ratio
 
 The number of years chosen for calculating the growth rate will wildly effect
-the Compound Annual Growth Rate, it is likely this should be setup as a user
-input in the system to run different simulations. Current (the last few years)
-growth compared to multi decade history might or might not be desired depending
-on context.
+the Compound Annual Growth Rate, so it's a user input (LynchModelParams) for running
+different simulations. Current (the last few years) growth compared to multi decade
+history might or might not be desired depending on context. The same goes for the
+resulting P/E, which can be clamped to sane bounds or replaced outright.
 
 https://www.investopedia.com/terms/p/pegyratio.asp
 
@@ -44,16 +45,78 @@ represent a good buying opportunity.
 Remember. All models are wrong but some are useful.
 */
 
+var (
+	// Not enough earnings history to measure growth over.
+	ErrInsufficientEarnings = errors.New("insufficient earnings history")
+	// Earnings are negative where the growth calculation needs them positive.
+	ErrNegativeEarnings = errors.New("negative earnings")
+	// The model parameters can't be used together or are out of range.
+	ErrInvalidModelParams = errors.New("invalid Lynch model parameters")
+)
+
+/*
+User knobs for the Lynch model. The zero value is the plain model described above, CAGR over
+every year of earnings given and the fair value P/E taken straight from it.
+*/
+type LynchModelParams struct {
+	// Years back from the latest earnings the CAGR is measured over. Zero uses all of them.
+	CAGRYears int `json:"cagr_years,omitempty"`
+	// Fair value P/E used as is instead of deriving one from CAGR. Zero derives it.
+	PEOverride float64 `json:"pe_override,omitempty"`
+	// Bounds the CAGR derived P/E is clamped to, zero leaves that side open. Not applied to
+	// PEOverride, which is taken as given.
+	MinPE float64 `json:"min_pe,omitempty"`
+	MaxPE float64 `json:"max_pe,omitempty"`
+	// How growth is measured, GrowthMethodEndpoints or GrowthMethodRegression. Empty uses endpoints.
+	GrowthMethod string `json:"growth_method,omitempty"`
+	// Classify the earnings history first, valuing cyclicals on normalized EPS and failing
+	// unprofitable companies with their classification instead of a bare CAGR error.
+	ClassifyEarnings bool `json:"classify_earnings,omitempty"`
+}
+
+func (p LynchModelParams) Validate() error {
+	switch {
+	case p.CAGRYears < 0:
+		return fmt.Errorf("%w: CAGR window must be a positive number of years, got %d", ErrInvalidModelParams, p.CAGRYears)
+	case p.PEOverride < 0 || p.MinPE < 0 || p.MaxPE < 0:
+		return fmt.Errorf("%w: P/E values can't be negative", ErrInvalidModelParams)
+	case p.MaxPE > 0 && p.MinPE > p.MaxPE:
+		return fmt.Errorf("%w: minimum P/E %.2f is above the maximum %.2f", ErrInvalidModelParams, p.MinPE, p.MaxPE)
+	case p.GrowthMethod != "" && p.GrowthMethod != GrowthMethodEndpoints && p.GrowthMethod != GrowthMethodRegression:
+		return fmt.Errorf("%w: growth method must be '%s' or '%s', got '%s'",
+			ErrInvalidModelParams, GrowthMethodEndpoints, GrowthMethodRegression, p.GrowthMethod)
+	}
+	return nil
+}
+
+// How the model was actually run, so a fair value curve can be traced back to where it came from.
+type LynchModelSummary struct {
+	LynchModelParams
+	// Zero when PEOverride was used, since no growth rate was needed.
+	CAGR          float64 `json:"cagr"`
+	CAGRStartDate string  `json:"cagr_start_date,omitempty"`
+	CAGREndDate   string  `json:"cagr_end_date,omitempty"`
+	FairValuePE   float64 `json:"fair_value_pe"`
+	// True when the derived P/E hit MinPE or MaxPE.
+	Clamped bool `json:"clamped,omitempty"`
+	// Only set when the CAGR came from a regression.
+	GrowthFit *GrowthFit `json:"growth_fit,omitempty"`
+	// Only set when ClassifyEarnings was requested.
+	EarningsProfile *EarningsProfile `json:"earnings_profile,omitempty"`
+	// Fiscal dates left without a fair value because their EPS, normalized for cyclicals, wasn't positive.
+	DroppedYears []string `json:"dropped_years,omitempty"`
+}
+
 /*
 Function to find the starting and ending positive EPS values from a collection of annual earnings data.
 CAGR only works on stable, profitable growth companies, so this function removes early unprofitable years
-of data and then boldly assumes all other years will be positive. Handling of intermittent negative years
-needs to be handled by another function.
+of data and then boldly assumes all other years will be positive. RegressionCAGR drops intermittent
+negative years instead, for histories where that assumption doesn't hold.
 */
 func ProfitableEarningsStartingAndEnding(earnings []types.AnnualEarningRecord) (start types.AnnualEarningRecord, end types.AnnualEarningRecord, err error) {
 	minimumYearsRequired := 2
 	if len(earnings) < minimumYearsRequired {
-		err = fmt.Errorf("not enough data points. Minimum: %d", minimumYearsRequired)
+		err = fmt.Errorf("%w: not enough data points. Minimum: %d", ErrInsufficientEarnings, minimumYearsRequired)
 		return
 	}
 
@@ -89,31 +152,33 @@ func ProfitableEarningsStartingAndEnding(earnings []types.AnnualEarningRecord) (
 		better user feedback and suggestion for analysis of early / unprofitable companies
 	*/
 	if !foundStart {
-		err = fmt.Errorf("no valid starting point with positive EPS found. Company just burns money")
+		err = fmt.Errorf("%w: no valid starting point with positive EPS found. Company just burns money", ErrNegativeEarnings)
 		return
 	}
 
 	end = sortedEarnings[len(sortedEarnings)-1]
 
 	if end.ReportedEPS <= 0 {
-		err = fmt.Errorf("ending EPS must be positive for a current CAGR calculation")
+		err = fmt.Errorf("%w: ending EPS must be positive for a current CAGR calculation", ErrNegativeEarnings)
 		return
 	}
 
 	return start, end, nil
 }
 
+// A per share figure on a fiscal date, one end of a growth measurement.
+type datedValue struct {
+	date  string
+	value float64
+}
+
 /*
-Calculate the Compound Annual Growth Rate for a collection of annual earnings.
+Compound annual growth from start to end, whatever the figure is. False when they are less than a
+year apart, too close to annualize.
 */
-func CAGR(earnings []types.AnnualEarningRecord) (float64, error) {
-	startEarning, endEarning, err := ProfitableEarningsStartingAndEnding(earnings)
-	if err != nil {
-		return 0, fmt.Errorf("could not determine calculation endpoints: %w", err)
-	}
-
-	startDate, _ := time.Parse("2006-01-02", startEarning.FiscalDateEnding)
-	endDate, _ := time.Parse("2006-01-02", endEarning.FiscalDateEnding)
+func compoundGrowth(start, end datedValue) (float64, bool) {
+	startDate, _ := time.Parse("2006-01-02", start.date)
+	endDate, _ := time.Parse("2006-01-02", end.date)
 
 	/*
 		Using 365.25 accounts for leap years.
@@ -121,15 +186,51 @@ func CAGR(earnings []types.AnnualEarningRecord) (float64, error) {
 	*/
 	years := endDate.Sub(startDate).Hours() / 24 / 365.25
 	if years < 1.0 {
-		return 0, fmt.Errorf("period between start and end date must be at least one year")
+		return 0, false
 	}
 
-	growthRatio := endEarning.ReportedEPS / startEarning.ReportedEPS
-	cagr := math.Pow(growthRatio, 1.0/years) - 1.0
+	growthRatio := end.value / start.value
+	return math.Pow(growthRatio, 1.0/years) - 1.0, true
+}
+
+/*
+Calculate the Compound Annual Growth Rate for a collection of annual earnings.
+*/
+func CAGR(earnings []types.AnnualEarningRecord) (float64, error) {
+	startEarning, endEarning, err := ProfitableEarningsStartingAndEnding(earnings)
+	if err != nil {
+		return 0, fmt.Errorf("could not determine calculation endpoints: %w", err)
+	}
+
+	cagr, ok := compoundGrowth(
+		datedValue{date: startEarning.FiscalDateEnding, value: startEarning.ReportedEPS},
+		datedValue{date: endEarning.FiscalDateEnding, value: endEarning.ReportedEPS},
+	)
+	if !ok {
+		return 0, fmt.Errorf("%w: period between start and end date must be at least one year", ErrInsufficientEarnings)
+	}
 
 	return cagr, nil
 }
 
+// e.g. "Fair value P/E 14.87 from a 14.87% CAGR, 2014-12-31 to 2024-12-31"
+func (s LynchModelSummary) String() string {
+	if s.PEOverride > 0 {
+		return fmt.Sprintf("Fair value P/E %.2f, set by hand", s.FairValuePE)
+	}
+	description := fmt.Sprintf("Fair value P/E %.2f from a %.2f%% CAGR, %s to %s",
+		s.FairValuePE, s.CAGR*100, s.CAGRStartDate, s.CAGREndDate)
+	if s.GrowthFit != nil {
+		description = fmt.Sprintf("Fair value P/E %.2f from a %.2f%% regression CAGR (R² %.2f, 95%% CI %.2f%% to %.2f%%), %s to %s",
+			s.FairValuePE, s.CAGR*100, s.GrowthFit.RSquared, s.GrowthFit.CAGRLow*100, s.GrowthFit.CAGRHigh*100,
+			s.CAGRStartDate, s.CAGREndDate)
+	}
+	if s.Clamped {
+		description += fmt.Sprintf(" (clamped from %.2f)", FairValuePE(s.CAGR))
+	}
+	return description
+}
+
 /*
 Calculate the Fair Value PE ratio given a Compound Annual Growth Rate.
 */
@@ -137,6 +238,49 @@ func FairValuePE(cagr float64) float64 {
 	return cagr * 100
 }
 
+/*
+Limits a P/E to [minPE, maxPE], where a zero bound is left open. Reports whether it had to be moved.
+*/
+func ClampPE(pe, minPE, maxPE float64) (float64, bool) {
+	if minPE > 0 && pe < minPE {
+		return minPE, true
+	}
+	if maxPE > 0 && pe > maxPE {
+		return maxPE, true
+	}
+	return pe, false
+}
+
+/*
+The earnings within the given number of years of the latest one, for measuring recent growth
+instead of the whole history. Zero or fewer years returns everything.
+*/
+func EarningsWithinCAGRWindow(earnings []types.AnnualEarningRecord, years int) []types.AnnualEarningRecord {
+	if years <= 0 {
+		return earnings
+	}
+
+	latest := ""
+	for _, earning := range earnings {
+		if earning.FiscalDateEnding > latest {
+			latest = earning.FiscalDateEnding
+		}
+	}
+	latestDate, err := time.Parse("2006-01-02", latest)
+	if err != nil {
+		return earnings
+	}
+	windowStart := latestDate.AddDate(-years, 0, 0).Format("2006-01-02")
+
+	window := make([]types.AnnualEarningRecord, 0, len(earnings))
+	for _, earning := range earnings {
+		if earning.FiscalDateEnding >= windowStart {
+			window = append(window, earning)
+		}
+	}
+	return window
+}
+
 /*
 Generate a history of estimated fair value prices for a given stock based on its earnings history
 and a fair value PE ratio.
@@ -162,17 +306,215 @@ func FairValuePriceHistory(fairValuePE float64, historicalEarnings []types.Annua
 }
 
 /*
-Pipeline function that orchestrates the full fair value calculation process.
+Pipeline function that orchestrates the full fair value calculation process. The fair value curve
+always covers all of the given earnings, params only change how its P/E is arrived at, and for
+classified cyclicals which EPS it is applied to. Also returns those EPS, normalized for cyclicals,
+so anything else measured alongside the model sees the same earnings it did.
 */
-func CalculateFairValueHistory(earnings []types.AnnualEarningRecord) ([]types.FairValuePriceRecord, error) {
-	cagr, err := CAGR(earnings)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate CAGR: %w", err)
+func CalculateFairValueHistory(
+	earnings []types.AnnualEarningRecord,
+	params LynchModelParams,
+) ([]types.FairValuePriceRecord, []types.AnnualEarningRecord, LynchModelSummary, error) {
+	if err := params.Validate(); err != nil {
+		return nil, nil, LynchModelSummary{}, err
+	}
+	summary := LynchModelSummary{LynchModelParams: params}
+
+	// Too little history is left for the CAGR to report, it has the better error.
+	if params.ClassifyEarnings && len(earnings) > 0 {
+		profile := ClassifyEarnings(earnings)
+		summary.EarningsProfile = &profile
+		switch profile.Class {
+		case EarningsUnprofitable:
+			return nil, nil, LynchModelSummary{}, fmt.Errorf("%w: classified as unprofitable with %d loss years, the Lynch model has no growth to value",
+				ErrNegativeEarnings, len(profile.LossYears))
+		case EarningsCyclical:
+			earnings = NormalizedEPS(earnings, profile.NormalizationYears)
+		}
 	}
 
-	fairValuePE := FairValuePE(cagr)
+	if params.PEOverride > 0 {
+		summary.FairValuePE = params.PEOverride
+	} else {
+		window := EarningsWithinCAGRWindow(earnings, params.CAGRYears)
+		if params.GrowthMethod == GrowthMethodRegression {
+			fit, err := RegressionCAGR(window)
+			if err != nil {
+				return nil, nil, LynchModelSummary{}, fmt.Errorf("failed to fit CAGR: %w", err)
+			}
+			summary.CAGR = fit.CAGR
+			summary.CAGRStartDate = fit.StartDate
+			summary.CAGREndDate = fit.EndDate
+			summary.GrowthFit = &fit
+		} else {
+			cagr, err := CAGR(window)
+			if err != nil {
+				return nil, nil, LynchModelSummary{}, fmt.Errorf("failed to calculate CAGR: %w", err)
+			}
+			// Can't fail, CAGR just found the same endpoints.
+			start, end, _ := ProfitableEarningsStartingAndEnding(window)
 
-	fairValueHistory := FairValuePriceHistory(fairValuePE, earnings)
+			summary.CAGR = cagr
+			summary.CAGRStartDate = start.FiscalDateEnding
+			summary.CAGREndDate = end.FiscalDateEnding
+		}
+		summary.FairValuePE, summary.Clamped = ClampPE(FairValuePE(summary.CAGR), params.MinPE, params.MaxPE)
+	}
+
+	fairValueHistory := FairValuePriceHistory(summary.FairValuePE, earnings)
+	summary.DroppedYears = DroppedFairValueYears(earnings)
+
+	return fairValueHistory, earnings, summary, nil
+}
+
+/*
+Carries the fair value curve forward onto analyst EPS estimates for the current and next fiscal
+year, using the same fair value PE as the history:
 
-	return fairValueHistory, nil
+	ProjectedFairValue(year) = EstimatedEPS(year) * FairValueP/E
+
+with the high and low estimates giving a band around it. The band starts at the latest point of the
+history so the projection continues the line instead of floating on its own. Estimates for periods
+the history already covers are dropped, as are ones with a non positive average, which would give
+a meaningless negative price.
+*/
+func ProjectFairValue(
+	fairValuePE float64,
+	history []types.FairValuePriceRecord,
+	estimates []types.EarningsEstimateRecord,
+) []types.ProjectedFairValueRecord {
+	var projections []types.ProjectedFairValueRecord
+
+	latestDate := ""
+	for _, record := range history {
+		if record.Date > latestDate {
+			latestDate = record.Date
+			projections = []types.ProjectedFairValueRecord{{
+				Ticker:             record.Ticker,
+				Date:               record.Date,
+				FairValuePrice:     record.FairValuePrice,
+				HighFairValuePrice: record.FairValuePrice,
+				LowFairValuePrice:  record.FairValuePrice,
+			}}
+		}
+	}
+
+	sortedEstimates := make([]types.EarningsEstimateRecord, len(estimates))
+	copy(sortedEstimates, estimates)
+	sort.Slice(sortedEstimates, func(i, j int) bool { return sortedEstimates[i].Date < sortedEstimates[j].Date })
+
+	projected := 0
+	for _, estimate := range sortedEstimates {
+		if estimate.Horizon != types.EstimateHorizonCurrentYear && estimate.Horizon != types.EstimateHorizonNextYear {
+			continue
+		}
+		if estimate.Date <= latestDate || estimate.AverageEPS <= 0 {
+			continue
+		}
+		projections = append(projections, types.ProjectedFairValueRecord{
+			Ticker:             estimate.Ticker,
+			Date:               estimate.Date,
+			FairValuePrice:     estimate.AverageEPS * fairValuePE,
+			HighFairValuePrice: estimate.HighEPS * fairValuePE,
+			LowFairValuePrice:  math.Max(estimate.LowEPS, 0) * fairValuePE,
+		})
+		projected++
+	}
+
+	// A lone anchor point isn't a projection.
+	if projected == 0 {
+		return nil
+	}
+	return projections
+}
+
+/*
+Benjamin Graham's two fair values, for comparison with the Lynch one.
+
+The Graham Number is the most a defensive investor should pay, capping both the P/E at 15 and the
+price to book at 1.5:
+
+	GrahamNumber = sqrt(22.5 × EPS × BookValuePerShare)
+
+The revised intrinsic value formula from The Intelligent Investor values a company on earnings and
+expected growth, scaled by how today's AAA corporate bond yield compares to the 4.4% of Graham's day:
+
+	IntrinsicValue = EPS × (8.5 + 2g) × 4.4 / Y
+
+where 8.5 is the P/E of a company with no growth, g is the growth rate in percent and Y the current
+AAA yield in percent. Both are meaningless on a loss or negative book value, those years are skipped.
+*/
+
+const (
+	// 15 times earnings by 1.5 times book.
+	grahamNumberMultiplier = 22.5
+	grahamNoGrowthPE       = 8.5
+	// AAA yield when Graham published the formula, using it leaves the value unadjusted for rates.
+	GrahamBaselineBondYield = 4.4
+)
+
+func GrahamNumber(eps, bookValuePerShare float64) float64 {
+	return math.Sqrt(grahamNumberMultiplier * eps * bookValuePerShare)
+}
+
+// growthPercent and bondYield are percentages, e.g. 7 for 7%.
+func GrahamIntrinsicValue(eps, growthPercent, bondYield float64) float64 {
+	return eps * (grahamNoGrowthPE + 2*growthPercent) * GrahamBaselineBondYield / bondYield
+}
+
+/*
+Graham Number for each year of earnings, using the latest book value per share reported on or
+before that year's fiscal date end so TTM earnings can be paired with annual balance sheets.
+*/
+func GrahamNumberHistory(
+	earnings []types.AnnualEarningRecord,
+	bookValues []types.BookValuePerShareRecord,
+) []types.FairValuePriceRecord {
+	sortedBookValues := make([]types.BookValuePerShareRecord, len(bookValues))
+	copy(sortedBookValues, bookValues)
+	sort.Slice(sortedBookValues, func(i, j int) bool {
+		return sortedBookValues[i].FiscalDateEnding < sortedBookValues[j].FiscalDateEnding
+	})
+
+	var history []types.FairValuePriceRecord
+	for _, earning := range earnings {
+		i := sort.Search(len(sortedBookValues), func(i int) bool {
+			return sortedBookValues[i].FiscalDateEnding > earning.FiscalDateEnding
+		})
+		if i == 0 {
+			continue
+		}
+		bookValue := sortedBookValues[i-1].BookValuePerShare
+		if earning.ReportedEPS <= 0 || bookValue <= 0 {
+			continue
+		}
+		history = append(history, types.FairValuePriceRecord{
+			Ticker:         earning.Ticker,
+			FairValuePrice: GrahamNumber(earning.ReportedEPS, bookValue),
+			Date:           earning.FiscalDateEnding,
+		})
+	}
+	return history
+}
+
+// Graham intrinsic value for each year of earnings at one growth rate and bond yield, both in percent.
+func GrahamIntrinsicValueHistory(
+	earnings []types.AnnualEarningRecord,
+	growthPercent float64,
+	bondYield float64,
+) []types.FairValuePriceRecord {
+	var history []types.FairValuePriceRecord
+	for _, earning := range earnings {
+		value := GrahamIntrinsicValue(earning.ReportedEPS, growthPercent, bondYield)
+		// A loss, or growth so negative the multiple goes below zero.
+		if earning.ReportedEPS <= 0 || value <= 0 {
+			continue
+		}
+		history = append(history, types.FairValuePriceRecord{
+			Ticker:         earning.Ticker,
+			FairValuePrice: value,
+			Date:           earning.FiscalDateEnding,
+		})
+	}
+	return history
 }
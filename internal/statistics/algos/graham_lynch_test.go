@@ -1,6 +1,7 @@
 package algos
 
 import (
+	"errors"
 	"testing"
 
 	"cibo/internal/types"
@@ -51,6 +52,9 @@ func TestEarningsEndpoints_NotEnoughData(t *testing.T) {
 	if err == nil {
 		t.Fatal("EarningsEndpoints() expected an error for insufficient data, but got none")
 	}
+	if !errors.Is(err, ErrInsufficientEarnings) {
+		t.Errorf("Expected an insufficient earnings error, but got: %v", err)
+	}
 }
 
 // Given a slice containing only negative earnings, verify that an error is returned because there is
@@ -64,6 +68,9 @@ func TestEarningsEndpoints_AllNegativeEPS(t *testing.T) {
 	if err == nil {
 		t.Fatal("EarningsEndpoints() expected an error for all negative EPS, but got none")
 	}
+	if !errors.Is(err, ErrNegativeEarnings) {
+		t.Errorf("Expected a negative earnings error, but got: %v", err)
+	}
 }
 
 // Given a slice where the final chronological earning is negative, verify that an error is returned.
@@ -76,6 +83,9 @@ func TestEarningsEndpoints_NegativeEndingEPS(t *testing.T) {
 	if err == nil {
 		t.Fatal("EarningsEndpoints() expected an error for negative ending EPS, but got none")
 	}
+	if !errors.Is(err, ErrNegativeEarnings) {
+		t.Errorf("Expected a negative earnings error, but got: %v", err)
+	}
 }
 
 // Given a valid slice of earnings, verify that the Compound Annual Growth Rate is calculated correctly.
@@ -113,6 +123,9 @@ func TestCAGR_PeriodTooShort(t *testing.T) {
 	if err == nil {
 		t.Fatal("CAGR() expected an error for a period less than one year, but got none")
 	}
+	if !errors.Is(err, ErrInsufficientEarnings) {
+		t.Errorf("Expected an insufficient earnings error, but got: %v", err)
+	}
 }
 
 // Given a positive CAGR, verify that the correct Fair Value P/E ratio is calculated.
@@ -192,3 +205,160 @@ func TestFairValuePriceHistory_EmptyInput(t *testing.T) {
 		t.Errorf("FairValuePriceHistory() expected an empty slice for empty input, but got %d elements", len(history))
 	}
 }
+
+// Given a fair value history and estimates for a reported year, the current and next year and a
+// quarter, verify the projection starts at the latest fair value and only uses unreported years.
+func TestProjectFairValue(t *testing.T) {
+	fairValuePE := 20.0
+	history := []types.FairValuePriceRecord{
+		{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 40.0},
+		{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: 30.0},
+	}
+	estimates := []types.EarningsEstimateRecord{
+		{Ticker: "TEST", Date: "2026-12-31", Horizon: types.EstimateHorizonNextYear, AverageEPS: 3.0, HighEPS: 3.5, LowEPS: 2.5},
+		{Ticker: "TEST", Date: "2025-12-31", Horizon: types.EstimateHorizonCurrentYear, AverageEPS: 2.5, HighEPS: 2.6, LowEPS: 2.2},
+		{Ticker: "TEST", Date: "2025-09-30", Horizon: "next fiscal quarter", AverageEPS: 0.6, HighEPS: 0.7, LowEPS: 0.5},
+		{Ticker: "TEST", Date: "2024-12-31", Horizon: types.EstimateHorizonCurrentYear, AverageEPS: 1.9, HighEPS: 2.0, LowEPS: 1.8},
+	}
+
+	expected := []types.ProjectedFairValueRecord{
+		{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 40, HighFairValuePrice: 40, LowFairValuePrice: 40},
+		{Ticker: "TEST", Date: "2025-12-31", FairValuePrice: 50, HighFairValuePrice: 52, LowFairValuePrice: 44},
+		{Ticker: "TEST", Date: "2026-12-31", FairValuePrice: 60, HighFairValuePrice: 70, LowFairValuePrice: 50},
+	}
+
+	result := ProjectFairValue(fairValuePE, history, estimates)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("ProjectFairValue() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given only estimates for periods already in the history, verify there's no projection at all.
+func TestProjectFairValue_NothingAhead(t *testing.T) {
+	history := []types.FairValuePriceRecord{{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 40.0}}
+	estimates := []types.EarningsEstimateRecord{
+		{Ticker: "TEST", Date: "2024-12-31", Horizon: types.EstimateHorizonCurrentYear, AverageEPS: 1.9, HighEPS: 2.0, LowEPS: 1.8},
+	}
+
+	if result := ProjectFairValue(20.0, history, estimates); result != nil {
+		t.Errorf("ProjectFairValue() expected no projection, got %v", result)
+	}
+}
+
+var mockDecadeOfEarnings = []types.AnnualEarningRecord{
+	{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 4.0},
+	{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 2.0},
+	{Ticker: "TEST", FiscalDateEnding: "2020-12-31", ReportedEPS: 1.0},
+	{Ticker: "TEST", FiscalDateEnding: "2014-12-31", ReportedEPS: 1.0},
+}
+
+// Given a CAGR window, verify growth is measured over the window only while the fair value curve
+// still covers every year.
+func TestCalculateFairValueHistory_CAGRWindow(t *testing.T) {
+	history, _, summary, err := CalculateFairValueHistory(mockDecadeOfEarnings, LynchModelParams{CAGRYears: 4})
+	if err != nil {
+		t.Fatalf("CalculateFairValueHistory() returned an unexpected error: %v", err)
+	}
+
+	if summary.CAGRStartDate != "2020-12-31" || summary.CAGREndDate != "2024-12-31" {
+		t.Errorf("Expected the CAGR window 2020-12-31 to 2024-12-31, got %s to %s", summary.CAGRStartDate, summary.CAGREndDate)
+	}
+	// EPS quadrupled over the four years, roughly 41% a year.
+	if diff := cmp.Diff(41.4, summary.FairValuePE, cmpopts.EquateApprox(0, 0.1)); diff != "" {
+		t.Errorf("CalculateFairValueHistory() fair value PE mismatch (-want +got):\n%s", diff)
+	}
+	if len(history) != len(mockDecadeOfEarnings) {
+		t.Errorf("Expected a fair value for every year, got %d", len(history))
+	}
+}
+
+// Given P/E bounds and an override, verify the derived P/E is clamped and the override used as is.
+func TestCalculateFairValueHistory_PEBoundsAndOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   LynchModelParams
+		expected float64
+		clamped  bool
+	}{
+		{"clamped to max", LynchModelParams{CAGRYears: 4, MaxPE: 25}, 25, true},
+		{"clamped to min", LynchModelParams{MinPE: 30}, 30, true},
+		{"override ignores bounds", LynchModelParams{PEOverride: 15, MinPE: 20}, 15, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, _, summary, err := CalculateFairValueHistory(mockDecadeOfEarnings, tt.params)
+			if err != nil {
+				t.Fatalf("CalculateFairValueHistory() returned an unexpected error: %v", err)
+			}
+			if summary.FairValuePE != tt.expected || summary.Clamped != tt.clamped {
+				t.Errorf("Expected P/E %.2f (clamped %t), got %.2f (clamped %t)", tt.expected, tt.clamped, summary.FairValuePE, summary.Clamped)
+			}
+			if history[0].FairValuePrice != 4.0*tt.expected {
+				t.Errorf("Expected the latest fair value to use the P/E, got %.2f", history[0].FairValuePrice)
+			}
+		})
+	}
+}
+
+// Given parameters that can't work together, verify they're rejected before any calculation.
+func TestCalculateFairValueHistory_InvalidParams(t *testing.T) {
+	for _, params := range []LynchModelParams{{CAGRYears: -1}, {PEOverride: -5}, {MinPE: 30, MaxPE: 20}, {GrowthMethod: "median"}} {
+		if _, _, _, err := CalculateFairValueHistory(mockDecadeOfEarnings, params); !errors.Is(err, ErrInvalidModelParams) {
+			t.Errorf("Expected ErrInvalidModelParams for %+v, got: %v", params, err)
+		}
+	}
+}
+
+// Given annual and quarterly dated earnings with a loss year and a year before any balance sheet,
+// verify each Graham Number uses the latest book value on or before it and skips the rest.
+func TestGrahamNumberHistory(t *testing.T) {
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-06-30", ReportedEPS: 4},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 2},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: -1},
+		{Ticker: "TEST", FiscalDateEnding: "2020-12-31", ReportedEPS: 1},
+	}
+	bookValues := []types.BookValuePerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", BookValuePerShare: 5},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", BookValuePerShare: 4},
+	}
+
+	expected := []types.FairValuePriceRecord{
+		// sqrt(22.5 * 4 * 5) and sqrt(22.5 * 2 * 5)
+		{Ticker: "TEST", Date: "2024-06-30", FairValuePrice: 21.213203435596427},
+		{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: 15},
+	}
+
+	result := GrahamNumberHistory(earnings, bookValues)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("GrahamNumberHistory() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a 10% growth rate, verify the intrinsic value is unadjusted at Graham's 4.4% yield and
+// scaled down at a higher one, and loss years are skipped.
+func TestGrahamIntrinsicValueHistory(t *testing.T) {
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 2},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: -1},
+	}
+
+	tests := []struct {
+		bondYield float64
+		expected  float64
+	}{
+		// 2 * (8.5 + 2 * 10)
+		{GrahamBaselineBondYield, 57},
+		{5.5, 45.6},
+	}
+	for _, tc := range tests {
+		expected := []types.FairValuePriceRecord{{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: tc.expected}}
+		result := GrahamIntrinsicValueHistory(earnings, 10, tc.bondYield)
+		if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+			t.Errorf("GrahamIntrinsicValueHistory() at a %v%% yield mismatch (-want +got):\n%s", tc.bondYield, diff)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package algos
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Growth from a least squares fit through every profitable year instead of only the two endpoints.
+
+	ln(EPS(t)) = a + b × t        t in years since the first point
+	CAGR = e^b − 1
+
+The endpoint CAGR hangs on two numbers, so one bad start or end year swings the whole fair value
+P/E. The fit weighs every year equally, and its R² says how well a steady compounding rate
+describes the history at all. Loss years have no logarithm and are dropped rather than ending the
+calculation, so intermittent losses no longer need the endpoints to fall on good years.
+
+The confidence interval is the 95% interval of the slope mapped through e^b − 1, so it is not
+symmetric around the CAGR. It needs at least three points, two always fit perfectly.
+*/
+
+const (
+	GrowthMethodEndpoints  = "endpoints"
+	GrowthMethodRegression = "regression"
+
+	minRegressionPoints = 3
+)
+
+// Diagnostics of a regression CAGR, so a growth rate can be judged before the P/E built on it is.
+type GrowthFit struct {
+	CAGR     float64 `json:"cagr"`
+	RSquared float64 `json:"r_squared"`
+	// 95% confidence interval of the CAGR.
+	CAGRLow   float64 `json:"cagr_low"`
+	CAGRHigh  float64 `json:"cagr_high"`
+	Points    int     `json:"points"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+	// Years with zero or negative EPS, left out of the fit.
+	DroppedYears int `json:"dropped_years,omitempty"`
+}
+
+// Two sided 97.5% quantiles of Student's t for 1 to 30 degrees of freedom.
+var tQuantile975 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// Past 30 degrees of freedom the normal quantile is within a few percent, close enough for a band.
+func tCritical(degreesOfFreedom int) float64 {
+	if degreesOfFreedom <= len(tQuantile975) {
+		return tQuantile975[degreesOfFreedom-1]
+	}
+	return 1.96
+}
+
+/*
+Fits the CAGR of a collection of annual earnings by log-linear least squares over every year with
+positive EPS. Fails when fewer than three profitable years, or less than a year between them, are left.
+*/
+func RegressionCAGR(earnings []types.AnnualEarningRecord) (GrowthFit, error) {
+	profitable := make([]types.AnnualEarningRecord, 0, len(earnings))
+	for _, earning := range earnings {
+		if earning.ReportedEPS > 0 {
+			profitable = append(profitable, earning)
+		}
+	}
+	if len(earnings) > 0 && len(profitable) == 0 {
+		return GrowthFit{}, fmt.Errorf("%w: no year with positive EPS to fit growth to", ErrNegativeEarnings)
+	}
+	if len(profitable) < minRegressionPoints {
+		return GrowthFit{}, fmt.Errorf("%w: not enough years with positive EPS to fit. Minimum: %d",
+			ErrInsufficientEarnings, minRegressionPoints)
+	}
+	sort.Slice(profitable, func(i, j int) bool { return profitable[i].FiscalDateEnding < profitable[j].FiscalDateEnding })
+
+	start, _ := time.Parse("2006-01-02", profitable[0].FiscalDateEnding)
+	xs := make([]float64, len(profitable))
+	ys := make([]float64, len(profitable))
+	for i, earning := range profitable {
+		date, _ := time.Parse("2006-01-02", earning.FiscalDateEnding)
+		// Same year length as CAGR, so both methods agree on a steady series.
+		xs[i] = date.Sub(start).Hours() / 24 / 365.25
+		ys[i] = math.Log(earning.ReportedEPS)
+	}
+	if xs[len(xs)-1] < 1.0 {
+		return GrowthFit{}, fmt.Errorf("%w: period between the first and last profitable year must be at least one year", ErrInsufficientEarnings)
+	}
+
+	meanX, meanY := Mean(xs), Mean(ys)
+	var sxx, sxy, syy float64
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+		syy += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+
+	var residuals float64
+	for i := range xs {
+		residual := ys[i] - (intercept + slope*xs[i])
+		residuals += residual * residual
+	}
+	// Flat earnings are fit perfectly by a zero slope.
+	rSquared := 1.0
+	if syy > 0 {
+		rSquared = 1 - residuals/syy
+	}
+
+	degreesOfFreedom := len(xs) - 2
+	slopeError := math.Sqrt(residuals / float64(degreesOfFreedom) / sxx)
+	margin := tCritical(degreesOfFreedom) * slopeError
+
+	return GrowthFit{
+		CAGR:         math.Exp(slope) - 1,
+		RSquared:     rSquared,
+		CAGRLow:      math.Exp(slope-margin) - 1,
+		CAGRHigh:     math.Exp(slope+margin) - 1,
+		Points:       len(profitable),
+		StartDate:    profitable[0].FiscalDateEnding,
+		EndDate:      profitable[len(profitable)-1].FiscalDateEnding,
+		DroppedYears: len(earnings) - len(profitable),
+	}, nil
+}
+
+// The CAGR of earnings by the given growth method, empty meaning endpoints.
+func GrowthRate(earnings []types.AnnualEarningRecord, method string) (float64, error) {
+	if method == GrowthMethodRegression {
+		fit, err := RegressionCAGR(earnings)
+		return fit.CAGR, err
+	}
+	return CAGR(earnings)
+}
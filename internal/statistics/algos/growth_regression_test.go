@@ -0,0 +1,127 @@
+package algos
+
+import (
+	"errors"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given EPS that doubles every year, verify the fit finds 100% growth with an R² of one and a tight
+// interval around it.
+func TestRegressionCAGR_SteadyGrowth(t *testing.T) {
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 8.0},
+		{Ticker: "TEST", FiscalDateEnding: "2021-12-31", ReportedEPS: 1.0},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 4.0},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 2.0},
+	}
+
+	fit, err := RegressionCAGR(earnings)
+	if err != nil {
+		t.Fatalf("RegressionCAGR() returned an unexpected error: %v", err)
+	}
+
+	// Years are 365.25 days, so calendar years are a touch off a perfect line.
+	if diff := cmp.Diff(1.0, fit.CAGR, cmpopts.EquateApprox(0, 0.01)); diff != "" {
+		t.Errorf("RegressionCAGR() CAGR mismatch (-want +got):\n%s", diff)
+	}
+	if fit.RSquared < 0.9999 {
+		t.Errorf("Expected an R² of about one, got %v", fit.RSquared)
+	}
+	if fit.CAGRLow > fit.CAGR || fit.CAGRHigh < fit.CAGR || fit.CAGRHigh-fit.CAGRLow > 0.05 {
+		t.Errorf("Expected a tight interval around the CAGR, got %v to %v", fit.CAGRLow, fit.CAGRHigh)
+	}
+	if fit.Points != 4 || fit.StartDate != "2021-12-31" || fit.EndDate != "2024-12-31" {
+		t.Errorf("Expected 4 points from 2021-12-31 to 2024-12-31, got %d from %s to %s", fit.Points, fit.StartDate, fit.EndDate)
+	}
+}
+
+// Given a first year far below the trend, verify the fit is pulled less than the endpoint CAGR and
+// reports the poorer fit.
+func TestRegressionCAGR_BadEndpoint(t *testing.T) {
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2020-12-31", ReportedEPS: 0.2},
+		{Ticker: "TEST", FiscalDateEnding: "2021-12-31", ReportedEPS: 2.0},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 2.2},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 2.4},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 2.7},
+	}
+
+	fit, err := RegressionCAGR(earnings)
+	if err != nil {
+		t.Fatalf("RegressionCAGR() returned an unexpected error: %v", err)
+	}
+	endpoints, err := CAGR(earnings)
+	if err != nil {
+		t.Fatalf("CAGR() returned an unexpected error: %v", err)
+	}
+
+	if fit.CAGR >= endpoints {
+		t.Errorf("Expected the fit %v to be below the endpoint CAGR %v", fit.CAGR, endpoints)
+	}
+	if fit.RSquared > 0.9 {
+		t.Errorf("Expected the outlier to lower R², got %v", fit.RSquared)
+	}
+}
+
+// Given early losses, verify the loss years are dropped and counted rather than failing the fit.
+func TestRegressionCAGR_DropsLossYears(t *testing.T) {
+	fit, err := RegressionCAGR(mockEarnings)
+	if err != nil {
+		t.Fatalf("RegressionCAGR() returned an unexpected error: %v", err)
+	}
+
+	if fit.Points != 6 || fit.DroppedYears != 2 || fit.StartDate != "2019-12-31" {
+		t.Errorf("Expected 6 points from 2019-12-31 with 2 dropped, got %d from %s with %d dropped",
+			fit.Points, fit.StartDate, fit.DroppedYears)
+	}
+}
+
+// Given too few profitable years, verify the matching error for each case.
+func TestRegressionCAGR_NotEnoughData(t *testing.T) {
+	tests := []struct {
+		name     string
+		earnings []types.AnnualEarningRecord
+		expected error
+	}{
+		{"two profitable years", mockEarnings[:2], ErrInsufficientEarnings},
+		{"all losses", mockEarnings[5:7], ErrNegativeEarnings},
+		{"under a year apart", []types.AnnualEarningRecord{
+			{FiscalDateEnding: "2024-03-31", ReportedEPS: 1},
+			{FiscalDateEnding: "2024-06-30", ReportedEPS: 1.1},
+			{FiscalDateEnding: "2024-09-30", ReportedEPS: 1.2},
+		}, ErrInsufficientEarnings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RegressionCAGR(tt.earnings); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// Given the regression growth method, verify the fair value P/E comes from the fit and its
+// diagnostics are kept on the summary.
+func TestCalculateFairValueHistory_RegressionGrowth(t *testing.T) {
+	_, _, summary, err := CalculateFairValueHistory(mockEarnings, LynchModelParams{GrowthMethod: GrowthMethodRegression})
+	if err != nil {
+		t.Fatalf("CalculateFairValueHistory() returned an unexpected error: %v", err)
+	}
+
+	if summary.GrowthFit == nil {
+		t.Fatal("Expected the summary to carry the growth fit")
+	}
+	if summary.CAGR != summary.GrowthFit.CAGR || summary.FairValuePE != FairValuePE(summary.GrowthFit.CAGR) {
+		t.Errorf("Expected the P/E to come from the fitted CAGR %v, got CAGR %v and P/E %v",
+			summary.GrowthFit.CAGR, summary.CAGR, summary.FairValuePE)
+	}
+	if summary.CAGRStartDate != "2019-12-31" || summary.CAGREndDate != "2024-12-31" {
+		t.Errorf("Expected the fit to span 2019-12-31 to 2024-12-31, got %s to %s", summary.CAGRStartDate, summary.CAGREndDate)
+	}
+}
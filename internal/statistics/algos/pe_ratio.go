@@ -0,0 +1,155 @@
+package algos
+
+import (
+	"math"
+	"sort"
+
+	"cibo/internal/types"
+)
+
+/*
+The P/E the market actually paid on each trading day, as opposed to the fair value P/E the Lynch
+model says it should have paid.
+
+	PE(t) = ClosingPrice(t) / EPS known on t
+
+EPS is point in time: a fiscal period's EPS only counts from the day it was reported, taken from the
+quarterly report for the same fiscal date end. When that report date isn't known the fiscal date is
+used, same as the CAPE calculation. Days before the first known EPS, or while the latest known EPS is
+zero or negative, have no P/E since a multiple of a loss means nothing.
+*/
+
+type knownEPS struct {
+	availableDate string
+	earning       types.AnnualEarningRecord
+}
+
+/*
+Pairs each of earnings with the day it became known, the report date of the quarter with the same
+fiscal date end or the fiscal date itself when that isn't known. Sorted by that day.
+*/
+func knownEarnings(earnings []types.AnnualEarningRecord, quarterly []types.QuarterlyEarningRecord) []knownEPS {
+	reportedDates := make(map[string]string, len(quarterly))
+	for _, quarter := range quarterly {
+		if quarter.ReportedDate != "" {
+			reportedDates[quarter.FiscalDateEnding] = quarter.ReportedDate
+		}
+	}
+
+	known := make([]knownEPS, 0, len(earnings))
+	for _, record := range earnings {
+		available, ok := reportedDates[record.FiscalDateEnding]
+		if !ok {
+			available = record.FiscalDateEnding
+		}
+		known = append(known, knownEPS{availableDate: available, earning: record})
+	}
+	sort.SliceStable(known, func(i, j int) bool { return known[i].availableDate < known[j].availableDate })
+	return known
+}
+
+// Index into known of the latest EPS known on date, -1 when none was yet.
+func latestKnownEPS(known []knownEPS, date string) int {
+	return sort.Search(len(known), func(i int) bool { return known[i].availableDate > date }) - 1
+}
+
+/*
+Calculates the daily P/E for every price that has positive EPS known on its date. earnings can be
+annual or TTM records, quarterly is only used to find when each of them was reported. Returns the
+records in the order of dailyPrices.
+*/
+func HistoricalPE(
+	dailyPrices []types.DailyStockRecord,
+	earnings []types.AnnualEarningRecord,
+	quarterly []types.QuarterlyEarningRecord,
+) []types.PERatioRecord {
+	known := knownEarnings(earnings, quarterly)
+
+	records := make([]types.PERatioRecord, 0, len(dailyPrices))
+	for _, price := range dailyPrices {
+		i := latestKnownEPS(known, price.Date)
+		if i < 0 {
+			continue
+		}
+		eps := known[i].earning.ReportedEPS
+		if eps <= 0 || price.ClosingPrice <= 0 {
+			continue
+		}
+		records = append(records, types.PERatioRecord{
+			Ticker: price.Ticker,
+			Date:   price.Date,
+			Ratio:  price.ClosingPrice / eps,
+			EPS:    eps,
+		})
+	}
+
+	return records
+}
+
+// Just the ratios, for the summary statistics.
+func PERatios(records []types.PERatioRecord) []float64 {
+	ratios := make([]float64, len(records))
+	for i, record := range records {
+		ratios[i] = record.Ratio
+	}
+	return ratios
+}
+
+// Percentile bands around the daily P/E, along with the mean and median.
+var peRatioPercentileBands = []struct {
+	series     string
+	percentile float64
+}{
+	{types.SeriesPERatioP10, 10},
+	{types.SeriesPERatioP25, 25},
+	{types.SeriesPERatioP75, 75},
+	{types.SeriesPERatioP90, 90},
+}
+
+/*
+The mean, median and 10th, 25th, 75th and 90th percentile of the daily P/E, each spanning the
+first to the last day of records. Returns nil when there are no records.
+*/
+func PERatioBands(records []types.PERatioRecord) []types.PERatioBandRecord {
+	if len(records) == 0 {
+		return nil
+	}
+	ratios := PERatios(records)
+	start, end := records[0].Date, records[0].Date
+	for _, record := range records {
+		start = min(start, record.Date)
+		end = max(end, record.Date)
+	}
+
+	band := func(series string, ratio float64) types.PERatioBandRecord {
+		return types.PERatioBandRecord{
+			Ticker:    records[0].Ticker,
+			Series:    series,
+			StartDate: start,
+			EndDate:   end,
+			Ratio:     ratio,
+		}
+	}
+	bands := []types.PERatioBandRecord{
+		band(types.SeriesPERatioMean, Mean(ratios)),
+		band(types.SeriesPERatioMedian, Median(ratios)),
+	}
+	for _, percentile := range peRatioPercentileBands {
+		bands = append(bands, band(percentile.series, Percentile(ratios, percentile.percentile)))
+	}
+	return bands
+}
+
+// The most recent P/E and where it ranks against all of records, as a percentage of days at or below it.
+func LatestPERank(records []types.PERatioRecord) (types.PERatioRecord, float64) {
+	if len(records) == 0 {
+		return types.PERatioRecord{}, math.NaN()
+	}
+	latest := records[0]
+	for _, record := range records {
+		if record.Date > latest.Date {
+			latest = record
+		}
+	}
+	return latest, PercentileRank(PERatios(records), latest.Ratio)
+}
@@ -0,0 +1,88 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given annual EPS with one report date known and a loss year, verify each day is priced on the EPS
+// reported by then, and days before any EPS or on a loss have no P/E.
+func TestHistoricalPE(t *testing.T) {
+	dailyPrices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2025-02-03", ClosingPrice: 200},
+		{Ticker: "TEST", Date: "2025-01-02", ClosingPrice: 150},
+		{Ticker: "TEST", Date: "2023-06-01", ClosingPrice: 90},
+		{Ticker: "TEST", Date: "2022-06-01", ClosingPrice: 80},
+		{Ticker: "TEST", Date: "2021-06-01", ClosingPrice: 70},
+	}
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 10},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 5},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 3},
+		{Ticker: "TEST", FiscalDateEnding: "2021-12-31", ReportedEPS: -1},
+	}
+	quarterly := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedDate: "2025-01-30", ReportedEPS: 3},
+		// No report date, so the fiscal date is used.
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 1.5},
+	}
+
+	expected := []types.PERatioRecord{
+		{Ticker: "TEST", Date: "2025-02-03", Ratio: 20, EPS: 10},
+		{Ticker: "TEST", Date: "2025-01-02", Ratio: 30, EPS: 5},
+		{Ticker: "TEST", Date: "2023-06-01", Ratio: 30, EPS: 3},
+		// 2022-06-01 only knows the 2021 loss, 2021-06-01 knows no EPS at all.
+	}
+
+	result := HistoricalPE(dailyPrices, earnings, quarterly)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("HistoricalPE() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given five days of P/E, verify the bands span the first to the last day and the percentiles
+// interpolate between days.
+func TestPERatioBands(t *testing.T) {
+	records := []types.PERatioRecord{
+		{Ticker: "TEST", Date: "2024-01-05", Ratio: 30},
+		{Ticker: "TEST", Date: "2024-01-04", Ratio: 10},
+		{Ticker: "TEST", Date: "2024-01-03", Ratio: 20},
+		{Ticker: "TEST", Date: "2024-01-02", Ratio: 50},
+		{Ticker: "TEST", Date: "2024-01-01", Ratio: 40},
+	}
+
+	band := func(series string, ratio float64) types.PERatioBandRecord {
+		return types.PERatioBandRecord{Ticker: "TEST", Series: series, StartDate: "2024-01-01", EndDate: "2024-01-05", Ratio: ratio}
+	}
+	expected := []types.PERatioBandRecord{
+		band(types.SeriesPERatioMean, 30),
+		band(types.SeriesPERatioMedian, 30),
+		band(types.SeriesPERatioP10, 14),
+		band(types.SeriesPERatioP25, 20),
+		band(types.SeriesPERatioP75, 40),
+		band(types.SeriesPERatioP90, 46),
+	}
+
+	result := PERatioBands(records)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("PERatioBands() mismatch (-want +got):\n%s", diff)
+	}
+
+	latest, rank := LatestPERank(records)
+	if latest.Date != "2024-01-05" || rank != 60 {
+		t.Errorf("Expected the latest P/E on 2024-01-05 to rank at 60%%, got %s at %v", latest.Date, rank)
+	}
+}
+
+// Given no P/E at all, verify there are no bands rather than NaN ones.
+func TestPERatioBands_Empty(t *testing.T) {
+	if bands := PERatioBands(nil); bands != nil {
+		t.Errorf("Expected no bands, got %+v", bands)
+	}
+}
@@ -0,0 +1,94 @@
+package algos
+
+import (
+	"cibo/internal/types"
+)
+
+/*
+The P/E the market paid set against the growth behind it. Lynch's rule of thumb is that a fairly
+priced company trades at a P/E equal to its growth rate, a PEG of 1. PEGY adds the dividend yield to
+the growth, since a payer hands part of its earnings back as cash instead of growing with them.
+
+	PEG(t)  = PE(t) / (Growth(t) × 100)
+	PEGY(t) = PE(t) / ((Growth(t) + Yield(t)) × 100)
+
+Growth is the EPS CAGR over the trailing window as known on each day, measured the same way as the
+fair value's growth, so like the P/E it only moves once a report is out. Days without positive
+growth have neither ratio, a multiple of shrinking earnings can't be compared to 1.
+
+https://www.investopedia.com/terms/p/pegyratio.asp
+*/
+
+type trailingGrowth struct {
+	cagr float64
+	ok   bool
+}
+
+/*
+Calculates PEG and PEGY for every day of peRatios. earnings and quarterly must be the ones the P/E
+was calculated from, so each day's growth ends on the same EPS as its P/E. cagrYears is the trailing
+window the CAGR is measured over, zero for all earnings known that day, and growthMethod how it's
+measured as for GrowthRate. Days with no yield record are taken as paying no dividend. Returns the
+records in the order of peRatios.
+*/
+func HistoricalPEG(
+	peRatios []types.PERatioRecord,
+	earnings []types.AnnualEarningRecord,
+	quarterly []types.QuarterlyEarningRecord,
+	yields []types.DividendYieldRecord,
+	cagrYears int,
+	growthMethod string,
+) []types.PEGRecord {
+	known := knownEarnings(earnings, quarterly)
+
+	// Growth changes once per report, so measure it once per report rather than once per day.
+	growth := make([]trailingGrowth, len(known))
+	for i := range known {
+		available := make([]types.AnnualEarningRecord, 0, i+1)
+		for _, k := range known[:i+1] {
+			available = append(available, k.earning)
+		}
+		cagr, err := GrowthRate(EarningsWithinCAGRWindow(available, cagrYears), growthMethod)
+		growth[i] = trailingGrowth{cagr: cagr, ok: err == nil}
+	}
+
+	yieldByDate := make(map[string]float64, len(yields))
+	for _, yield := range yields {
+		yieldByDate[yield.Date] = yield.Yield
+	}
+
+	records := make([]types.PEGRecord, 0, len(peRatios))
+	for _, pe := range peRatios {
+		i := latestKnownEPS(known, pe.Date)
+		if i < 0 || !growth[i].ok || growth[i].cagr <= 0 {
+			continue
+		}
+		cagr := growth[i].cagr
+		yield := yieldByDate[pe.Date]
+		records = append(records, types.PEGRecord{
+			Ticker:        pe.Ticker,
+			Date:          pe.Date,
+			PERatio:       pe.Ratio,
+			Growth:        cagr,
+			DividendYield: yield,
+			PEG:           pe.Ratio / (cagr * 100),
+			PEGY:          pe.Ratio / ((cagr + yield) * 100),
+		})
+	}
+
+	return records
+}
+
+// The most recent PEG record, false when there are none.
+func LatestPEG(records []types.PEGRecord) (types.PEGRecord, bool) {
+	if len(records) == 0 {
+		return types.PEGRecord{}, false
+	}
+	latest := records[0]
+	for _, record := range records {
+		if record.Date > latest.Date {
+			latest = record
+		}
+	}
+	return latest, true
+}
@@ -0,0 +1,94 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given three years of EPS, one reported a month after its fiscal year end, verify each day's growth
+// ends on the EPS known that day, the window limits how far back it starts, and a day with a single
+// known year has no PEG.
+func TestHistoricalPEG(t *testing.T) {
+	peRatios := []types.PERatioRecord{
+		{Ticker: "TEST", Date: "2025-02-03", Ratio: 20, EPS: 8},
+		{Ticker: "TEST", Date: "2025-01-02", Ratio: 30, EPS: 6},
+		{Ticker: "TEST", Date: "2021-06-01", Ratio: 25, EPS: 4},
+	}
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 8},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 6},
+		{Ticker: "TEST", FiscalDateEnding: "2020-12-31", ReportedEPS: 4},
+	}
+	quarterly := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedDate: "2025-01-30", ReportedEPS: 2},
+	}
+	yields := []types.DividendYieldRecord{
+		{Ticker: "TEST", Date: "2025-02-03", Yield: 0.02},
+	}
+
+	cagr := func(records ...types.AnnualEarningRecord) float64 {
+		growth, err := CAGR(records)
+		if err != nil {
+			t.Fatalf("CAGR() returned an unexpected error: %v", err)
+		}
+		return growth
+	}
+	peg := func(pe types.PERatioRecord, growth, yield float64) types.PEGRecord {
+		return types.PEGRecord{
+			Ticker:        "TEST",
+			Date:          pe.Date,
+			PERatio:       pe.Ratio,
+			Growth:        growth,
+			DividendYield: yield,
+			PEG:           pe.Ratio / (growth * 100),
+			PEGY:          pe.Ratio / ((growth + yield) * 100),
+		}
+	}
+
+	allYears := cagr(earnings[2], earnings[0])
+	beforeReport := cagr(earnings[2], earnings[1])
+	expected := []types.PEGRecord{
+		peg(peRatios[0], allYears, 0.02),
+		// The 2024 report wasn't out yet, so growth ends on 2022.
+		peg(peRatios[1], beforeReport, 0),
+	}
+	result := HistoricalPEG(peRatios, earnings, quarterly, yields, 0, GrowthMethodEndpoints)
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("HistoricalPEG() mismatch (-want +got):\n%s", diff)
+	}
+
+	// A two year window starts the latest day's growth at 2022 instead of 2020.
+	windowed := HistoricalPEG(peRatios[:1], earnings, quarterly, yields, 2, GrowthMethodEndpoints)
+	expected = []types.PEGRecord{peg(peRatios[0], cagr(earnings[1], earnings[0]), 0.02)}
+	if diff := cmp.Diff(expected, windowed, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("HistoricalPEG() windowed mismatch (-want +got):\n%s", diff)
+	}
+
+	// Regression growth fits all three years, and needs all three known to give a PEG at all.
+	fit, err := RegressionCAGR(earnings)
+	if err != nil {
+		t.Fatalf("RegressionCAGR() returned an unexpected error: %v", err)
+	}
+	regression := HistoricalPEG(peRatios, earnings, quarterly, yields, 0, GrowthMethodRegression)
+	expected = []types.PEGRecord{peg(peRatios[0], fit.CAGR, 0.02)}
+	if diff := cmp.Diff(expected, regression, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("HistoricalPEG() regression mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given EPS that shrank, verify there is no PEG rather than a negative one.
+func TestHistoricalPEG_ShrinkingEarnings(t *testing.T) {
+	peRatios := []types.PERatioRecord{{Ticker: "TEST", Date: "2025-01-02", Ratio: 30, EPS: 4}}
+	earnings := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", ReportedEPS: 4},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 6},
+	}
+
+	if result := HistoricalPEG(peRatios, earnings, nil, nil, 0, GrowthMethodEndpoints); len(result) != 0 {
+		t.Errorf("Expected no PEG on shrinking earnings, got %+v", result)
+	}
+}
@@ -0,0 +1,203 @@
+package algos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Price to Sales (P/S) fair value. Where the Lynch model values a company on its earnings, this one
+values it on revenue, which makes it usable for companies that aren't (yet) reliably profitable.
+
+Fair Value Price = Revenue Per Share × Historical P/S Multiple
+
+General calculation flow process:
+	1. Get annual revenue and shares outstanding (split adjusted)
+	2. Calculate revenue per share for each fiscal year
+		RevenuePerShare(t) = TotalRevenue(t) / SharesOutstanding(t)
+	3. Calculate the P/S ratio the market paid at each fiscal year end
+		P/S(t) = ClosingPrice(t) / RevenuePerShare(t)
+	4. Collapse the P/S ratios over a chosen window of years into one multiple (mean or median)
+	5. Calculate fair value prices to create a curve
+		FairValuePrice(t) = RevenuePerShare(t) × P/S Multiple
+
+Which is to say the model answers "what would the stock cost if the market paid its usual price
+for each dollar of sales". Median is the default since a single bubble year can drag the mean
+a long way.
+*/
+
+const (
+	StatisticMean   = "mean"
+	StatisticMedian = "median"
+)
+
+// Not enough revenue history to derive a historical multiple from.
+var ErrInsufficientRevenue = errors.New("insufficient revenue history")
+
+type PriceToSalesRecord struct {
+	Date            string
+	ClosingPrice    float64
+	RevenuePerShare float64
+	Ratio           float64
+}
+
+/*
+Joins revenue and shares outstanding on fiscal date. Years missing either side, or with no shares,
+are skipped since a per share number can't be made for them.
+*/
+func RevenuePerShare(revenues []types.AnnualRevenueRecord, shares []types.SharesOutstandingRecord) []types.RevenuePerShareRecord {
+	sharesByDate := make(map[string]float64, len(shares))
+	for _, record := range shares {
+		sharesByDate[record.FiscalDateEnding] = record.SharesOutstanding
+	}
+
+	var revenuePerShare []types.RevenuePerShareRecord
+	for _, revenue := range revenues {
+		shareCount, ok := sharesByDate[revenue.FiscalDateEnding]
+		if !ok || shareCount <= 0 {
+			continue
+		}
+		revenuePerShare = append(revenuePerShare, types.RevenuePerShareRecord{
+			Ticker:           revenue.Ticker,
+			FiscalDateEnding: revenue.FiscalDateEnding,
+			RevenuePerShare:  revenue.TotalRevenue / shareCount,
+		})
+	}
+
+	sort.Slice(revenuePerShare, func(i, j int) bool {
+		return revenuePerShare[i].FiscalDateEnding < revenuePerShare[j].FiscalDateEnding
+	})
+
+	return revenuePerShare
+}
+
+/*
+Calculates the P/S ratio at each fiscal year end, using the last close on or before the fiscal
+date since fiscal years often end on a weekend. Years without a price or with non positive
+revenue are skipped.
+*/
+func PriceToSalesRatios(dailyPrices []types.DailyStockRecord, revenuePerShare []types.RevenuePerShareRecord) []PriceToSalesRecord {
+	var ratios []PriceToSalesRecord
+	for _, record := range revenuePerShare {
+		if record.RevenuePerShare <= 0 {
+			continue
+		}
+		price, ok := ClosingPriceOnOrBefore(dailyPrices, record.FiscalDateEnding)
+		if !ok {
+			continue
+		}
+		ratios = append(ratios, PriceToSalesRecord{
+			Date:            record.FiscalDateEnding,
+			ClosingPrice:    price,
+			RevenuePerShare: record.RevenuePerShare,
+			Ratio:           price / record.RevenuePerShare,
+		})
+	}
+	return ratios
+}
+
+/*
+Finds the closing price on the given date, or the most recent trading day before it. Prices can
+be in any order.
+*/
+func ClosingPriceOnOrBefore(dailyPrices []types.DailyStockRecord, date string) (float64, bool) {
+	bestDate := ""
+	bestPrice := 0.0
+	for _, price := range dailyPrices {
+		if price.Date <= date && price.Date > bestDate {
+			bestDate = price.Date
+			bestPrice = price.ClosingPrice
+		}
+	}
+	return bestPrice, bestDate != ""
+}
+
+/*
+Collapses a set of P/S ratios into a single multiple. windowYears limits the ratios used to those
+within that many years of the most recent one, zero or less uses all of them. An empty statistic
+uses the median.
+*/
+func PriceToSalesMultiple(ratios []PriceToSalesRecord, windowYears int, statistic string) (float64, error) {
+	minimumYearsRequired := 2
+	windowed := ratiosInWindow(ratios, windowYears)
+	if len(windowed) < minimumYearsRequired {
+		return 0, fmt.Errorf("%w: not enough priced fiscal years. Minimum: %d", ErrInsufficientRevenue, minimumYearsRequired)
+	}
+
+	values := make([]float64, len(windowed))
+	for i, ratio := range windowed {
+		values[i] = ratio.Ratio
+	}
+
+	switch statistic {
+	case StatisticMean:
+		return Mean(values), nil
+	case StatisticMedian, "":
+		return Median(values), nil
+	default:
+		return 0, fmt.Errorf("unknown statistic '%s', expected '%s' or '%s'", statistic, StatisticMean, StatisticMedian)
+	}
+}
+
+func ratiosInWindow(ratios []PriceToSalesRecord, windowYears int) []PriceToSalesRecord {
+	if windowYears <= 0 || len(ratios) == 0 {
+		return ratios
+	}
+
+	latest := ""
+	for _, ratio := range ratios {
+		if ratio.Date > latest {
+			latest = ratio.Date
+		}
+	}
+	latestDate, err := time.Parse("2006-01-02", latest)
+	if err != nil {
+		return ratios
+	}
+	// A week of slack so a window of N years keeps N+1 fiscal year ends even when they land on
+	// slightly different days, e.g. the last Saturday of September.
+	cutoff := latestDate.AddDate(-windowYears, 0, -7).Format("2006-01-02")
+
+	var windowed []PriceToSalesRecord
+	for _, ratio := range ratios {
+		if ratio.Date >= cutoff {
+			windowed = append(windowed, ratio)
+		}
+	}
+	return windowed
+}
+
+/*
+Pipeline function that orchestrates the full P/S fair value calculation process. Returns the
+fair value history along with the multiple used so callers can report it.
+*/
+func CalculatePriceToSalesFairValueHistory(
+	dailyPrices []types.DailyStockRecord,
+	revenuePerShare []types.RevenuePerShareRecord,
+	windowYears int,
+	statistic string,
+) ([]types.FairValuePriceRecord, float64, error) {
+	ratios := PriceToSalesRatios(dailyPrices, revenuePerShare)
+	multiple, err := PriceToSalesMultiple(ratios, windowYears, statistic)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to calculate P/S multiple: %w", err)
+	}
+
+	var fairValueHistory []types.FairValuePriceRecord
+	for _, record := range revenuePerShare {
+		if record.RevenuePerShare <= 0 {
+			continue
+		}
+		fairValueHistory = append(fairValueHistory, types.FairValuePriceRecord{
+			Ticker:         record.Ticker,
+			FairValuePrice: record.RevenuePerShare * multiple,
+			Date:           record.FiscalDateEnding,
+		})
+	}
+
+	return fairValueHistory, multiple, nil
+}
@@ -0,0 +1,142 @@
+package algos
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given revenue and share counts with a year missing from each side, verify only matching years
+// are joined and the result is sorted oldest first.
+func TestRevenuePerShare(t *testing.T) {
+	revenues := []types.AnnualRevenueRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", TotalRevenue: 1000},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", TotalRevenue: 600},
+		{Ticker: "TEST", FiscalDateEnding: "2021-12-31", TotalRevenue: 500}, // No shares reported
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", TotalRevenue: 800},
+	}
+	shares := []types.SharesOutstandingRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", SharesOutstanding: 100},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", SharesOutstanding: 100},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", SharesOutstanding: 0},   // Can't divide by zero shares
+		{Ticker: "TEST", FiscalDateEnding: "2020-12-31", SharesOutstanding: 100}, // No revenue reported
+	}
+
+	expected := []types.RevenuePerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", RevenuePerShare: 8},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", RevenuePerShare: 10},
+	}
+
+	if diff := cmp.Diff(expected, RevenuePerShare(revenues, shares)); diff != "" {
+		t.Errorf("RevenuePerShare() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a fiscal date that lands on a weekend, verify the previous trading day's close is used.
+func TestClosingPriceOnOrBefore(t *testing.T) {
+	prices := []types.DailyStockRecord{
+		{Date: "2024-09-30", ClosingPrice: 233},
+		{Date: "2024-09-27", ClosingPrice: 227},
+		{Date: "2024-09-26", ClosingPrice: 227.5},
+	}
+
+	price, ok := ClosingPriceOnOrBefore(prices, "2024-09-28")
+	if !ok || price != 227 {
+		t.Errorf("Expected the 2024-09-27 close of 227, got %v (found: %v)", price, ok)
+	}
+	if _, ok := ClosingPriceOnOrBefore(prices, "2024-09-01"); ok {
+		t.Error("Expected no price for a date before all prices")
+	}
+}
+
+var mockRatios = []PriceToSalesRecord{
+	{Date: "2020-12-31", Ratio: 10},
+	{Date: "2021-12-31", Ratio: 2},
+	{Date: "2022-12-31", Ratio: 3},
+	{Date: "2023-12-31", Ratio: 4},
+	{Date: "2024-12-31", Ratio: 6},
+}
+
+// Given a set of ratios with one outlier, verify the mean and median are calculated over the
+// chosen window.
+func TestPriceToSalesMultiple(t *testing.T) {
+	tests := []struct {
+		name        string
+		windowYears int
+		statistic   string
+		expected    float64
+	}{
+		{name: "median of all years", windowYears: 0, statistic: StatisticMedian, expected: 4},
+		{name: "mean of all years", windowYears: 0, statistic: StatisticMean, expected: 5},
+		{name: "empty statistic uses median", windowYears: 0, statistic: "", expected: 4},
+		{name: "two year window", windowYears: 2, statistic: StatisticMean, expected: 13.0 / 3.0},
+		{name: "median of an even count", windowYears: 3, statistic: StatisticMedian, expected: 3.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multiple, err := PriceToSalesMultiple(mockRatios, tt.windowYears, tt.statistic)
+			if err != nil {
+				t.Fatalf("PriceToSalesMultiple() returned an unexpected error: %v", err)
+			}
+			if math.Abs(multiple-tt.expected) > 1e-9 {
+				t.Errorf("Expected multiple %v, got %v", tt.expected, multiple)
+			}
+		})
+	}
+}
+
+// Given too few ratios or an unknown statistic, verify an error is returned.
+func TestPriceToSalesMultiple_Errors(t *testing.T) {
+	_, err := PriceToSalesMultiple(mockRatios[:1], 0, StatisticMedian)
+	if !errors.Is(err, ErrInsufficientRevenue) {
+		t.Errorf("Expected an insufficient revenue error, but got: %v", err)
+	}
+	if _, err := PriceToSalesMultiple(mockRatios, 0, "mode"); err == nil {
+		t.Error("Expected an error for an unknown statistic, but got none")
+	}
+}
+
+// Given prices and revenue per share, verify the fair value is revenue per share times the
+// median historical P/S multiple.
+func TestCalculatePriceToSalesFairValueHistory(t *testing.T) {
+	prices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2024-12-31", ClosingPrice: 60},
+		{Ticker: "TEST", Date: "2023-12-29", ClosingPrice: 30},
+		{Ticker: "TEST", Date: "2022-12-30", ClosingPrice: 40},
+	}
+	revenuePerShare := []types.RevenuePerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", RevenuePerShare: 10}, // P/S 4
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", RevenuePerShare: 10}, // P/S 3
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", RevenuePerShare: 12}, // P/S 5
+	}
+
+	expected := []types.FairValuePriceRecord{
+		{Ticker: "TEST", Date: "2022-12-31", FairValuePrice: 40},
+		{Ticker: "TEST", Date: "2023-12-31", FairValuePrice: 40},
+		{Ticker: "TEST", Date: "2024-12-31", FairValuePrice: 48},
+	}
+
+	history, multiple, err := CalculatePriceToSalesFairValueHistory(prices, revenuePerShare, 0, StatisticMedian)
+	if err != nil {
+		t.Fatalf("CalculatePriceToSalesFairValueHistory() returned an unexpected error: %v", err)
+	}
+	if multiple != 4 {
+		t.Errorf("Expected a multiple of 4, got %v", multiple)
+	}
+	if diff := cmp.Diff(expected, history, cmpopts.EquateApprox(0.001, 0)); diff != "" {
+		t.Errorf("CalculatePriceToSalesFairValueHistory() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given empty input, verify mean and median are NaN rather than zero.
+func TestMeanMedian_Empty(t *testing.T) {
+	if !math.IsNaN(Mean(nil)) || !math.IsNaN(Median(nil)) {
+		t.Error("Expected NaN for the mean and median of no values")
+	}
+}
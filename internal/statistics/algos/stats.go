@@ -0,0 +1,77 @@
+package algos
+
+import (
+	"math"
+	"sort"
+)
+
+// Small descriptive statistics helpers shared by the valuation models. Empty input returns NaN
+// rather than an error, callers are expected to check they have enough data first.
+
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// The pth percentile (0 to 100), interpolating linearly between the two closest values.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Share of values at or below value, as a percentage.
+func PercentileRank(values []float64, value float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	atOrBelow := 0
+	for _, v := range values {
+		if v <= value {
+			atOrBelow++
+		}
+	}
+	return float64(atOrBelow) / float64(len(values)) * 100
+}
+
+// Sample standard deviation, NaN with fewer than two values.
+func StandardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+	mean := Mean(values)
+	sumSquares := 0.0
+	for _, value := range values {
+		sumSquares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
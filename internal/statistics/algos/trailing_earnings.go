@@ -0,0 +1,58 @@
+package algos
+
+import (
+	"sort"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Trailing twelve month (TTM) EPS is the sum of the latest four quarters. Unlike annual EPS it moves
+every quarter, so a fair value curve built on it reacts to a bad quarter within weeks instead of
+waiting for the fiscal year to close.
+
+	TTM EPS(q) = EPS(q) + EPS(q-1) + EPS(q-2) + EPS(q-3)
+*/
+
+// Quarters summed into one TTM value.
+const ttmQuarters = 4
+
+/*
+Rolling TTM EPS as of each fiscal quarter end, oldest first. The records reuse AnnualEarningRecord
+since a TTM value is a twelve month EPS like an annual one, so they can go straight into the Lynch
+fair value calculation. A quarter only gets a value when it and the three before it all fall
+within one year, so a gap in Alpha Vantage's history doesn't sum five quarters into one.
+*/
+func TrailingTwelveMonthEPS(quarterly []types.QuarterlyEarningRecord) []types.AnnualEarningRecord {
+	sorted := make([]types.QuarterlyEarningRecord, len(quarterly))
+	copy(sorted, quarterly)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FiscalDateEnding < sorted[j].FiscalDateEnding })
+
+	var ttm []types.AnnualEarningRecord
+	for i := ttmQuarters - 1; i < len(sorted); i++ {
+		latest := sorted[i]
+		oldest := sorted[i-ttmQuarters+1]
+
+		latestDate, err := time.Parse("2006-01-02", latest.FiscalDateEnding)
+		if err != nil {
+			continue
+		}
+		oldestDate, err := time.Parse("2006-01-02", oldest.FiscalDateEnding)
+		if err != nil || !oldestDate.After(latestDate.AddDate(-1, 0, 0)) {
+			continue
+		}
+
+		sum := 0.0
+		for _, quarter := range sorted[i-ttmQuarters+1 : i+1] {
+			sum += quarter.ReportedEPS
+		}
+		ttm = append(ttm, types.AnnualEarningRecord{
+			Ticker:           latest.Ticker,
+			FiscalDateEnding: latest.FiscalDateEnding,
+			ReportedEPS:      sum,
+		})
+	}
+
+	return ttm
+}
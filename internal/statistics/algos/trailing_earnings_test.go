@@ -0,0 +1,38 @@
+package algos
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Given quarters newest first with a missing quarter in the history, verify each TTM value sums
+// four consecutive quarters and no value spans the gap.
+func TestTrailingTwelveMonthEPS(t *testing.T) {
+	quarterly := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-09-30", ReportedEPS: 1.5},
+		{Ticker: "TEST", FiscalDateEnding: "2024-06-30", ReportedEPS: 1.4},
+		{Ticker: "TEST", FiscalDateEnding: "2024-03-31", ReportedEPS: 1.3},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", ReportedEPS: 1.2},
+		{Ticker: "TEST", FiscalDateEnding: "2023-09-30", ReportedEPS: 1.1},
+		{Ticker: "TEST", FiscalDateEnding: "2023-06-30", ReportedEPS: 1.0},
+		// 2023-03-31 is missing
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", ReportedEPS: 0.9},
+		{Ticker: "TEST", FiscalDateEnding: "2022-09-30", ReportedEPS: 0.8},
+	}
+
+	expected := []types.AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-03-31", ReportedEPS: 4.6},
+		{Ticker: "TEST", FiscalDateEnding: "2024-06-30", ReportedEPS: 5.0},
+		{Ticker: "TEST", FiscalDateEnding: "2024-09-30", ReportedEPS: 5.4},
+	}
+
+	result := TrailingTwelveMonthEPS(quarterly)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("TrailingTwelveMonthEPS() mismatch (-want +got):\n%s", diff)
+	}
+}
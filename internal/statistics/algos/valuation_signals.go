@@ -0,0 +1,163 @@
+package algos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"cibo/internal/types"
+)
+
+/*
+How the close has stood against the daily fair value over a range, and the days it crossed into an
+over or undervalued band. Both work on the price to fair value ratio
+
+	Ratio(t) = ClosingPrice(t) / FairValue(t)
+
+where 1 is fairly priced. The statistics say whether today's ratio is unusual for this company,
+e.g. a stock that has always traded at 1.5 is less stretched at 1.6 than its premium suggests. The
+bands are fixed premiums either side of the fair value, overvalued at or above +OvervaluedPct and
+undervalued at or below −UndervaluedPct.
+
+A signal is emitted on the first day in a band after a day outside it, so a close that stays in a
+band gives one signal, not one a day. The first day of the range only sets where the close starts,
+it hasn't crossed into anything. Days without a positive fair value are left out of both, a premium
+over a fair value of zero or below doesn't say anything about the price.
+*/
+
+const DefaultValuationBandPct = 20.0
+
+// The over and undervalued bands can't be used together or are out of range.
+var ErrInvalidValuationBands = errors.New("invalid valuation bands")
+
+// Premium over the fair value, in percent, at which the close counts as over or undervalued. Both
+// are distances from the fair value, e.g. 20 and 20 for +20% and −20%. Zero uses the default.
+type ValuationBands struct {
+	OvervaluedPct  float64 `json:"overvalued_pct"`
+	UndervaluedPct float64 `json:"undervalued_pct"`
+}
+
+// Fills in the defaults for zero values.
+func (b ValuationBands) Resolve() ValuationBands {
+	if b.OvervaluedPct == 0 {
+		b.OvervaluedPct = DefaultValuationBandPct
+	}
+	if b.UndervaluedPct == 0 {
+		b.UndervaluedPct = DefaultValuationBandPct
+	}
+	return b
+}
+
+// Validates resolved bands. A close can't be more than 100% below a positive fair value.
+func (b ValuationBands) Validate() error {
+	switch {
+	case b.OvervaluedPct <= 0:
+		return fmt.Errorf("%w: overvalued band must be positive, got %.2f", ErrInvalidValuationBands, b.OvervaluedPct)
+	case b.UndervaluedPct <= 0 || b.UndervaluedPct >= 100:
+		return fmt.Errorf("%w: undervalued band must be between 0 and 100, got %.2f", ErrInvalidValuationBands, b.UndervaluedPct)
+	}
+	return nil
+}
+
+// Distribution of the price to fair value ratio over a range, and where the latest day sits in it.
+type PremiumStatistics struct {
+	Days   int     `json:"days"`
+	Mean   float64 `json:"mean_ratio"`
+	Stdev  float64 `json:"stdev_ratio"`
+	Median float64 `json:"median_ratio"`
+	P10    float64 `json:"p10_ratio"`
+	P25    float64 `json:"p25_ratio"`
+	P75    float64 `json:"p75_ratio"`
+	P90    float64 `json:"p90_ratio"`
+	// The last day's ratio, its distance from the mean in standard deviations and the percentage of
+	// days at or below it.
+	LatestDate       string  `json:"latest_date"`
+	LatestRatio      float64 `json:"latest_ratio"`
+	LatestZScore     float64 `json:"latest_z_score"`
+	LatestPercentile float64 `json:"latest_percentile"`
+}
+
+// The records with a positive fair value to measure a premium against.
+func pricedRecords(records []types.DailyFairValueRecord) []types.DailyFairValueRecord {
+	priced := make([]types.DailyFairValueRecord, 0, len(records))
+	for _, record := range records {
+		if record.FairValuePrice > 0 {
+			priced = append(priced, record)
+		}
+	}
+	return priced
+}
+
+func premiumRatio(record types.DailyFairValueRecord) float64 {
+	return 1 + record.PremiumPct/100
+}
+
+// Summarises the ratio over records, false when there are none. The z-score is 0 when every day had
+// the same ratio, or there was only one day.
+func CalculatePremiumStatistics(records []types.DailyFairValueRecord) (PremiumStatistics, bool) {
+	records = pricedRecords(records)
+	latest, ok := LatestDailyFairValue(records)
+	if !ok {
+		return PremiumStatistics{}, false
+	}
+	ratios := make([]float64, len(records))
+	for i, record := range records {
+		ratios[i] = premiumRatio(record)
+	}
+
+	stats := PremiumStatistics{
+		Days:             len(ratios),
+		Mean:             Mean(ratios),
+		Median:           Median(ratios),
+		P10:              Percentile(ratios, 10),
+		P25:              Percentile(ratios, 25),
+		P75:              Percentile(ratios, 75),
+		P90:              Percentile(ratios, 90),
+		LatestDate:       latest.Date,
+		LatestRatio:      premiumRatio(latest),
+		LatestPercentile: PercentileRank(ratios, premiumRatio(latest)),
+	}
+	if len(ratios) > 1 {
+		stats.Stdev = StandardDeviation(ratios)
+	}
+	if stats.Stdev > 0 {
+		stats.LatestZScore = (stats.LatestRatio - stats.Mean) / stats.Stdev
+	}
+	return stats, true
+}
+
+// The band a premium falls in, empty between the bands.
+func valuationBand(premiumPct float64, bands ValuationBands) string {
+	switch {
+	case premiumPct >= bands.OvervaluedPct:
+		return types.SignalOvervalued
+	case premiumPct <= -bands.UndervaluedPct:
+		return types.SignalUndervalued
+	default:
+		return ""
+	}
+}
+
+// The days records crossed into a band, see above. Returns the signals sorted by date.
+func ValuationSignals(records []types.DailyFairValueRecord, bands ValuationBands) []types.ValuationSignalRecord {
+	sorted := pricedRecords(records)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	var signals []types.ValuationSignalRecord
+	previous := ""
+	for i, record := range sorted {
+		band := valuationBand(record.PremiumPct, bands)
+		if i > 0 && band != "" && band != previous {
+			signals = append(signals, types.ValuationSignalRecord{
+				Ticker:         record.Ticker,
+				Date:           record.Date,
+				Price:          record.Price,
+				FairValuePrice: record.FairValuePrice,
+				PremiumPct:     record.PremiumPct,
+				Signal:         band,
+			})
+		}
+		previous = band
+	}
+	return signals
+}
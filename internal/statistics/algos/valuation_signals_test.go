@@ -0,0 +1,166 @@
+package algos
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func premiumDay(date string, premiumPct float64) types.DailyFairValueRecord {
+	return types.DailyFairValueRecord{
+		Ticker:         "TEST",
+		Date:           date,
+		Price:          100 * (1 + premiumPct/100),
+		FairValuePrice: 100,
+		PremiumPct:     premiumPct,
+	}
+}
+
+// Given ratios of 1, 1.2, 0.8 and 1.4 with the highest last, verify the distribution and that the
+// latest day is ranked against it.
+func TestCalculatePremiumStatistics(t *testing.T) {
+	records := []types.DailyFairValueRecord{
+		premiumDay("2025-01-04", 40),
+		premiumDay("2025-01-01", 0),
+		premiumDay("2025-01-02", 20),
+		premiumDay("2025-01-03", -20),
+	}
+	stdev := math.Sqrt(0.2 / 3)
+	expected := PremiumStatistics{
+		Days:             4,
+		Mean:             1.1,
+		Stdev:            stdev,
+		Median:           1.1,
+		P10:              0.86,
+		P25:              0.95,
+		P75:              1.25,
+		P90:              1.34,
+		LatestDate:       "2025-01-04",
+		LatestRatio:      1.4,
+		LatestZScore:     0.3 / stdev,
+		LatestPercentile: 100,
+	}
+
+	stats, ok := CalculatePremiumStatistics(records)
+	if !ok {
+		t.Fatal("Expected statistics for four days")
+	}
+	if diff := cmp.Diff(expected, stats, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CalculatePremiumStatistics() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a single day, verify there's no spread to measure a z-score against, and given none, verify
+// there are no statistics.
+func TestCalculatePremiumStatistics_TooFewDays(t *testing.T) {
+	stats, ok := CalculatePremiumStatistics([]types.DailyFairValueRecord{premiumDay("2025-01-01", 30)})
+	if !ok || stats.Stdev != 0 || stats.LatestZScore != 0 {
+		t.Errorf("Expected a zero stdev and z-score for one day, got: %+v", stats)
+	}
+	if _, ok := CalculatePremiumStatistics(nil); ok {
+		t.Error("Expected no statistics without records")
+	}
+}
+
+// Given a close that starts overvalued, dips back, then swings between the bands, verify one signal
+// per crossing and none for the first day or for staying in a band.
+func TestValuationSignals(t *testing.T) {
+	records := []types.DailyFairValueRecord{
+		premiumDay("2025-01-08", -20),
+		premiumDay("2025-01-07", 0),
+		premiumDay("2025-01-06", -30),
+		premiumDay("2025-01-05", -25),
+		premiumDay("2025-01-04", 35),
+		premiumDay("2025-01-03", 30),
+		premiumDay("2025-01-02", 10),
+		premiumDay("2025-01-01", 25),
+	}
+	signal := func(date string, premiumPct float64, signal string) types.ValuationSignalRecord {
+		day := premiumDay(date, premiumPct)
+		return types.ValuationSignalRecord{
+			Ticker:         day.Ticker,
+			Date:           day.Date,
+			Price:          day.Price,
+			FairValuePrice: day.FairValuePrice,
+			PremiumPct:     day.PremiumPct,
+			Signal:         signal,
+		}
+	}
+	expected := []types.ValuationSignalRecord{
+		signal("2025-01-03", 30, types.SignalOvervalued),
+		signal("2025-01-05", -25, types.SignalUndervalued),
+		// Exactly on the band counts as in it.
+		signal("2025-01-08", -20, types.SignalUndervalued),
+	}
+
+	result := ValuationSignals(records, ValuationBands{}.Resolve())
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("ValuationSignals() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given days without a positive fair value around the ones with one, verify they neither signal nor
+// count as where the close starts, and are left out of the statistics.
+func TestValuationSignals_NonPositiveFairValue(t *testing.T) {
+	unpriced := func(date string, fairValue float64) types.DailyFairValueRecord {
+		return types.DailyFairValueRecord{
+			Ticker:         "TEST",
+			Date:           date,
+			Price:          100,
+			FairValuePrice: fairValue,
+			PremiumPct:     (100/fairValue - 1) * 100,
+		}
+	}
+	records := []types.DailyFairValueRecord{
+		unpriced("2025-01-01", 0),
+		premiumDay("2025-01-02", 10),
+		unpriced("2025-01-03", -50),
+		premiumDay("2025-01-04", 30),
+	}
+
+	expected := []types.ValuationSignalRecord{{
+		Ticker:         "TEST",
+		Date:           "2025-01-04",
+		Price:          130,
+		FairValuePrice: 100,
+		PremiumPct:     30,
+		Signal:         types.SignalOvervalued,
+	}}
+	result := ValuationSignals(records, ValuationBands{}.Resolve())
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("ValuationSignals() mismatch (-want +got):\n%s", diff)
+	}
+
+	stats, ok := CalculatePremiumStatistics(records)
+	if !ok || stats.Days != 2 || math.Abs(stats.Mean-1.2) > 1e-9 {
+		t.Errorf("Expected statistics over the 2 priced days with a mean ratio of 1.2, got %+v", stats)
+	}
+}
+
+// Given bands that can't be crossed, verify they're rejected.
+func TestValuationBands_Validate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		bands ValuationBands
+	}{
+		{"negative overvalued band", ValuationBands{OvervaluedPct: -10, UndervaluedPct: 20}},
+		{"negative undervalued band", ValuationBands{OvervaluedPct: 20, UndervaluedPct: -10}},
+		{"undervalued band below zero price", ValuationBands{OvervaluedPct: 20, UndervaluedPct: 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.bands.Validate(); !errors.Is(err, ErrInvalidValuationBands) {
+				t.Errorf("Expected %v, got: %v", ErrInvalidValuationBands, err)
+			}
+		})
+	}
+	if err := (ValuationBands{}).Resolve().Validate(); err != nil {
+		t.Errorf("Expected the default bands to be valid, got: %v", err)
+	}
+}
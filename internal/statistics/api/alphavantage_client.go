@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,20 +22,104 @@ type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	baseURL    string
+	limiter    *RateLimiter
+	retry      RetryPolicy
 }
 
-func NewClient(apiKey string, baseURL string) *Client {
-	return &Client{
+type ClientOption func(*Client)
+
+// Every call made by the client is budgeted against the given limiter before it hits the network.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+func NewClient(apiKey string, baseURL string, opts ...ClientOption) *Client {
+	client := &Client{
 		apiKey: apiKey,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 		baseURL: baseURL,
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// Shared request handling for all the Fetch methods. Returns the raw body of a 200 response that
+// isn't a throttle or error message, retrying transient failures per the client's RetryPolicy.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if sleepErr := sleepContext(ctx, c.retry.delayAfter(attempt-1, err)); sleepErr != nil {
+				return nil, sleepErr
+			}
+		}
+
+		var body []byte
+		body, err = c.getOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if !isRetryable(err) {
+			return nil, err
+		}
+	}
+
+	if attempts > 1 {
+		return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+	}
+	return nil, err
+}
+
+func (c *Client) getOnce(ctx context.Context, url string) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build API request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &requestError{err: err}
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := classifyBody(bodyBytes); err != nil {
+		if errors.Is(err, ErrRateLimited) && c.limiter != nil {
+			// Throttled calls don't return data, so they shouldn't use up the daily budget.
+			c.limiter.Refund()
+		}
+		return nil, err
+	}
+
+	return bodyBytes, nil
 }
 
 // Retrieve the raw daily time series data for a given stock symbol.
-func (c *Client) FetchDailyPrice(symbol string) ([]byte, error) {
+func (c *Client) FetchDailyPrice(ctx context.Context, symbol string) ([]byte, error) {
 	// {
 	// "Meta Data": {
 	//     "1. Information": "Daily Prices (open, high, low, close) and Volumes",
@@ -57,28 +143,11 @@ func (c *Client) FetchDailyPrice(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return bodyBytes, nil
+	return c.get(ctx, url)
 }
 
 // Retrieve the raw earnings data for a given stock symbol. Contains both annual and quarterly data.
-func (c *Client) FetchEarnings(symbol string) ([]byte, error) {
+func (c *Client) FetchEarnings(ctx context.Context, symbol string) ([]byte, error) {
 	// https://www.alphavantage.co/query?function=EARNINGS&symbol=IBM&apikey=demo -> "annualReports": []
 	// https://www.alphavantage.co/query?function=EARNINGS&symbol=IBM&apikey=demo -> "quarterlyEarnings": []
 	// {
@@ -106,27 +175,11 @@ func (c *Client) FetchEarnings(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return bodyBytes, nil
+	return c.get(ctx, url)
 }
 
 // Retrieve the overview of a whole company for a given stock symbol.
-func (c *Client) FetchOverview(symbol string) ([]byte, error) {
+func (c *Client) FetchOverview(ctx context.Context, symbol string) ([]byte, error) {
 	// https://www.alphavantage.co/query?function=OVERVIEW&symbol=IBM&apikey=demo -> {}
 	// 	{
 	// 	"Symbol": "IBM",
@@ -192,27 +245,11 @@ func (c *Client) FetchOverview(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return bodyBytes, nil
+	return c.get(ctx, url)
 }
 
 // Retrieve dividend data for a given stock symbol.
-func (c *Client) FetchDividends(symbol string) ([]byte, error) {
+func (c *Client) FetchDividends(ctx context.Context, symbol string) ([]byte, error) {
 	// https://www.alphavantage.co/query?function=DIVIDENDS&symbol=IBM&apikey=demo
 	// {
 	// "symbol": "IBM",
@@ -239,27 +276,11 @@ func (c *Client) FetchDividends(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return bodyBytes, nil
+	return c.get(ctx, url)
 }
 
 // Retrieve the next years earnings estimates for a given stock symbol
-func (c *Client) FetchEarningsEstimates(symbol string) ([]byte, error) {
+func (c *Client) FetchEarningsEstimates(ctx context.Context, symbol string) ([]byte, error) {
 	// https://www.alphavantage.co/query?function=EARNINGS_ESTIMATES&symbol=IBM&apikey=demo
 	// {
 	// "symbol": "IBM",
@@ -292,27 +313,28 @@ func (c *Client) FetchEarningsEstimates(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
+	return c.get(ctx, url)
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+// Retrieve the upcoming earnings reports for a given stock symbol over the next twelve months.
+// This is the one Alpha Vantage endpoint that answers in CSV rather than JSON.
+func (c *Client) FetchEarningsCalendar(ctx context.Context, symbol string) ([]byte, error) {
+	// https://www.alphavantage.co/query?function=EARNINGS_CALENDAR&symbol=IBM&horizon=12month&apikey=demo
+	// symbol,name,reportDate,fiscalDateEnding,estimate,currency,timeOfTheDay
+	// IBM,International Business Machines Corp,2025-10-22,2025-09-30,2.45,USD,post-market
+	// IBM,International Business Machines Corp,2026-01-28,2025-12-31,,USD,
+	url := fmt.Sprintf(
+		"%s/query?function=EARNINGS_CALENDAR&symbol=%s&horizon=12month&apikey=%s",
+		c.baseURL,
+		symbol,
+		c.apiKey,
+	)
 
-	return bodyBytes, nil
+	return c.get(ctx, url)
 }
 
 // Retrieve stock split data for a specific ticker
-func (c *Client) FetchStockSplits(symbol string) ([]byte, error) {
+func (c *Client) FetchStockSplits(ctx context.Context, symbol string) ([]byte, error) {
 	// https://www.alphavantage.co/query?function=SPLITS&symbol=NVDA&apikey=demo
 	// 	{
 	//     "symbol": "NVDA",
@@ -351,21 +373,81 @@ func (c *Client) FetchStockSplits(symbol string) ([]byte, error) {
 		c.apiKey,
 	)
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make API request: %w", err)
-	}
-	defer resp.Body.Close()
+	return c.get(ctx, url)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", resp.StatusCode, string(body))
-	}
+// Retrieve the raw income statements for a given stock symbol. Contains both annual and quarterly reports.
+func (c *Client) FetchIncomeStatement(ctx context.Context, symbol string) ([]byte, error) {
+	// https://www.alphavantage.co/query?function=INCOME_STATEMENT&symbol=IBM&apikey=demo
+	// {
+	//     "symbol": "IBM",
+	//     "annualReports": [
+	//         {
+	//             "fiscalDateEnding": "2024-12-31",
+	//             "reportedCurrency": "USD",
+	//             "grossProfit": "35551000000",
+	//             "totalRevenue": "62753000000",
+	//             "costOfRevenue": "27202000000",
+	//             ...
+	//             "netIncome": "6023000000"
+	//         },
+	//     "quarterlyReports": [
+	url := fmt.Sprintf(
+		"%s/query?function=INCOME_STATEMENT&symbol=%s&apikey=%s",
+		c.baseURL,
+		symbol,
+		c.apiKey,
+	)
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+	return c.get(ctx, url)
+}
 
-	return bodyBytes, nil
+// Retrieve the raw balance sheets for a given stock symbol. Contains both annual and quarterly reports.
+func (c *Client) FetchBalanceSheet(ctx context.Context, symbol string) ([]byte, error) {
+	// https://www.alphavantage.co/query?function=BALANCE_SHEET&symbol=IBM&apikey=demo
+	// {
+	//     "symbol": "IBM",
+	//     "annualReports": [
+	//         {
+	//             "fiscalDateEnding": "2024-12-31",
+	//             "reportedCurrency": "USD",
+	//             "totalAssets": "137175000000",
+	//             ...
+	//             "commonStockSharesOutstanding": "937200000"
+	//         },
+	//     "quarterlyReports": [
+	url := fmt.Sprintf(
+		"%s/query?function=BALANCE_SHEET&symbol=%s&apikey=%s",
+		c.baseURL,
+		symbol,
+		c.apiKey,
+	)
+
+	return c.get(ctx, url)
+}
+
+// Retrieve the raw cash flow statements for a given stock symbol. Contains both annual and quarterly reports.
+func (c *Client) FetchCashFlow(ctx context.Context, symbol string) ([]byte, error) {
+	// https://www.alphavantage.co/query?function=CASH_FLOW&symbol=IBM&apikey=demo
+	// {
+	//     "symbol": "IBM",
+	//     "annualReports": [
+	//         {
+	//             "fiscalDateEnding": "2024-12-31",
+	//             "reportedCurrency": "USD",
+	//             "operatingCashflow": "13445000000",
+	//             ...
+	//             "capitalExpenditures": "1685000000",
+	//             ...
+	//             "netIncome": "6023000000"
+	//         },
+	//     "quarterlyReports": [
+	url := fmt.Sprintf(
+		"%s/query?function=CASH_FLOW&symbol=%s&apikey=%s",
+		c.baseURL,
+		symbol,
+		c.apiKey,
+	)
+
+	return c.get(ctx, url)
 }
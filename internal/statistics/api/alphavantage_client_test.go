@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -27,8 +28,45 @@ func (er *errorReader) Read(p []byte) (n int, err error) {
 	return 0, errors.New("simulated read error")
 }
 
+// Blocks until the request context is done, like a slow server would.
+type HangingRoundTripper struct{}
+
+func (h *HangingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+// Always succeeds and counts how many requests made it to the network.
+type CountingRoundTripper struct {
+	calls   int
+	lastURL string
+}
+
+func (c *CountingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	c.lastURL = req.URL.String()
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+}
+
+// Replies with the given responses in order, repeating the last one once they run out.
+type SequenceRoundTripper struct {
+	statusCodes []int
+	bodies      []string
+	calls       int
+}
+
+func (s *SequenceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := min(s.calls, len(s.bodies)-1)
+	s.calls++
+	return &http.Response{StatusCode: s.statusCodes[i], Body: io.NopCloser(bytes.NewBufferString(s.bodies[i]))}, nil
+}
+
 var errSimulatedNetwork = errors.New("simulated network failure")
 
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+const perMinuteNote = `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute and 500 calls per day."}`
+
 // Given a valid symbol for daily prices, verify that the response body is returned correctly.
 func TestFetchDailyPrice_Success(t *testing.T) {
 	mockClient := &http.Client{
@@ -47,7 +85,7 @@ func TestFetchDailyPrice_Success(t *testing.T) {
 	}
 	expectedBody := []byte(`{"key":"value"}`)
 
-	body, err := apiClient.FetchDailyPrice("IBM")
+	body, err := apiClient.FetchDailyPrice(context.Background(), "IBM")
 
 	if err != nil {
 		t.Errorf("Did not expect an error but got: %v", err)
@@ -72,7 +110,7 @@ func TestFetchDailyPrice_NetworkError(t *testing.T) {
 		baseURL:    "base",
 	}
 
-	body, err := apiClient.FetchDailyPrice("GOOG")
+	body, err := apiClient.FetchDailyPrice(context.Background(), "GOOG")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -103,7 +141,7 @@ func TestFetchDailyPrice_APIReturnsNon200(t *testing.T) {
 	}
 	expectedError := "API returned non-200 status code: 404, body: {\"error\":\"symbol not found\"}"
 
-	body, err := apiClient.FetchDailyPrice("AAPL")
+	body, err := apiClient.FetchDailyPrice(context.Background(), "AAPL")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -134,7 +172,7 @@ func TestFetchDailyPrice_BodyReadError(t *testing.T) {
 	}
 	expectedError := "failed to read response body: simulated read error"
 
-	body, err := apiClient.FetchDailyPrice("TSLA")
+	body, err := apiClient.FetchDailyPrice(context.Background(), "TSLA")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -166,7 +204,7 @@ func TestFetchEarnings_Success(t *testing.T) {
 	}
 	expectedBody := []byte(`{"annualEarnings":[]}`)
 
-	body, err := apiClient.FetchEarnings("IBM")
+	body, err := apiClient.FetchEarnings(context.Background(), "IBM")
 
 	if err != nil {
 		t.Errorf("Did not expect an error but got: %v", err)
@@ -187,7 +225,7 @@ func TestFetchEarnings_NetworkError(t *testing.T) {
 		baseURL:    "base",
 	}
 
-	_, err := apiClient.FetchEarnings("IBM")
+	_, err := apiClient.FetchEarnings(context.Background(), "IBM")
 
 	if !errors.Is(err, errSimulatedNetwork) {
 		t.Errorf("Expected error to be '%v', but got '%v'", errSimulatedNetwork, err)
@@ -212,7 +250,7 @@ func TestFetchEarnings_APIReturnsNon200(t *testing.T) {
 	}
 	expectedError := "API returned non-200 status code: 404, body: {\"error\":\"symbol not found\"}"
 
-	body, err := apiClient.FetchEarnings("AAPL")
+	body, err := apiClient.FetchEarnings(context.Background(), "AAPL")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -243,7 +281,7 @@ func TestFetchEarnings_BodyReadError(t *testing.T) {
 	}
 	expectedError := "failed to read response body: simulated read error"
 
-	body, err := apiClient.FetchEarnings("TSLA")
+	body, err := apiClient.FetchEarnings(context.Background(), "TSLA")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -275,7 +313,7 @@ func TestFetchOverview_Success(t *testing.T) {
 	}
 	expectedBody := []byte(`{"Symbol":"IBM"}`)
 
-	body, err := apiClient.FetchOverview("IBM")
+	body, err := apiClient.FetchOverview(context.Background(), "IBM")
 
 	if err != nil {
 		t.Errorf("Did not expect an error but got: %v", err)
@@ -302,7 +340,7 @@ func TestFetchOverview_APIReturnsNon200(t *testing.T) {
 	}
 	expectedError := "API returned non-200 status code: 400, body: {\"error\":\"bad request\"}"
 
-	_, err := apiClient.FetchOverview("IBM")
+	_, err := apiClient.FetchOverview(context.Background(), "IBM")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -323,7 +361,7 @@ func TestFetchOverview_NetworkError(t *testing.T) {
 		baseURL:    "base",
 	}
 
-	_, err := apiClient.FetchOverview("IBM")
+	_, err := apiClient.FetchOverview(context.Background(), "IBM")
 
 	if !errors.Is(err, errSimulatedNetwork) {
 		t.Errorf("Expected error to be '%v', but got '%v'", errSimulatedNetwork, err)
@@ -348,7 +386,7 @@ func TestFetchOverview_BodyReadError(t *testing.T) {
 	}
 	expectedError := "failed to read response body: simulated read error"
 
-	body, err := apiClient.FetchOverview("TSLA")
+	body, err := apiClient.FetchOverview(context.Background(), "TSLA")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -380,7 +418,7 @@ func TestFetchDividends_Success(t *testing.T) {
 	}
 	expectedBody := []byte(`{"data":[]}`)
 
-	body, err := apiClient.FetchDividends("IBM")
+	body, err := apiClient.FetchDividends(context.Background(), "IBM")
 
 	if err != nil {
 		t.Errorf("Did not expect an error but got: %v", err)
@@ -407,7 +445,7 @@ func TestFetchDividends_BodyReadError(t *testing.T) {
 	}
 	expectedError := "failed to read response body: simulated read error"
 
-	_, err := apiClient.FetchDividends("IBM")
+	_, err := apiClient.FetchDividends(context.Background(), "IBM")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -434,7 +472,7 @@ func TestFetchDividend_APIReturnsNon200(t *testing.T) {
 	}
 	expectedError := "API returned non-200 status code: 400, body: {\"error\":\"bad request\"}"
 
-	_, err := apiClient.FetchDividends("IBM")
+	_, err := apiClient.FetchDividends(context.Background(), "IBM")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -455,7 +493,7 @@ func TestFetchDividend_NetworkError(t *testing.T) {
 		baseURL:    "base",
 	}
 
-	_, err := apiClient.FetchDividends("IBM")
+	_, err := apiClient.FetchDividends(context.Background(), "IBM")
 
 	if !errors.Is(err, errSimulatedNetwork) {
 		t.Errorf("Expected error to be '%v', but got '%v'", errSimulatedNetwork, err)
@@ -480,7 +518,7 @@ func TestFetchEarningsEstimates_Success(t *testing.T) {
 	}
 	expectedBody := []byte(`{"estimates":[]}`)
 
-	body, err := apiClient.FetchEarningsEstimates("IBM")
+	body, err := apiClient.FetchEarningsEstimates(context.Background(), "IBM")
 
 	if err != nil {
 		t.Errorf("Did not expect an error but got: %v", err)
@@ -501,7 +539,7 @@ func TestFetchEarningsEstimates_NetworkError(t *testing.T) {
 		baseURL:    "base",
 	}
 
-	_, err := apiClient.FetchEarningsEstimates("IBM")
+	_, err := apiClient.FetchEarningsEstimates(context.Background(), "IBM")
 
 	if !errors.Is(err, errSimulatedNetwork) {
 		t.Errorf("Expected error to be '%v', but got '%v'", errSimulatedNetwork, err)
@@ -525,7 +563,7 @@ func TestFetchEarningsEstimate_APIReturnsNon200(t *testing.T) {
 	}
 	expectedError := "API returned non-200 status code: 400, body: {\"error\":\"bad request\"}"
 
-	_, err := apiClient.FetchEarningsEstimates("IBM")
+	_, err := apiClient.FetchEarningsEstimates(context.Background(), "IBM")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -552,7 +590,7 @@ func TestFetchEarningsEstimate_BodyReadError(t *testing.T) {
 	}
 	expectedError := "failed to read response body: simulated read error"
 
-	_, err := apiClient.FetchEarningsEstimates("IBM")
+	_, err := apiClient.FetchEarningsEstimates(context.Background(), "IBM")
 
 	if err == nil {
 		t.Fatal("Expected an error, but got none.")
@@ -564,6 +602,228 @@ func TestFetchEarningsEstimate_BodyReadError(t *testing.T) {
 
 //----------------------------------
 
+// Given the fundamentals fetches, verify each one requests its Alpha Vantage function for the symbol.
+func TestFetchFundamentals_RequestsFunction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fetch    func(*Client) ([]byte, error)
+		expected string
+	}{
+		{"income statement", func(c *Client) ([]byte, error) { return c.FetchIncomeStatement(context.Background(), "IBM") },
+			"base/query?function=INCOME_STATEMENT&symbol=IBM&apikey=test_api_key"},
+		{"balance sheet", func(c *Client) ([]byte, error) { return c.FetchBalanceSheet(context.Background(), "IBM") },
+			"base/query?function=BALANCE_SHEET&symbol=IBM&apikey=test_api_key"},
+		{"cash flow", func(c *Client) ([]byte, error) { return c.FetchCashFlow(context.Background(), "IBM") },
+			"base/query?function=CASH_FLOW&symbol=IBM&apikey=test_api_key"},
+		{"earnings calendar", func(c *Client) ([]byte, error) { return c.FetchEarningsCalendar(context.Background(), "IBM") },
+			"base/query?function=EARNINGS_CALENDAR&symbol=IBM&horizon=12month&apikey=test_api_key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &CountingRoundTripper{}
+			apiClient := &Client{
+				apiKey:     "test_api_key",
+				httpClient: &http.Client{Transport: transport},
+				baseURL:    "base",
+			}
+
+			body, err := tc.fetch(apiClient)
+
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+			if string(body) != `{}` {
+				t.Errorf("Expected the response body, got: %s", string(body))
+			}
+			if diff := cmp.Diff(tc.expected, transport.lastURL); diff != "" {
+				t.Errorf("Request URL mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//----------------------------------
+
+// Given a context that times out while the request is in flight, verify that the fetch gives up
+// and returns the context error.
+func TestFetch_ContextDeadline(t *testing.T) {
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: &HangingRoundTripper{}},
+		baseURL:    "base",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	body, err := apiClient.FetchStockSplits(ctx, "IBM")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a context deadline error, but got: %v", err)
+	}
+	if body != nil {
+		t.Errorf("Expected body to be nil but got: %s", string(body))
+	}
+}
+
+// Given a client whose rate limiter has no daily budget left, verify that the fetch fails with a
+// quota error and never reaches the network.
+func TestFetch_QuotaExhaustedSkipsNetwork(t *testing.T) {
+	transport := &CountingRoundTripper{}
+	limiter, _ := NewRateLimiter(0, 1, "")
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "base",
+		limiter:    limiter,
+	}
+
+	if _, err := apiClient.FetchEarnings(context.Background(), "IBM"); err != nil {
+		t.Fatalf("Did not expect an error on the first call but got: %v", err)
+	}
+	_, err := apiClient.FetchEarnings(context.Background(), "IBM")
+
+	if !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Expected a quota exhausted error, but got: %v", err)
+	}
+	if transport.calls != 1 {
+		t.Errorf("Expected exactly 1 network call, got %d", transport.calls)
+	}
+}
+
+// Given a per minute throttle note followed by real data, verify the call is retried and the data
+// is returned.
+func TestFetch_RetriesRateLimitNote(t *testing.T) {
+	transport := &SequenceRoundTripper{
+		statusCodes: []int{http.StatusOK, http.StatusOK},
+		bodies:      []string{perMinuteNote, `{"symbol":"IBM"}`},
+	}
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "base",
+		retry:      fastRetries,
+	}
+
+	body, err := apiClient.FetchEarnings(context.Background(), "IBM")
+
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if string(body) != `{"symbol":"IBM"}` {
+		t.Errorf("Expected the retried body, got: %s", string(body))
+	}
+	if transport.calls != 2 {
+		t.Errorf("Expected 2 network calls, got %d", transport.calls)
+	}
+}
+
+// Given a throttled call that succeeds on retry, verify the throttled attempt isn't charged to the
+// daily budget.
+func TestFetch_RateLimitedAttemptsAreRefunded(t *testing.T) {
+	transport := &SequenceRoundTripper{
+		statusCodes: []int{http.StatusOK, http.StatusOK},
+		bodies:      []string{perMinuteNote, `{"symbol":"IBM"}`},
+	}
+	limiter, _ := NewRateLimiter(0, 25, "")
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "base",
+		limiter:    limiter,
+		retry:      fastRetries,
+	}
+
+	if _, err := apiClient.FetchEarnings(context.Background(), "IBM"); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if remaining, _ := limiter.Remaining(); remaining != 24 {
+		t.Errorf("Expected only the successful call to be charged, got %d calls remaining", remaining)
+	}
+}
+
+// Given an API that keeps failing with server errors, verify the client stops at the attempt cap
+// and returns the last status error.
+func TestFetch_RetriesStopAtMaxAttempts(t *testing.T) {
+	transport := &SequenceRoundTripper{statusCodes: []int{http.StatusBadGateway}, bodies: []string{"bad gateway"}}
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "base",
+		retry:      fastRetries,
+	}
+
+	_, err := apiClient.FetchStockSplits(context.Background(), "IBM")
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected a 502 status error, but got: %v", err)
+	}
+	if transport.calls != 3 {
+		t.Errorf("Expected 3 network calls, got %d", transport.calls)
+	}
+}
+
+// Given responses that will never succeed, verify they are returned as typed errors without
+// spending any retries.
+func TestFetch_PermanentFailuresAreNotRetried(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   error
+	}{
+		{"daily limit", http.StatusOK, `{"Information": "Our standard API rate limit is 25 requests per day."}`, ErrQuotaExhausted},
+		{"invalid symbol", http.StatusOK, `{"Error Message": "Invalid API call."}`, ErrInvalidCall},
+		{"premium endpoint", http.StatusOK, `{"Information": "This is a premium endpoint."}`, ErrPremiumEndpoint},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &SequenceRoundTripper{statusCodes: []int{tc.statusCode}, bodies: []string{tc.body}}
+			apiClient := &Client{
+				apiKey:     "test_api_key",
+				httpClient: &http.Client{Transport: transport},
+				baseURL:    "base",
+				retry:      fastRetries,
+			}
+
+			body, err := apiClient.FetchDailyPrice(context.Background(), "IBM")
+
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected error '%v', but got: %v", tc.expected, err)
+			}
+			if body != nil {
+				t.Errorf("Expected body to be nil but got: %s", string(body))
+			}
+			if transport.calls != 1 {
+				t.Errorf("Expected 1 network call, got %d", transport.calls)
+			}
+		})
+	}
+}
+
+// Given a 404, verify it is not retried since the resource won't appear on a second try.
+func TestFetch_ClientErrorsAreNotRetried(t *testing.T) {
+	transport := &SequenceRoundTripper{statusCodes: []int{http.StatusNotFound}, bodies: []string{"not found"}}
+	apiClient := &Client{
+		apiKey:     "test_api_key",
+		httpClient: &http.Client{Transport: transport},
+		baseURL:    "base",
+		retry:      fastRetries,
+	}
+
+	if _, err := apiClient.FetchDailyPrice(context.Background(), "IBM"); err == nil {
+		t.Fatal("Expected an error, but got none.")
+	}
+	if transport.calls != 1 {
+		t.Errorf("Expected 1 network call, got %d", transport.calls)
+	}
+}
+
+//----------------------------------
+
 // Given a new client, verify that a new client is created with the correct default values.
 func TestNewClient(t *testing.T) {
 	apiKey := "my-secret-key"
@@ -583,8 +843,16 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("NewClient() httpClient.Timeout mismatch (-want +got):\n%s", diff)
 	}
 
+	if client.limiter != nil {
+		t.Error("Expected no rate limiter unless one is passed as an option")
+	}
+
+	if diff := cmp.Diff(DefaultRetryPolicy, client.retry); diff != "" {
+		t.Errorf("NewClient() retry policy mismatch (-want +got):\n%s", diff)
+	}
+
 	numFields := reflect.TypeOf(*client).NumField()
-	if diff := cmp.Diff(3, numFields); diff != "" {
+	if diff := cmp.Diff(5, numFields); diff != "" {
 		t.Errorf("NewClient() struct field count mismatch (-want +got):\n%s", diff)
 	}
 }
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+Alpha Vantage reports most problems with a 200 status and a small JSON body instead of a
+proper HTTP error, e.g.
+
+	{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute..."}
+	{"Information": "... our standard API rate limit is 25 requests per day..."}
+	{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint..."}
+	{"Error Message": "Invalid API call. Please retry or visit the documentation..."}
+
+These get classified here into typed errors so callers can tell a transient throttle worth
+retrying apart from a call that will never succeed.
+*/
+
+var (
+	// Short term throttling, e.g. too many calls per minute. Safe to retry after a wait.
+	ErrRateLimited = errors.New("alpha vantage rate limit reached")
+	// The API rejected the call itself, most often because the symbol doesn't exist.
+	ErrInvalidCall = errors.New("alpha vantage rejected the API call")
+	// The endpoint needs a paid key.
+	ErrPremiumEndpoint = errors.New("alpha vantage premium endpoint")
+)
+
+// A throttle or error message returned in place of data. Kind is one of the sentinel errors
+// above, or ErrQuotaExhausted when the API says the daily limit has been hit.
+type APIMessageError struct {
+	Kind    error
+	Message string
+}
+
+func (e *APIMessageError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Kind, e.Message)
+}
+
+func (e *APIMessageError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// A non-200 HTTP response.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned non-200 status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Too many requests and server side failures tend to clear up on their own, other 4xx don't.
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+type apiMessage struct {
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+	ErrorMessage string `json:"Error Message"`
+}
+
+// Returns an APIMessageError if body is one of Alpha Vantage's throttle or error payloads, nil
+// for anything else including non JSON bodies.
+func classifyBody(body []byte) error {
+	// Only top level keys count, successful daily price responses have a nested "1. Information".
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return nil
+	}
+	var message apiMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil
+	}
+
+	if message.ErrorMessage != "" {
+		return &APIMessageError{Kind: ErrInvalidCall, Message: message.ErrorMessage}
+	}
+
+	text := message.Information
+	if text == "" {
+		text = message.Note
+	}
+	if text == "" {
+		return nil
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "premium"):
+		return &APIMessageError{Kind: ErrPremiumEndpoint, Message: text}
+	// The older per minute note also mentions the daily limit, so only a bare daily message counts.
+	case strings.Contains(lower, "per day") && !strings.Contains(lower, "per minute"):
+		return &APIMessageError{Kind: ErrQuotaExhausted, Message: text}
+	default:
+		return &APIMessageError{Kind: ErrRateLimited, Message: text}
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Given the different payloads Alpha Vantage sends in place of data, verify each is classified
+// into the right error kind and real data is left alone.
+func TestClassifyBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected error
+	}{
+		{"per minute note", perMinuteNote, ErrRateLimited},
+		{"burst information", `{"Information": "Please consider spreading out your free API requests more sparingly (1 request per second)."}`, ErrRateLimited},
+		{"daily information", `{"Information": "We have detected your API key as X and our standard API rate limit is 25 requests per day."}`, ErrQuotaExhausted},
+		{"premium", `{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint."}`, ErrPremiumEndpoint},
+		{"error message", `{"Error Message": "Invalid API call. Please retry or visit the documentation."}`, ErrInvalidCall},
+		{"daily prices", `{"Meta Data": {"1. Information": "Daily Prices"}, "Time Series (Daily)": {}}`, nil},
+		{"csv", "symbol,name,reportDate\nIBM,IBM,2025-10-20", nil},
+		{"invalid json", `{"Note": `, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classifyBody([]byte(tc.body))
+			if tc.expected == nil {
+				if err != nil {
+					t.Errorf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected error '%v', but got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// Given successive retries, verify the jittered delay grows but never passes MaxDelay.
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	bounds := []struct{ low, high time.Duration }{
+		{50 * time.Millisecond, 100 * time.Millisecond},
+		{100 * time.Millisecond, 200 * time.Millisecond},
+		{150 * time.Millisecond, 300 * time.Millisecond},
+		{150 * time.Millisecond, 300 * time.Millisecond},
+	}
+	for i, bound := range bounds {
+		delay := policy.delay(i + 1)
+		if delay < bound.low || delay > bound.high {
+			t.Errorf("Retry %d: expected delay between %v and %v, got %v", i+1, bound.low, bound.high, delay)
+		}
+	}
+}
+
+// Given a throttle, verify the retry waits out the per minute window rather than backing off for
+// seconds, and given no RateLimitDelay, verify it backs off like any other failure.
+func TestRetryPolicy_DelayAfterRateLimit(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, RateLimitDelay: time.Minute}
+
+	if delay := policy.delayAfter(1, ErrRateLimited); delay != time.Minute {
+		t.Errorf("Expected a throttled retry to wait a minute, got %v", delay)
+	}
+	if delay := policy.delayAfter(1, errSimulatedNetwork); delay > 100*time.Millisecond {
+		t.Errorf("Expected a network failure to back off exponentially, got %v", delay)
+	}
+	policy.RateLimitDelay = 0
+	if delay := policy.delayAfter(1, ErrRateLimited); delay > 100*time.Millisecond {
+		t.Errorf("Expected a throttled retry without RateLimitDelay to back off exponentially, got %v", delay)
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+/*
+Client side budget for Alpha Vantage calls. The free key allows a handful of calls per minute
+and 25 per day, and going over doesn't fail loudly, it just returns a 200 with an "Information"
+message instead of data. So rather than burn calls finding that out, calls are counted here first.
+
+Per minute limits are handled by waiting for a free slot. Per day limits can't reasonably be
+waited out, so they fail fast with a QuotaExhaustedError. The daily count is persisted to a small
+JSON file so restarting the app (or running it from cron) doesn't reset the budget.
+
+Alpha Vantage doesn't document exactly when the daily count resets, midnight UTC is used as a
+close enough approximation.
+*/
+
+const (
+	DefaultCallsPerMinute = 5
+	DefaultCallsPerDay    = 25
+)
+
+var ErrQuotaExhausted = errors.New("alpha vantage daily quota exhausted")
+
+type QuotaExhaustedError struct {
+	Limit    int
+	ResetsAt time.Time
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("alpha vantage daily quota of %d calls exhausted, resets at %s",
+		e.Limit, e.ResetsAt.Local().Format(time.RFC1123))
+}
+
+func (e *QuotaExhaustedError) Is(target error) bool {
+	return target == ErrQuotaExhausted
+}
+
+type quotaState struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type RateLimiter struct {
+	perMinute int
+	perDay    int
+	statePath string
+	window    time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	recent []time.Time
+	state  quotaState
+}
+
+// Creates a limiter allowing perMinute and perDay calls. A limit of zero or less disables that
+// limit. statePath is where the daily count is persisted, empty keeps it in memory only.
+func NewRateLimiter(perMinute int, perDay int, statePath string) (*RateLimiter, error) {
+	limiter := &RateLimiter{
+		perMinute: perMinute,
+		perDay:    perDay,
+		statePath: statePath,
+		window:    time.Minute,
+		now:       time.Now,
+	}
+
+	if statePath == "" {
+		return limiter, nil
+	}
+
+	stateJson, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return limiter, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota state file '%s': %w", statePath, err)
+	}
+	if err := json.Unmarshal(stateJson, &limiter.state); err != nil {
+		return nil, fmt.Errorf("failed to decode quota state file '%s': %w", statePath, err)
+	}
+
+	return limiter, nil
+}
+
+// Blocks until a call is allowed under the per minute limit, then records it against the daily
+// quota. Returns a QuotaExhaustedError without waiting if the daily budget is already spent.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.rollDay(now)
+
+		if l.perDay > 0 && l.state.Count >= l.perDay {
+			l.mu.Unlock()
+			return &QuotaExhaustedError{Limit: l.perDay, ResetsAt: nextUTCMidnight(now)}
+		}
+
+		l.dropExpired(now)
+		if l.perMinute <= 0 || len(l.recent) < l.perMinute {
+			l.recent = append(l.recent, now)
+			l.state.Count++
+			err := l.persist()
+			l.mu.Unlock()
+			return err
+		}
+
+		waitFor := l.recent[0].Add(l.window).Sub(now)
+		l.mu.Unlock()
+
+		timer := time.NewTimer(waitFor)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Gives back the daily quota taken by the last Wait, for a call the API throttled. It still counts
+// against the per minute limit, since the throttle means that window is full.
+func (l *RateLimiter) Refund() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollDay(l.now())
+	if l.state.Count > 0 {
+		l.state.Count--
+		// Best effort, the count is only ever one call too high if this fails.
+		_ = l.persist()
+	}
+}
+
+// Number of calls left today and when the daily count resets.
+func (l *RateLimiter) Remaining() (int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.rollDay(now)
+	if l.perDay <= 0 {
+		return -1, nextUTCMidnight(now)
+	}
+	return max(l.perDay-l.state.Count, 0), nextUTCMidnight(now)
+}
+
+func (l *RateLimiter) rollDay(now time.Time) {
+	today := now.UTC().Format("2006-01-02")
+	if l.state.Date != today {
+		l.state = quotaState{Date: today}
+	}
+}
+
+func (l *RateLimiter) dropExpired(now time.Time) {
+	keepFrom := 0
+	for keepFrom < len(l.recent) && !l.recent[keepFrom].Add(l.window).After(now) {
+		keepFrom++
+	}
+	l.recent = l.recent[keepFrom:]
+}
+
+// Writes via a temp file and rename so a crash mid write can't leave a corrupt state file.
+func (l *RateLimiter) persist() error {
+	if l.statePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.statePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create quota state directory: %w", err)
+	}
+	stateJson, err := json.Marshal(l.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota state: %w", err)
+	}
+	tempPath := l.statePath + ".tmp"
+	if err := os.WriteFile(tempPath, stateJson, 0o644); err != nil {
+		return fmt.Errorf("failed to write quota state file: %w", err)
+	}
+	if err := os.Rename(tempPath, l.statePath); err != nil {
+		return fmt.Errorf("failed to replace quota state file: %w", err)
+	}
+	return nil
+}
+
+func nextUTCMidnight(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Given a limiter with a daily budget, verify calls are allowed until the budget is spent and then
+// a quota error naming the reset time is returned.
+func TestRateLimiter_DailyQuotaExhausted(t *testing.T) {
+	limiter, err := NewRateLimiter(0, 2, "")
+	if err != nil {
+		t.Fatalf("NewRateLimiter() returned an unexpected error: %v", err)
+	}
+	fixedNow := time.Date(2025, 8, 22, 15, 30, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return fixedNow }
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() call %d returned an unexpected error: %v", i+1, err)
+		}
+	}
+	err = limiter.Wait(context.Background())
+
+	var quotaErr *QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Expected a *QuotaExhaustedError, but got: %v", err)
+	}
+	if !errors.Is(err, ErrQuotaExhausted) {
+		t.Error("Expected the quota error to match ErrQuotaExhausted")
+	}
+	expectedReset := time.Date(2025, 8, 23, 0, 0, 0, 0, time.UTC)
+	if diff := cmp.Diff(expectedReset, quotaErr.ResetsAt); diff != "" {
+		t.Errorf("ResetsAt mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a spent daily budget, verify that the count resets once the UTC day rolls over.
+func TestRateLimiter_DailyQuotaResetsNextDay(t *testing.T) {
+	limiter, _ := NewRateLimiter(0, 1, "")
+	now := time.Date(2025, 8, 22, 23, 59, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned an unexpected error: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Expected the quota to reset on a new day, but got: %v", err)
+	}
+}
+
+// Given a call that was refunded, verify it no longer counts against the daily budget.
+func TestRateLimiter_Refund(t *testing.T) {
+	limiter, _ := NewRateLimiter(0, 1, "")
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned an unexpected error: %v", err)
+	}
+	limiter.Refund()
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Expected the refunded call to be available again, but got: %v", err)
+	}
+}
+
+// Given a state file, verify that the daily count survives creating a new limiter, like a
+// process restart would.
+func TestRateLimiter_PersistsDailyCount(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "nested", "quota.json")
+
+	first, err := NewRateLimiter(0, 2, statePath)
+	if err != nil {
+		t.Fatalf("NewRateLimiter() returned an unexpected error: %v", err)
+	}
+	if err := first.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned an unexpected error: %v", err)
+	}
+
+	second, err := NewRateLimiter(0, 2, statePath)
+	if err != nil {
+		t.Fatalf("NewRateLimiter() returned an unexpected error on reload: %v", err)
+	}
+	remaining, _ := second.Remaining()
+	if remaining != 1 {
+		t.Errorf("Expected 1 call remaining after reload, got %d", remaining)
+	}
+}
+
+// Given a corrupt state file, verify that an error is returned instead of silently resetting the budget.
+func TestRateLimiter_CorruptStateFile(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "quota.json")
+	if err := os.WriteFile(statePath, []byte("not json"), 0o644); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, err := NewRateLimiter(5, 25, statePath)
+	if err == nil {
+		t.Fatal("Expected an error for a corrupt state file, but got nil")
+	}
+}
+
+// Given a per minute limit that is already used up, verify that the next call waits for a free
+// slot instead of failing.
+func TestRateLimiter_PerMinuteWaits(t *testing.T) {
+	limiter, _ := NewRateLimiter(2, 0, "")
+	limiter.window = 50 * time.Millisecond
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() call %d returned an unexpected error: %v", i+1, err)
+		}
+	}
+
+	if elapsed := time.Since(started); elapsed < limiter.window {
+		t.Errorf("Expected the third call to wait for the window (%v), only took %v", limiter.window, elapsed)
+	}
+}
+
+// Given a per minute limit that is used up and a context that is cancelled while waiting,
+// verify that the wait gives up with the context error.
+func TestRateLimiter_PerMinuteWaitCancelled(t *testing.T) {
+	limiter, _ := NewRateLimiter(1, 0, "")
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned an unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := limiter.Wait(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a context deadline error, but got: %v", err)
+	}
+}
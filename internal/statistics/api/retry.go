@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// How failed calls are retried. Delays double after every attempt up to MaxDelay, with jitter so
+// batch workers that failed together don't all retry at the same moment. A per minute throttle
+// won't clear in seconds, so those wait RateLimitDelay instead.
+type RetryPolicy struct {
+	// Total number of tries including the first. Zero or one disables retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Wait before retrying a throttled call. Zero backs off like any other failure.
+	RateLimitDelay time.Duration
+}
+
+// A couple of quick retries for network and server errors, and a full minute for a throttle so
+// its window has passed before the call is tried again.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	BaseDelay:      2 * time.Second,
+	MaxDelay:       30 * time.Second,
+	RateLimitDelay: time.Minute,
+}
+
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// Delay before the given retry, 1 being the first. Picks a random point in the upper half of the
+// exponential delay so retries still back off but never line up exactly.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.BaseDelay << (retry - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// Delay before retrying after err, see RetryPolicy.
+func (p RetryPolicy) delayAfter(retry int, err error) time.Duration {
+	if p.RateLimitDelay > 0 && errors.Is(err, ErrRateLimited) {
+		return p.RateLimitDelay
+	}
+	return p.delay(retry)
+}
+
+// Only transient failures are worth spending another call on. Daily quota, invalid symbols and
+// premium endpoints will fail the same way every time.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryable()
+	}
+	var requestErr *requestError
+	return errors.As(err, &requestErr)
+}
+
+// Network level failure, e.g. a dropped connection or client timeout.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return "failed to make API request: " + e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Where a response came from, recorded per fetch so the TUI and CLI can show how much of a run
+// was served from disk.
+type Source string
+
+const (
+	SourceCache   Source = "cache"
+	SourceNetwork Source = "network"
+)
+
+type FetchEntry struct {
+	Function string
+	Ticker   string
+	Source   Source
+}
+
+// Collects the fetches made while running with a context from WithFetchReport. Safe for
+// concurrent use since batch runs share one report across workers.
+type FetchReport struct {
+	mu      sync.Mutex
+	entries []FetchEntry
+}
+
+type fetchReportKey struct{}
+
+// Returns a context that records every cached client fetch made with it into the returned report.
+func WithFetchReport(ctx context.Context) (context.Context, *FetchReport) {
+	report := &FetchReport{}
+	return context.WithValue(ctx, fetchReportKey{}, report), report
+}
+
+func recordFetch(ctx context.Context, function string, ticker string, source Source) {
+	report, ok := ctx.Value(fetchReportKey{}).(*FetchReport)
+	if !ok {
+		return
+	}
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	report.entries = append(report.entries, FetchEntry{Function: function, Ticker: ticker, Source: source})
+}
+
+func (r *FetchReport) Entries() []FetchEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FetchEntry(nil), r.entries...)
+}
+
+// Number of responses served from disk and from the network.
+func (r *FetchReport) Counts() (hits int, fetches int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.Source == SourceCache {
+			hits++
+		} else {
+			fetches++
+		}
+	}
+	return hits, fetches
+}
+
+// One line summary for logs, e.g. "Cache: 2 hits, 1 network fetch". Empty when nothing went
+// through the cache, e.g. when caching is disabled.
+func (r *FetchReport) Summary() string {
+	hits, fetches := r.Counts()
+	if hits+fetches == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Cache: %d %s, %d network %s", hits, plural(hits, "hit", "hits"), fetches, plural(fetches, "fetch", "fetches"))
+}
+
+func plural(count int, singular string, many string) string {
+	if count == 1 {
+		return singular
+	}
+	return many
+}
@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"cibo/internal/pipelines"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+On disk cache of raw Alpha Vantage responses, per (function, symbol). It wraps any
+pipelines.APIClient and implements the same interface, so pipelines don't know or care whether
+their data came from the network or from disk.
+
+Responses are stored untouched, as the same raw bytes the API returned, so parsing logic stays
+in one place. Each endpoint gets its own time to live since the data moves at very different
+speeds, e.g. splits change a few times a decade while daily prices change every day.
+
+	<dir>/TIME_SERIES_DAILY/AAPL.json
+	<dir>/EARNINGS/AAPL.json
+	<dir>/SPLITS/AAPL.json
+*/
+
+// Alpha Vantage function names, used as the cache key namespace and for TTL overrides.
+const (
+	FunctionDailyPrices = "TIME_SERIES_DAILY"
+	FunctionEarnings    = "EARNINGS"
+	FunctionSplits      = "SPLITS"
+	FunctionIncome      = "INCOME_STATEMENT"
+	FunctionBalance     = "BALANCE_SHEET"
+	FunctionCashFlow    = "CASH_FLOW"
+	FunctionDividends   = "DIVIDENDS"
+	FunctionCalendar    = "EARNINGS_CALENDAR"
+	FunctionEstimates   = "EARNINGS_ESTIMATES"
+)
+
+// Default time to live per endpoint. A TTL of zero or less disables caching for that endpoint.
+func DefaultTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		FunctionDailyPrices: 24 * time.Hour,
+		FunctionEarnings:    24 * time.Hour,
+		FunctionSplits:      7 * 24 * time.Hour,
+		// Statements only change when a new quarter is reported.
+		FunctionIncome:   7 * 24 * time.Hour,
+		FunctionBalance:  7 * 24 * time.Hour,
+		FunctionCashFlow: 7 * 24 * time.Hour,
+		// New dividends are declared weeks ahead, a day old copy never misses one.
+		FunctionDividends: 24 * time.Hour,
+		FunctionCalendar:  24 * time.Hour,
+		FunctionEstimates: 24 * time.Hour,
+	}
+}
+
+type CachingClient struct {
+	upstream     pipelines.APIClient
+	dir          string
+	ttls         map[string]time.Duration
+	forceRefresh bool
+	now          func() time.Time
+}
+
+var _ pipelines.APIClient = (*CachingClient)(nil)
+
+// Creates a cache in dir in front of upstream. forceRefresh skips reading the cache but still
+// writes fresh responses to it, so the next normal run picks them up.
+func NewCachingClient(upstream pipelines.APIClient, dir string, ttls map[string]time.Duration, forceRefresh bool) *CachingClient {
+	return &CachingClient{
+		upstream:     upstream,
+		dir:          dir,
+		ttls:         ttls,
+		forceRefresh: forceRefresh,
+		now:          time.Now,
+	}
+}
+
+func (c *CachingClient) FetchDailyPrice(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionDailyPrices, ticker, c.upstream.FetchDailyPrice)
+}
+
+func (c *CachingClient) FetchEarnings(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionEarnings, ticker, c.upstream.FetchEarnings)
+}
+
+func (c *CachingClient) FetchStockSplits(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionSplits, ticker, c.upstream.FetchStockSplits)
+}
+
+func (c *CachingClient) FetchIncomeStatement(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionIncome, ticker, c.upstream.FetchIncomeStatement)
+}
+
+func (c *CachingClient) FetchBalanceSheet(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionBalance, ticker, c.upstream.FetchBalanceSheet)
+}
+
+func (c *CachingClient) FetchCashFlow(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionCashFlow, ticker, c.upstream.FetchCashFlow)
+}
+
+func (c *CachingClient) FetchDividends(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionDividends, ticker, c.upstream.FetchDividends)
+}
+
+func (c *CachingClient) FetchEarningsCalendar(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionCalendar, ticker, c.upstream.FetchEarningsCalendar)
+}
+
+func (c *CachingClient) FetchEarningsEstimates(ctx context.Context, ticker string) ([]byte, error) {
+	return c.fetch(ctx, FunctionEstimates, ticker, c.upstream.FetchEarningsEstimates)
+}
+
+func (c *CachingClient) fetch(
+	ctx context.Context,
+	function string,
+	ticker string,
+	fetchUpstream func(context.Context, string) ([]byte, error),
+) ([]byte, error) {
+	ttl := c.ttls[function]
+	path := c.entryPath(function, ticker)
+
+	if ttl > 0 && !c.forceRefresh {
+		if body, ok := c.readFresh(path, ttl); ok {
+			recordFetch(ctx, function, ticker, SourceCache)
+			return body, nil
+		}
+	}
+
+	body, err := fetchUpstream(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+	recordFetch(ctx, function, ticker, SourceNetwork)
+
+	if ttl > 0 {
+		// A cache that can't be written to shouldn't fail a run that already has its data.
+		if err := writeEntry(path, body); err != nil {
+			log.Printf("Warning: could not write response cache entry %s: %v", path, err)
+		}
+	}
+
+	return body, nil
+}
+
+func (c *CachingClient) entryPath(function string, ticker string) string {
+	// Tickers come from user input, so keep them from escaping the cache directory.
+	safeTicker := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(strings.ToUpper(ticker))
+	return filepath.Join(c.dir, function, safeTicker+".json")
+}
+
+func (c *CachingClient) readFresh(path string, ttl time.Duration) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if c.now().Sub(info.ModTime()) > ttl {
+		return nil, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// Writes via a temp file and rename so concurrent batch runs never read a half written entry.
+func writeEntry(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tempFile.Write(body); err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+		return err
+	}
+	return os.Rename(tempFile.Name(), path)
+}
+
+// Parses TTL overrides in the form "SPLITS=168h,TIME_SERIES_DAILY=12h" on top of the defaults.
+func ParseTTLOverrides(overrides string) (map[string]time.Duration, error) {
+	ttls := DefaultTTLs()
+	if strings.TrimSpace(overrides) == "" {
+		return ttls, nil
+	}
+
+	for _, pair := range strings.Split(overrides, ",") {
+		function, rawTTL, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid cache TTL '%s', expected FUNCTION=duration", pair)
+		}
+		ttl, err := time.ParseDuration(rawTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL duration for %s: %w", function, err)
+		}
+		function = strings.ToUpper(strings.TrimSpace(function))
+		if _, known := ttls[function]; !known {
+			return nil, errors.New("unknown cache function '" + function + "'")
+		}
+		ttls[function] = ttl
+	}
+
+	return ttls, nil
+}
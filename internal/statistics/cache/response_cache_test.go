@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// --- Mock Implementations ---
+type mockAPIClient struct {
+	calls      map[string]int
+	failWith   error
+	dailyPrice []byte
+}
+
+func newMockAPIClient() *mockAPIClient {
+	return &mockAPIClient{calls: map[string]int{}, dailyPrice: []byte(`{"prices": 1}`)}
+}
+
+func (m *mockAPIClient) FetchDailyPrice(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionDailyPrices]++
+	if m.failWith != nil {
+		return nil, m.failWith
+	}
+	return m.dailyPrice, nil
+}
+
+func (m *mockAPIClient) FetchEarnings(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionEarnings]++
+	return []byte(`{"earnings": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchStockSplits(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionSplits]++
+	return []byte(`{"splits": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchIncomeStatement(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionIncome]++
+	return []byte(`{"income": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchBalanceSheet(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionBalance]++
+	return []byte(`{"balance": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchCashFlow(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionCashFlow]++
+	return []byte(`{"cash_flow": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchDividends(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionDividends]++
+	return []byte(`{"dividends": 1}`), nil
+}
+
+func (m *mockAPIClient) FetchEarningsCalendar(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionCalendar]++
+	return []byte("symbol,reportDate\n"), nil
+}
+
+func (m *mockAPIClient) FetchEarningsEstimates(ctx context.Context, ticker string) ([]byte, error) {
+	m.calls[FunctionEstimates]++
+	return []byte(`{"estimates": 1}`), nil
+}
+
+// Given two fetches of the same ticker, verify the second is served from disk with the same bytes
+// and both are recorded in the fetch report.
+func TestCachingClient_HitAfterMiss(t *testing.T) {
+	upstream := newMockAPIClient()
+	client := NewCachingClient(upstream, t.TempDir(), DefaultTTLs(), false)
+	ctx, report := WithFetchReport(context.Background())
+
+	first, err := client.FetchDailyPrice(ctx, "aapl")
+	if err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+	second, err := client.FetchDailyPrice(ctx, "AAPL")
+	if err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("Expected cached body '%s', got '%s'", first, second)
+	}
+	if upstream.calls[FunctionDailyPrices] != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", upstream.calls[FunctionDailyPrices])
+	}
+	expectedEntries := []FetchEntry{
+		{Function: FunctionDailyPrices, Ticker: "aapl", Source: SourceNetwork},
+		{Function: FunctionDailyPrices, Ticker: "AAPL", Source: SourceCache},
+	}
+	if diff := cmp.Diff(expectedEntries, report.Entries()); diff != "" {
+		t.Errorf("Fetch report mismatch (-want +got):\n%s", diff)
+	}
+	if report.Summary() != "Cache: 1 hit, 1 network fetch" {
+		t.Errorf("Unexpected summary: %s", report.Summary())
+	}
+}
+
+// Given a cache entry older than its endpoint's TTL, verify it is fetched again.
+func TestCachingClient_ExpiredEntry(t *testing.T) {
+	upstream := newMockAPIClient()
+	client := NewCachingClient(upstream, t.TempDir(), DefaultTTLs(), false)
+	ctx := context.Background()
+
+	if _, err := client.FetchDailyPrice(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+	client.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	if _, err := client.FetchDailyPrice(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+	// Splits have a longer TTL so the same age should still be a hit.
+	if _, err := client.FetchStockSplits(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchStockSplits() returned an unexpected error: %v", err)
+	}
+	if _, err := client.FetchStockSplits(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchStockSplits() returned an unexpected error: %v", err)
+	}
+
+	if upstream.calls[FunctionDailyPrices] != 2 {
+		t.Errorf("Expected expired daily prices to be fetched again, got %d upstream calls", upstream.calls[FunctionDailyPrices])
+	}
+	if upstream.calls[FunctionSplits] != 1 {
+		t.Errorf("Expected splits to still be cached, got %d upstream calls", upstream.calls[FunctionSplits])
+	}
+}
+
+// Given a forced refresh, verify the cache is bypassed on read but updated with the new response.
+func TestCachingClient_ForceRefresh(t *testing.T) {
+	upstream := newMockAPIClient()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if _, err := NewCachingClient(upstream, dir, DefaultTTLs(), false).FetchDailyPrice(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+
+	upstream.dailyPrice = []byte(`{"prices": 2}`)
+	if _, err := NewCachingClient(upstream, dir, DefaultTTLs(), true).FetchDailyPrice(ctx, "AAPL"); err != nil {
+		t.Fatalf("FetchDailyPrice() returned an unexpected error: %v", err)
+	}
+	if upstream.calls[FunctionDailyPrices] != 2 {
+		t.Errorf("Expected the refresh to hit upstream, got %d upstream calls", upstream.calls[FunctionDailyPrices])
+	}
+
+	cached, err := os.ReadFile(filepath.Join(dir, FunctionDailyPrices, "AAPL.json"))
+	if err != nil {
+		t.Fatalf("Failed to read cache entry: %v", err)
+	}
+	if string(cached) != `{"prices": 2}` {
+		t.Errorf("Expected the cache to hold the refreshed response, got '%s'", cached)
+	}
+}
+
+// Given a failing upstream, verify the error is returned and nothing is written to the cache.
+func TestCachingClient_ErrorsAreNotCached(t *testing.T) {
+	upstream := newMockAPIClient()
+	upstream.failWith = errors.New("network down")
+	dir := t.TempDir()
+	client := NewCachingClient(upstream, dir, DefaultTTLs(), false)
+
+	_, err := client.FetchDailyPrice(context.Background(), "AAPL")
+	if !errors.Is(err, upstream.failWith) {
+		t.Fatalf("Expected the upstream error, but got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FunctionDailyPrices, "AAPL.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no cache entry after a failed fetch, stat returned: %v", err)
+	}
+}
+
+// Given an endpoint with a zero TTL, verify it is never cached.
+func TestCachingClient_ZeroTTLDisablesEndpoint(t *testing.T) {
+	upstream := newMockAPIClient()
+	ttls := DefaultTTLs()
+	ttls[FunctionEarnings] = 0
+	client := NewCachingClient(upstream, t.TempDir(), ttls, false)
+
+	for range 2 {
+		if _, err := client.FetchEarnings(context.Background(), "AAPL"); err != nil {
+			t.Fatalf("FetchEarnings() returned an unexpected error: %v", err)
+		}
+	}
+	if upstream.calls[FunctionEarnings] != 2 {
+		t.Errorf("Expected every earnings fetch to hit upstream, got %d calls", upstream.calls[FunctionEarnings])
+	}
+}
+
+// Given a ticker containing path separators, verify the entry stays inside the cache directory.
+func TestCachingClient_EntryPathStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	client := NewCachingClient(newMockAPIClient(), dir, DefaultTTLs(), false)
+
+	path := client.entryPath(FunctionSplits, "../../etc/passwd")
+	rel, err := filepath.Rel(filepath.Join(dir, FunctionSplits), path)
+	if err != nil || filepath.Dir(rel) != "." {
+		t.Errorf("Expected entry path inside the cache directory, got '%s'", path)
+	}
+}
+
+func TestParseTTLOverrides(t *testing.T) {
+	ttls, err := ParseTTLOverrides("splits=720h, TIME_SERIES_DAILY=0")
+	if err != nil {
+		t.Fatalf("ParseTTLOverrides() returned an unexpected error: %v", err)
+	}
+	expected := DefaultTTLs()
+	expected[FunctionSplits] = 720 * time.Hour
+	expected[FunctionDailyPrices] = 0
+	if diff := cmp.Diff(expected, ttls); diff != "" {
+		t.Errorf("ParseTTLOverrides() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, bad := range []string{"SPLITS", "SPLITS=soon", "OVERVIEW=1h"} {
+		if _, err := ParseTTLOverrides(bad); err == nil {
+			t.Errorf("ParseTTLOverrides(%q) expected an error, but got none", bad)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package io
+
+import (
+	"bufio"
+	"cibo/internal/types"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+/*
+Writes events as an iCalendar (RFC 5545) file that Google Calendar, Outlook and Apple Calendar
+can import. Earnings events are all day events since Alpha Vantage only gives a date and a rough
+time of day.
+
+Each event's UID is built from the ticker and fiscal quarter, so re-importing a newer export
+updates an estimated date in place once the real one is announced, instead of adding a duplicate.
+*/
+
+// RFC 5545 wants lines no longer than 75 octets, longer ones are folded onto continuation lines.
+const icsMaxLineLength = 75
+
+type ICalendarClient struct {
+	now func() time.Time
+}
+
+func NewICalendarClient() *ICalendarClient {
+	return &ICalendarClient{now: time.Now}
+}
+
+// Write earnings events to an iCalendar file
+func (c *ICalendarClient) WriteEarningsEventsToICS(
+	events []types.EarningsEventRecord,
+	w io.Writer,
+) (string, error) {
+	stamp := c.now().UTC().Format("20060102T150405Z")
+
+	bw := bufio.NewWriter(w)
+	writeLine := func(line string) {
+		// Errors stick to the bufio.Writer and come back from Flush.
+		bw.WriteString(foldICSLine(line))
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//cibo//Earnings Calendar//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:Earnings Calendar")
+
+	written := 0
+	for _, event := range events {
+		start, err := time.Parse("2006-01-02", event.ReportDate)
+		if err != nil {
+			return "", fmt.Errorf("invalid report date '%s' for %s: %w", event.ReportDate, event.Ticker, err)
+		}
+
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + earningsEventUID(event))
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format("20060102"))
+		writeLine("SUMMARY:" + escapeICSText(earningsEventSummary(event)))
+		writeLine("DESCRIPTION:" + escapeICSText(earningsEventDescription(event)))
+		// Shows as free so a full watchlist doesn't block out everyone's day.
+		writeLine("TRANSP:TRANSPARENT")
+		writeLine("END:VEVENT")
+		written++
+	}
+
+	writeLine("END:VCALENDAR")
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write iCalendar file: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d earnings events to iCalendar file", written)
+	return successMessage, nil
+}
+
+func earningsEventUID(event types.EarningsEventRecord) string {
+	quarter := event.FiscalDateEnding
+	if quarter == "" {
+		quarter = event.ReportDate
+	}
+	return fmt.Sprintf("%s-%s-earnings@cibo", event.Ticker, quarter)
+}
+
+// e.g. "AAPL earnings (post-market)" or "AAPL earnings (estimated)"
+func earningsEventSummary(event types.EarningsEventRecord) string {
+	var details []string
+	if event.ReportTime != "" {
+		details = append(details, event.ReportTime)
+	}
+	if event.Source == types.EarningsSourceEstimated {
+		details = append(details, "estimated")
+	}
+	if len(details) == 0 {
+		return event.Ticker + " earnings"
+	}
+	return fmt.Sprintf("%s earnings (%s)", event.Ticker, strings.Join(details, ", "))
+}
+
+func earningsEventDescription(event types.EarningsEventRecord) string {
+	var lines []string
+	if event.CompanyName != "" {
+		lines = append(lines, event.CompanyName)
+	}
+	if event.FiscalDateEnding != "" {
+		lines = append(lines, "Fiscal quarter ending "+event.FiscalDateEnding)
+	}
+	if event.EstimatedEPS != 0 {
+		lines = append(lines, fmt.Sprintf("EPS estimate: %.2f", event.EstimatedEPS))
+	}
+	if event.Source == types.EarningsSourceEstimated {
+		lines = append(lines, "Date projected from past report dates, not yet announced")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Escapes the characters RFC 5545 gives meaning to inside TEXT values.
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// Terminates a content line with CRLF, folding it with a leading space on each continuation line
+// when it's too long. Folds only between whole characters so multi byte names survive.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	lineLength := 0
+	for _, r := range line {
+		runeLength := len(string(r))
+		if lineLength+runeLength > icsMaxLineLength {
+			b.WriteString("\r\n ")
+			lineLength = 1
+		}
+		b.WriteRune(r)
+		lineLength += runeLength
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package io
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"cibo/internal/types"
+)
+
+// Given a scheduled and an estimated event, verify the calendar is written as all day events with
+// CRLF line endings, escaped text and UIDs stable across exports.
+func TestWriteEarningsEventsToICSHappyPath(t *testing.T) {
+	events := []types.EarningsEventRecord{
+		{Ticker: "IBM", CompanyName: "International Business Machines, Corp", ReportDate: "2025-10-22",
+			FiscalDateEnding: "2025-09-30", ReportTime: "post-market", EstimatedEPS: 2.45, Source: types.EarningsSourceCalendar},
+		{Ticker: "AAPL", ReportDate: "2025-10-30", FiscalDateEnding: "2025-09-30", Source: types.EarningsSourceEstimated},
+	}
+
+	client := &ICalendarClient{now: func() time.Time { return time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC) }}
+	var buf bytes.Buffer
+	if _, err := client.WriteEarningsEventsToICS(events, &buf); err != nil {
+		t.Fatalf("WriteEarningsEventsToICS returned an unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//cibo//Earnings Calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Earnings Calendar",
+		"BEGIN:VEVENT",
+		"UID:IBM-2025-09-30-earnings@cibo",
+		"DTSTAMP:20251001T120000Z",
+		"DTSTART;VALUE=DATE:20251022",
+		"DTEND;VALUE=DATE:20251023",
+		"SUMMARY:IBM earnings (post-market)",
+		`DESCRIPTION:International Business Machines\, Corp\nFiscal quarter ending 2`,
+		` 025-09-30\nEPS estimate: 2.45`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:AAPL-2025-09-30-earnings@cibo",
+		"DTSTAMP:20251001T120000Z",
+		"DTSTART;VALUE=DATE:20251030",
+		"DTEND;VALUE=DATE:20251031",
+		"SUMMARY:AAPL earnings (estimated)",
+		`DESCRIPTION:Fiscal quarter ending 2025-09-30\nDate projected from past repo`,
+		` rt dates\, not yet announced`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("iCalendar output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given an event with a malformed report date, verify an error is returned.
+func TestWriteEarningsEventsToICSBadDate(t *testing.T) {
+	events := []types.EarningsEventRecord{{Ticker: "IBM", ReportDate: "10/22/2025"}}
+
+	var buf bytes.Buffer
+	if _, err := NewICalendarClient().WriteEarningsEventsToICS(events, &buf); err == nil {
+		t.Error("Expected an error for a malformed report date")
+	}
+}
@@ -47,6 +47,186 @@ func (p *ParquetClient) WriteCombinedPriceDataToParquet(
 	return successMessage, nil
 }
 
+// Write full OHLCV daily price data to a parquet file
+func (p *ParquetClient) WriteDailyStockDataToParquet(
+	dailyData []types.DailyStockRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	dailyDataParquet := types.DailyStocksToParquet(dailyData)
+	pw, err := writer.NewParquetWriter(fw, new(types.DailyStockRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range dailyDataParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d OHLCV records to Parquet file", len(dailyDataParquet))
+	return successMessage, nil
+}
+
+// Write dividend events to a parquet file
+func (p *ParquetClient) WriteDividendsToParquet(
+	dividends []types.DividendRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	dividendsParquet := types.DividendsToParquet(dividends)
+	pw, err := writer.NewParquetWriter(fw, new(types.DividendRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range dividendsParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d dividend records to Parquet file", len(dividendsParquet))
+	return successMessage, nil
+}
+
+// Write earnings events to a parquet file
+func (p *ParquetClient) WriteEarningsEventsToParquet(
+	events []types.EarningsEventRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	eventsParquet := types.EarningsEventsToParquet(events)
+	pw, err := writer.NewParquetWriter(fw, new(types.EarningsEventRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range eventsParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d earnings events to Parquet file", len(eventsParquet))
+	return successMessage, nil
+}
+
+// Write run metadata key/value pairs to a parquet file
+func (p *ParquetClient) WriteMetadataToParquet(
+	metadata []types.MetadataRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	metadataParquet := types.MetadataToParquet(metadata)
+	pw, err := writer.NewParquetWriter(fw, new(types.MetadataRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range metadataParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d metadata entries to Parquet file", len(metadataParquet))
+	return successMessage, nil
+}
+
+// Write a DCF sensitivity grid to a parquet file
+func (p *ParquetClient) WriteDCFSensitivityToParquet(
+	grid []types.DCFSensitivityRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	gridParquet := types.DCFSensitivityToParquet(grid)
+	pw, err := writer.NewParquetWriter(fw, new(types.DCFSensitivityRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range gridParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d DCF sensitivity cells to Parquet file", len(gridParquet))
+	return successMessage, nil
+}
+
+// Write valuation signal events to a parquet file
+func (p *ParquetClient) WriteValuationSignalsToParquet(
+	signals []types.ValuationSignalRecord,
+	w io.WriteCloser,
+) (string, error) {
+	fw, ok := w.(source.ParquetFile)
+	if !ok {
+		return "", fmt.Errorf("writer is not a valid source.ParquetFile")
+	}
+
+	signalsParquet := types.ValuationSignalsToParquet(signals)
+	pw, err := writer.NewParquetWriter(fw, new(types.ValuationSignalRecordParquet), 4)
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, record := range signalsParquet {
+		if err = pw.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return "", fmt.Errorf("failed to stop parquet writer: %w", err)
+	}
+
+	successMessage := fmt.Sprintf("Successfully wrote %d valuation signals to Parquet file", len(signalsParquet))
+	return successMessage, nil
+}
+
 // Read price data from a parquet file.
 func (p *ParquetClient) ReadCombinedPriceDataFromParquet(filePath string) ([]types.CombinedPriceRecordParquet, error) {
 	fr, err := local.NewLocalFileReader(filePath)
@@ -74,3 +254,143 @@ func (p *ParquetClient) ReadCombinedPriceDataFromParquet(filePath string) ([]typ
 
 	return records, nil
 }
+
+// Read full OHLCV daily price data from a parquet file.
+func (p *ParquetClient) ReadDailyStockDataFromParquet(filePath string) ([]types.DailyStockRecordParquet, error) {
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(types.DailyStockRecordParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRecords := int(pr.GetNumRows())
+	records := make([]types.DailyStockRecordParquet, numRecords)
+
+	if numRecords == 0 {
+		return records, nil
+	}
+
+	if err := pr.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read records from parquet file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Read dividend events from a parquet file.
+func (p *ParquetClient) ReadDividendsFromParquet(filePath string) ([]types.DividendRecordParquet, error) {
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(types.DividendRecordParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRecords := int(pr.GetNumRows())
+	records := make([]types.DividendRecordParquet, numRecords)
+
+	if numRecords == 0 {
+		return records, nil
+	}
+
+	if err := pr.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read records from parquet file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Read run metadata key/value pairs from a parquet file.
+func (p *ParquetClient) ReadMetadataFromParquet(filePath string) ([]types.MetadataRecordParquet, error) {
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(types.MetadataRecordParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRecords := int(pr.GetNumRows())
+	records := make([]types.MetadataRecordParquet, numRecords)
+
+	if numRecords == 0 {
+		return records, nil
+	}
+
+	if err := pr.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read records from parquet file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Read a DCF sensitivity grid from a parquet file.
+func (p *ParquetClient) ReadDCFSensitivityFromParquet(filePath string) ([]types.DCFSensitivityRecordParquet, error) {
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(types.DCFSensitivityRecordParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRecords := int(pr.GetNumRows())
+	records := make([]types.DCFSensitivityRecordParquet, numRecords)
+
+	if numRecords == 0 {
+		return records, nil
+	}
+
+	if err := pr.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read records from parquet file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Read valuation signal events from a parquet file.
+func (p *ParquetClient) ReadValuationSignalsFromParquet(filePath string) ([]types.ValuationSignalRecordParquet, error) {
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(types.ValuationSignalRecordParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRecords := int(pr.GetNumRows())
+	records := make([]types.ValuationSignalRecordParquet, numRecords)
+
+	if numRecords == 0 {
+		return records, nil
+	}
+
+	if err := pr.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read records from parquet file: %w", err)
+	}
+
+	return records, nil
+}
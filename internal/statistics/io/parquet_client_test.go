@@ -201,3 +201,149 @@ func TestReadEmptyFile(t *testing.T) {
 		t.Errorf("Expected an empty slice when reading an empty file, but got %d records", len(records))
 	}
 }
+
+// Given OHLCV records, verify they round trip through a parquet file with every column intact.
+func TestWriteAndReadDailyStockDataHappyPath(t *testing.T) {
+	recordsToWrite := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2025-01-01", OpenPrice: 99.5, HighPrice: 101.0, LowPrice: 98.0, ClosingPrice: 100.0, Volume: 123456},
+		{Ticker: "TEST", Date: "2025-01-02", OpenPrice: 100.0, HighPrice: 103.0, LowPrice: 99.0, ClosingPrice: 102.5, Volume: 7890},
+	}
+	expectedOutput := []types.DailyStockRecordParquet{
+		{Ticker: "TEST", Date: "2025-01-01", OpenPrice: 99.5, HighPrice: 101.0, LowPrice: 98.0, ClosingPrice: 100.0, Volume: 123456},
+		{Ticker: "TEST", Date: "2025-01-02", OpenPrice: 100.0, HighPrice: 103.0, LowPrice: 99.0, ClosingPrice: 102.5, Volume: 7890},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "ohlcv.parquet")
+	fw, _ := local.NewLocalFileWriter(filePath)
+	client := NewParquetClient()
+	if _, err := client.WriteDailyStockDataToParquet(recordsToWrite, fw); err != nil {
+		t.Fatalf("WriteDailyStockDataToParquet returned an unexpected error: %v", err)
+	}
+	fw.Close()
+
+	readRecords, err := client.ReadDailyStockDataFromParquet(filePath)
+	if err != nil {
+		t.Fatalf("ReadDailyStockDataFromParquet returned an unexpected error: %v", err)
+	}
+
+	sorter := cmpopts.SortSlices(func(a, b types.DailyStockRecordParquet) bool { return a.Date < b.Date })
+	if diff := cmp.Diff(expectedOutput, readRecords, sorter); diff != "" {
+		t.Errorf("Record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given dividends with and without declaration dates, verify they round trip through a parquet
+// file with the missing dates left empty.
+func TestWriteAndReadDividendsHappyPath(t *testing.T) {
+	recordsToWrite := []types.DividendRecord{
+		{Ticker: "TEST", ExDividendDate: "2025-05-09", DeclarationDate: "2025-04-29", RecordDate: "2025-05-09", PaymentDate: "2025-06-10", Amount: 1.68},
+		{Ticker: "TEST", ExDividendDate: "1999-05-06", PaymentDate: "1999-06-10", Amount: 0.12},
+	}
+	expectedOutput := []types.DividendRecordParquet{
+		{Ticker: "TEST", ExDividendDate: "2025-05-09", DeclarationDate: "2025-04-29", RecordDate: "2025-05-09", PaymentDate: "2025-06-10", Amount: 1.68},
+		{Ticker: "TEST", ExDividendDate: "1999-05-06", PaymentDate: "1999-06-10", Amount: 0.12},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "dividends.parquet")
+	fw, _ := local.NewLocalFileWriter(filePath)
+	client := NewParquetClient()
+	if _, err := client.WriteDividendsToParquet(recordsToWrite, fw); err != nil {
+		t.Fatalf("WriteDividendsToParquet returned an unexpected error: %v", err)
+	}
+	fw.Close()
+
+	readRecords, err := client.ReadDividendsFromParquet(filePath)
+	if err != nil {
+		t.Fatalf("ReadDividendsFromParquet returned an unexpected error: %v", err)
+	}
+
+	sorter := cmpopts.SortSlices(func(a, b types.DividendRecordParquet) bool { return a.ExDividendDate < b.ExDividendDate })
+	if diff := cmp.Diff(expectedOutput, readRecords, sorter); diff != "" {
+		t.Errorf("Record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given run metadata entries, verify they round trip through a parquet file in order.
+func TestWriteAndReadMetadataHappyPath(t *testing.T) {
+	recordsToWrite := []types.MetadataRecord{
+		{Key: "inputs.ticker", Value: `"TEST"`},
+		{Key: "model.fair_value_pe", Value: "14.87"},
+	}
+	expectedOutput := []types.MetadataRecordParquet{
+		{Key: "inputs.ticker", Value: `"TEST"`},
+		{Key: "model.fair_value_pe", Value: "14.87"},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "metadata.parquet")
+	fw, _ := local.NewLocalFileWriter(filePath)
+	client := NewParquetClient()
+	if _, err := client.WriteMetadataToParquet(recordsToWrite, fw); err != nil {
+		t.Fatalf("WriteMetadataToParquet returned an unexpected error: %v", err)
+	}
+	fw.Close()
+
+	readRecords, err := client.ReadMetadataFromParquet(filePath)
+	if err != nil {
+		t.Fatalf("ReadMetadataFromParquet returned an unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(expectedOutput, readRecords); diff != "" {
+		t.Errorf("Record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteAndReadDCFSensitivityHappyPath(t *testing.T) {
+	recordsToWrite := []types.DCFSensitivityRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", DiscountRate: 9, TerminalGrowth: 2.5, FairValuePrice: 120.5},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", DiscountRate: 10, TerminalGrowth: 2.5, FairValuePrice: 101.25},
+	}
+	expectedOutput := []types.DCFSensitivityRecordParquet{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", DiscountRate: 9, TerminalGrowth: 2.5, FairValuePrice: 120.5},
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", DiscountRate: 10, TerminalGrowth: 2.5, FairValuePrice: 101.25},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "dcf_sensitivity.parquet")
+	fw, _ := local.NewLocalFileWriter(filePath)
+	client := NewParquetClient()
+	if _, err := client.WriteDCFSensitivityToParquet(recordsToWrite, fw); err != nil {
+		t.Fatalf("WriteDCFSensitivityToParquet returned an unexpected error: %v", err)
+	}
+	fw.Close()
+
+	readRecords, err := client.ReadDCFSensitivityFromParquet(filePath)
+	if err != nil {
+		t.Fatalf("ReadDCFSensitivityFromParquet returned an unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(expectedOutput, readRecords); diff != "" {
+		t.Errorf("Record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteAndReadValuationSignalsHappyPath(t *testing.T) {
+	recordsToWrite := []types.ValuationSignalRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 125, FairValuePrice: 100, PremiumPct: 25, Signal: types.SignalOvervalued},
+		{Ticker: "TEST", Date: "2025-03-03", Price: 75, FairValuePrice: 100, PremiumPct: -25, Signal: types.SignalUndervalued},
+	}
+	expectedOutput := []types.ValuationSignalRecordParquet{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 125, FairValuePrice: 100, PremiumPct: 25, Signal: "overvalued"},
+		{Ticker: "TEST", Date: "2025-03-03", Price: 75, FairValuePrice: 100, PremiumPct: -25, Signal: "undervalued"},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "signals.parquet")
+	fw, _ := local.NewLocalFileWriter(filePath)
+	client := NewParquetClient()
+	if _, err := client.WriteValuationSignalsToParquet(recordsToWrite, fw); err != nil {
+		t.Fatalf("WriteValuationSignalsToParquet returned an unexpected error: %v", err)
+	}
+	fw.Close()
+
+	readRecords, err := client.ReadValuationSignalsFromParquet(filePath)
+	if err != nil {
+		t.Fatalf("ReadValuationSignalsFromParquet returned an unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(expectedOutput, readRecords); diff != "" {
+		t.Errorf("Record mismatch (-want +got):\n%s", diff)
+	}
+}
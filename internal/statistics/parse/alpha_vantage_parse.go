@@ -2,6 +2,7 @@ package parse
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
@@ -16,6 +17,10 @@ Hard coded to their data structures, any changes to their API will
 be mapped here.
 */
 
+// Alpha Vantage answers unknown symbols with an empty object rather than an error, so a missing
+// ticker in the response is the signal that the symbol doesn't exist.
+var ErrUnknownTicker = errors.New("unknown ticker")
+
 type DailyPricesResponse struct {
 	MetaData    MetaDataContainer         `json:"Meta Data"`
 	TimeSeries  map[string]DailyDataPoint `json:"Time Series (Daily)"`
@@ -27,7 +32,11 @@ type MetaDataContainer struct {
 }
 
 type DailyDataPoint struct {
-	Close string `json:"4. close"`
+	Open   string `json:"1. open"`
+	High   string `json:"2. high"`
+	Low    string `json:"3. low"`
+	Close  string `json:"4. close"`
+	Volume string `json:"5. volume"`
 }
 
 /*
@@ -35,19 +44,25 @@ Function to take json data of daily prices and parse it into a collection
 of individual stock prices.
 */
 func ParseDailyPricesToFlat(jsonData []byte, skipErrors bool) ([]types.DailyStockRecord, error) {
+	records, _, err := ParseDailyPricesToFlatWithSkips(jsonData, skipErrors)
+	return records, err
+}
+
+// Same as ParseDailyPricesToFlat, also returning how many records were skipped.
+func ParseDailyPricesToFlatWithSkips(jsonData []byte, skipErrors bool) ([]types.DailyStockRecord, int, error) {
 	var response DailyPricesResponse
 
 	if err := json.Unmarshal(jsonData, &response); err != nil {
-		return nil, fmt.Errorf("error unmarshaling json: %w", err)
+		return nil, 0, fmt.Errorf("error unmarshaling json: %w", err)
 	}
 
 	if response.Information != "" {
-		return nil, fmt.Errorf("%s", response.Information)
+		return nil, 0, fmt.Errorf("%s", response.Information)
 	}
 
 	ticker := response.MetaData.Symbol
 	if ticker == "" {
-		return nil, fmt.Errorf("ticker not found in JSON Meta Data when parsing")
+		return nil, 0, fmt.Errorf("%w: ticker not found in JSON Meta Data when parsing", ErrUnknownTicker)
 	}
 
 	records := make([]types.DailyStockRecord, 0, len(response.TimeSeries))
@@ -59,14 +74,22 @@ func ParseDailyPricesToFlat(jsonData []byte, skipErrors bool) ([]types.DailyStoc
 				log.Printf("Warning: could not parse close price for date %s, skipping record. Error: %v", rawDate, err)
 				continue
 			}
-			return nil, fmt.Errorf("could not parse close price '%s' for date %s: %w", rawDataPoint.Close, rawDate, err)
+			return nil, 0, fmt.Errorf("could not parse close price '%s' for date %s: %w", rawDataPoint.Close, rawDate, err)
 		}
 
-		records = append(records, types.DailyStockRecord{
-			Ticker:       ticker,
-			Date:         rawDate,
-			ClosingPrice: closingPrice,
-		})
+		record, err := parseOpenHighLowVolume(rawDataPoint)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse OHLCV data for date %s, skipping record. Error: %v", rawDate, err)
+				continue
+			}
+			return nil, 0, fmt.Errorf("could not parse OHLCV data for date %s: %w", rawDate, err)
+		}
+		record.Ticker = ticker
+		record.Date = rawDate
+		record.ClosingPrice = closingPrice
+
+		records = append(records, record)
 	}
 
 	// Sort the records by date, descending (newest to oldest), to restore the order
@@ -75,7 +98,42 @@ func ParseDailyPricesToFlat(jsonData []byte, skipErrors bool) ([]types.DailyStoc
 		return records[i].Date > records[j].Date
 	})
 
-	return records, nil
+	return records, len(response.TimeSeries) - len(records), nil
+}
+
+/*
+Open, high, low and volume are optional since the close is all the fair value math needs. A missing
+field is left as zero, but a present and malformed one is treated like a bad close price.
+*/
+func parseOpenHighLowVolume(dataPoint DailyDataPoint) (types.DailyStockRecord, error) {
+	var record types.DailyStockRecord
+	var err error
+
+	prices := []struct {
+		name  string
+		raw   string
+		value *float64
+	}{
+		{"open", dataPoint.Open, &record.OpenPrice},
+		{"high", dataPoint.High, &record.HighPrice},
+		{"low", dataPoint.Low, &record.LowPrice},
+	}
+	for _, price := range prices {
+		if price.raw == "" {
+			continue
+		}
+		if *price.value, err = strconv.ParseFloat(price.raw, 64); err != nil {
+			return record, fmt.Errorf("could not parse %s price '%s': %w", price.name, price.raw, err)
+		}
+	}
+
+	if dataPoint.Volume != "" {
+		if record.Volume, err = strconv.ParseInt(dataPoint.Volume, 10, 64); err != nil {
+			return record, fmt.Errorf("could not parse volume '%s': %w", dataPoint.Volume, err)
+		}
+	}
+
+	return record, nil
 }
 
 type AnnualEarningResponse struct {
@@ -95,15 +153,21 @@ Function to take json data of annual earnings and parse it into a collection
 of individual annual earnings data points.
 */
 func ParseAnnualEarningsToFlat(jsonData []byte, skipErrors bool) ([]types.AnnualEarningRecord, error) {
+	records, _, err := ParseAnnualEarningsToFlatWithSkips(jsonData, skipErrors)
+	return records, err
+}
+
+// Same as ParseAnnualEarningsToFlat, also returning how many records were skipped.
+func ParseAnnualEarningsToFlatWithSkips(jsonData []byte, skipErrors bool) ([]types.AnnualEarningRecord, int, error) {
 	var response AnnualEarningResponse
 
 	if err := json.Unmarshal(jsonData, &response); err != nil {
-		return nil, fmt.Errorf("error unmarshaling json: %w", err)
+		return nil, 0, fmt.Errorf("error unmarshaling json: %w", err)
 	}
 
 	ticker := response.Symbol
 	if ticker == "" {
-		return nil, fmt.Errorf("ticker not found in JSON when parsing")
+		return nil, 0, fmt.Errorf("%w: ticker not found in JSON when parsing", ErrUnknownTicker)
 	}
 
 	records := make([]types.AnnualEarningRecord, 0, len(response.AnnualEarnings))
@@ -117,7 +181,7 @@ func ParseAnnualEarningsToFlat(jsonData []byte, skipErrors bool) ([]types.Annual
 					fiscalDateEnding, epsParseError)
 				continue
 			}
-			return nil, fmt.Errorf("could not parse reported EPS for date %s: %w",
+			return nil, 0, fmt.Errorf("could not parse reported EPS for date %s: %w",
 				fiscalDateEnding, epsParseError)
 		}
 
@@ -128,6 +192,153 @@ func ParseAnnualEarningsToFlat(jsonData []byte, skipErrors bool) ([]types.Annual
 		})
 	}
 
+	return records, len(response.AnnualEarnings) - len(records), nil
+}
+
+type QuarterlyEarningResponse struct {
+	Symbol            string             `json:"symbol"`
+	QuarterlyEarnings []QuarterlyEarning `json:"quarterlyEarnings"`
+}
+
+type QuarterlyEarning struct {
+	FiscalDateEnding   string `json:"fiscalDateEnding"`
+	ReportedDate       string `json:"reportedDate"`
+	ReportedEPS        string `json:"reportedEPS"`
+	EstimatedEPS       string `json:"estimatedEPS"`
+	Surprise           string `json:"surprise"`
+	SurprisePercentage string `json:"surprisePercentage"`
+	ReportTime         string `json:"reportTime"`
+}
+
+/*
+Function to take json data of earnings and parse the quarterly reports into a collection of
+individual quarterly data points, newest first as Alpha Vantage returns them. A quarter without a
+reported EPS is an error (or skipped), a missing estimate or surprise is left as zero since most
+older quarters don't have one.
+*/
+func ParseQuarterlyEarningsToFlat(jsonData []byte, skipErrors bool) ([]types.QuarterlyEarningRecord, error) {
+	records, _, err := ParseQuarterlyEarningsToFlatWithSkips(jsonData, skipErrors)
+	return records, err
+}
+
+// Same as ParseQuarterlyEarningsToFlat, also returning how many records were skipped.
+func ParseQuarterlyEarningsToFlatWithSkips(jsonData []byte, skipErrors bool) ([]types.QuarterlyEarningRecord, int, error) {
+	var response QuarterlyEarningResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, 0, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, 0, fmt.Errorf("%w: ticker not found in JSON when parsing quarterly earnings", ErrUnknownTicker)
+	}
+
+	records := make([]types.QuarterlyEarningRecord, 0, len(response.QuarterlyEarnings))
+	for _, earnings := range response.QuarterlyEarnings {
+		fiscalDateEnding := earnings.FiscalDateEnding
+		reportedEPS, err := strconv.ParseFloat(earnings.ReportedEPS, 64)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse quarterly reported EPS for date %s, skipping record. Error: %v",
+					fiscalDateEnding, err)
+				continue
+			}
+			return nil, 0, fmt.Errorf("could not parse quarterly reported EPS for date %s: %w", fiscalDateEnding, err)
+		}
+
+		records = append(records, types.QuarterlyEarningRecord{
+			Ticker:           ticker,
+			FiscalDateEnding: fiscalDateEnding,
+			ReportedDate:     knownDate(earnings.ReportedDate),
+			ReportTime:       earnings.ReportTime,
+			ReportedEPS:      reportedEPS,
+
+			EstimatedEPS:       optionalFloat(earnings.EstimatedEPS, "estimated EPS", fiscalDateEnding),
+			Surprise:           optionalFloat(earnings.Surprise, "surprise", fiscalDateEnding),
+			SurprisePercentage: optionalFloat(earnings.SurprisePercentage, "surprise percentage", fiscalDateEnding),
+		})
+	}
+
+	return records, len(response.QuarterlyEarnings) - len(records), nil
+}
+
+// Parses a value Alpha Vantage may leave out, as "None" or empty. Anything unparseable is
+// treated as missing too, with a warning, since it shouldn't cost the whole record.
+func optionalFloat(raw string, what string, date string) float64 {
+	if raw == "" || raw == "None" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("Warning: could not parse %s for date %s, leaving it empty. Error: %v", what, date, err)
+		return 0
+	}
+	return value
+}
+
+type EarningsEstimatesResponse struct {
+	Symbol    string             `json:"symbol"`
+	Estimates []EarningsEstimate `json:"estimates"`
+}
+
+// Alpha Vantage sends null for some of these, which leaves the string empty.
+type EarningsEstimate struct {
+	Date         string `json:"date"`
+	Horizon      string `json:"horizon"`
+	AverageEPS   string `json:"eps_estimate_average"`
+	HighEPS      string `json:"eps_estimate_high"`
+	LowEPS       string `json:"eps_estimate_low"`
+	AnalystCount string `json:"eps_estimate_analyst_count"`
+}
+
+/*
+Parses analyst EPS estimates, one record per fiscal period. An estimate without an average is an
+error (or skipped), a missing high or low falls back to the average so the estimate still has a
+band, just a zero width one.
+*/
+func ParseEarningsEstimatesToFlat(jsonData []byte, skipErrors bool) ([]types.EarningsEstimateRecord, error) {
+	var response EarningsEstimatesResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling earnings estimates json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing earnings estimates", ErrUnknownTicker)
+	}
+
+	records := make([]types.EarningsEstimateRecord, 0, len(response.Estimates))
+	for _, estimate := range response.Estimates {
+		averageEPS, err := strconv.ParseFloat(estimate.AverageEPS, 64)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse average EPS estimate for date %s, skipping record. Error: %v",
+					estimate.Date, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse average EPS estimate for date %s: %w", estimate.Date, err)
+		}
+
+		highEPS, err := strconv.ParseFloat(estimate.HighEPS, 64)
+		if err != nil {
+			highEPS = averageEPS
+		}
+		lowEPS, err := strconv.ParseFloat(estimate.LowEPS, 64)
+		if err != nil {
+			lowEPS = averageEPS
+		}
+
+		records = append(records, types.EarningsEstimateRecord{
+			Ticker:       ticker,
+			Date:         estimate.Date,
+			Horizon:      estimate.Horizon,
+			AverageEPS:   averageEPS,
+			HighEPS:      highEPS,
+			LowEPS:       lowEPS,
+			AnalystCount: optionalFloat(estimate.AnalystCount, "analyst count", estimate.Date),
+		})
+	}
+
 	return records, nil
 }
 
@@ -151,7 +362,7 @@ func ParseStockSplitsToFlat(jsonData []byte) ([]types.StockSplitRecord, error) {
 
 	ticker := response.Symbol
 	if ticker == "" {
-		return nil, fmt.Errorf("ticker not found in JSON when parsing stock splits")
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing stock splits", ErrUnknownTicker)
 	}
 
 	records := make([]types.StockSplitRecord, 0, len(response.Data))
@@ -172,3 +383,61 @@ func ParseStockSplitsToFlat(jsonData []byte) ([]types.StockSplitRecord, error) {
 
 	return records, nil
 }
+
+type DividendResponse struct {
+	Symbol string          `json:"symbol"`
+	Data   []DividendEvent `json:"data"`
+}
+
+type DividendEvent struct {
+	ExDividendDate  string `json:"ex_dividend_date"`
+	DeclarationDate string `json:"declaration_date"`
+	RecordDate      string `json:"record_date"`
+	PaymentDate     string `json:"payment_date"`
+	Amount          string `json:"amount"`
+}
+
+// Takes json data of dividends and parses it into a collection of individual dividend events.
+// Dates Alpha Vantage doesn't know come back as "None" and are left empty.
+func ParseDividendsToFlat(jsonData []byte, skipErrors bool) ([]types.DividendRecord, error) {
+	var response DividendResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling dividends json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing dividends", ErrUnknownTicker)
+	}
+
+	records := make([]types.DividendRecord, 0, len(response.Data))
+	for _, dividend := range response.Data {
+		amount, err := strconv.ParseFloat(dividend.Amount, 64)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse dividend amount for date %s, skipping record. Error: %v",
+					dividend.ExDividendDate, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse dividend amount for date %s: %w", dividend.ExDividendDate, err)
+		}
+
+		records = append(records, types.DividendRecord{
+			Ticker:          ticker,
+			ExDividendDate:  knownDate(dividend.ExDividendDate),
+			DeclarationDate: knownDate(dividend.DeclarationDate),
+			RecordDate:      knownDate(dividend.RecordDate),
+			PaymentDate:     knownDate(dividend.PaymentDate),
+			Amount:          amount,
+		})
+	}
+
+	return records, nil
+}
+
+func knownDate(date string) string {
+	if date == "None" {
+		return ""
+	}
+	return date
+}
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -52,6 +53,67 @@ func TestParseDailyToFlatHappyPath(t *testing.T) {
 	}
 }
 
+// Given a full OHLCV data point, verify every field is parsed.
+func TestParseDailyToFlatOHLCV(t *testing.T) {
+	jsonData := []byte(`{
+		"Meta Data": { "2. Symbol": "IBM" },
+		"Time Series (Daily)": {
+			"2025-08-22": {
+				"1. open": "240.7400",
+				"2. high": "243.6800",
+				"3. low": "240.2200",
+				"4. close": "242.0900",
+				"5. volume": "3134882"
+			}
+		}
+	}`)
+
+	expected := []types.DailyStockRecord{{
+		Ticker:       "IBM",
+		Date:         "2025-08-22",
+		OpenPrice:    240.74,
+		HighPrice:    243.68,
+		LowPrice:     240.22,
+		ClosingPrice: 242.09,
+		Volume:       3134882,
+	}}
+
+	records, err := ParseDailyPricesToFlat(jsonData, false)
+	if err != nil {
+		t.Fatalf(`ParseDailyPricesToFlat() returned an unexpected error: %v`, err)
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseDailyPricesToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a malformed volume, verify strict mode errors and lenient mode skips just that record.
+func TestParseDailyToFlatBadVolume(t *testing.T) {
+	jsonData := []byte(`{
+		"Meta Data": { "2. Symbol": "IBM" },
+		"Time Series (Daily)": {
+			"2025-08-22": { "4. close": "242.09", "5. volume": "lots" },
+			"2025-08-21": { "4. close": "240.00", "5. volume": "100" }
+		}
+	}`)
+
+	if _, err := ParseDailyPricesToFlat(jsonData, false); err == nil {
+		t.Error("ParseDailyPricesToFlat() expected an error for a malformed volume in strict mode, but got none")
+	}
+
+	records, err := ParseDailyPricesToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf(`ParseDailyPricesToFlat() returned an unexpected error: %v`, err)
+	}
+	if len(records) != 1 || records[0].Date != "2025-08-21" {
+		t.Errorf("Expected only the valid record to be kept, got: %+v", records)
+	}
+
+	if _, skipped, _ := ParseDailyPricesToFlatWithSkips(jsonData, true); skipped != 1 {
+		t.Errorf("Expected 1 skipped record, got %d", skipped)
+	}
+}
+
 // Given malformed json data, verify that the parser returns an error
 func TestMalformedJsonParseDailyToFlat(t *testing.T) {
 	jsonData := []byte(`{ "Meta Data": "invalid }`)
@@ -302,6 +364,10 @@ func TestParseAnnualNonNumericPermissive(t *testing.T) {
 	if diff := cmp.Diff(expected, records); diff != "" {
 		t.Errorf("ParseAnnualEarningsToFlat() mismatch (-want +got):\n%s", diff)
 	}
+
+	if _, skipped, _ := ParseAnnualEarningsToFlatWithSkips(jsonData, true); skipped != 1 {
+		t.Errorf("Expected 1 skipped record, got %d", skipped)
+	}
 }
 
 // Given valid json data, verify that it is parsed into the correct collection of split records.
@@ -441,3 +507,129 @@ func TestParseDailyToFlatInfoMessage(t *testing.T) {
 		t.Errorf("Info message mismatch (-want +got):\n%s", diff)
 	}
 }
+
+// Given dividends where older records have no declaration or record date, verify "None" dates
+// are left empty and amounts are parsed.
+func TestParseDividendsToFlat(t *testing.T) {
+	jsonData := []byte(`{
+		"symbol": "TEST",
+		"data": [
+			{"ex_dividend_date": "2025-08-08", "declaration_date": "2025-07-23", "record_date": "2025-08-08", "payment_date": "2025-09-10", "amount": "1.68"},
+			{"ex_dividend_date": "1999-05-06", "declaration_date": "None", "record_date": "None", "payment_date": "1999-06-10", "amount": "0.12"}
+		]
+	}`)
+
+	expected := []types.DividendRecord{
+		{Ticker: "TEST", ExDividendDate: "2025-08-08", DeclarationDate: "2025-07-23", RecordDate: "2025-08-08", PaymentDate: "2025-09-10", Amount: 1.68},
+		{Ticker: "TEST", ExDividendDate: "1999-05-06", PaymentDate: "1999-06-10", Amount: 0.12},
+	}
+
+	records, err := ParseDividendsToFlat(jsonData, false)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseDividendsToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a record with a non-numeric amount, verify it is skipped when skipping errors and fails
+// the parse otherwise.
+func TestParseDividendsToFlatBadAmount(t *testing.T) {
+	jsonData := []byte(`{
+		"symbol": "TEST",
+		"data": [
+			{"ex_dividend_date": "2025-08-08", "payment_date": "2025-09-10", "amount": "1.68"},
+			{"ex_dividend_date": "2025-05-09", "payment_date": "2025-06-10", "amount": "None"}
+		]
+	}`)
+
+	records, err := ParseDividendsToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("Expected no error when skipping bad records, but got: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("Expected 1 record after skipping the bad amount, but got %d", len(records))
+	}
+
+	if _, err := ParseDividendsToFlat(jsonData, false); err == nil {
+		t.Error("Expected an error for a bad amount when not skipping errors")
+	}
+}
+
+// Given a response without a symbol, verify an unknown ticker error is returned.
+func TestParseDividendsToFlatMissingTicker(t *testing.T) {
+	_, err := ParseDividendsToFlat([]byte(`{"data": []}`), true)
+	if !errors.Is(err, ErrUnknownTicker) {
+		t.Errorf("Expected an unknown ticker error, but got: %v", err)
+	}
+}
+
+// Given quarterly earnings with an unreported quarter, verify report dates, times, EPS and
+// estimates are mapped, missing estimates are left as zero and the quarter without EPS is skipped
+// when skipping errors.
+func TestParseQuarterlyEarningsToFlat(t *testing.T) {
+	jsonData := []byte(`{
+		"symbol": "TEST",
+		"annualEarnings": [{"fiscalDateEnding": "2024-12-31", "reportedEPS": "10.00"}],
+		"quarterlyEarnings": [
+			{"fiscalDateEnding": "2025-06-30", "reportedDate": "2025-07-23", "reportedEPS": "2.8", "estimatedEPS": "2.5",
+				"surprise": "0.3", "surprisePercentage": "12", "reportTime": "post-market"},
+			{"fiscalDateEnding": "2025-03-31", "reportedDate": "None", "reportedEPS": "2.1", "estimatedEPS": "None",
+				"surprise": "None", "surprisePercentage": "None", "reportTime": ""},
+			{"fiscalDateEnding": "1996-03-31", "reportedDate": "None", "reportedEPS": "None", "reportTime": ""}
+		]
+	}`)
+
+	expected := []types.QuarterlyEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2025-06-30", ReportedDate: "2025-07-23", ReportTime: "post-market", ReportedEPS: 2.8,
+			EstimatedEPS: 2.5, Surprise: 0.3, SurprisePercentage: 12},
+		{Ticker: "TEST", FiscalDateEnding: "2025-03-31", ReportedEPS: 2.1},
+	}
+
+	records, err := ParseQuarterlyEarningsToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("Expected no error when skipping bad records, but got: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseQuarterlyEarningsToFlat() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ParseQuarterlyEarningsToFlat(jsonData, false); err == nil {
+		t.Error("Expected an error for a quarter without EPS when not skipping errors")
+	}
+}
+
+// Given estimates with a null low and one without an average, verify the low falls back to the
+// average and the estimate without one is skipped, or fails when not skipping errors.
+func TestParseEarningsEstimatesToFlat(t *testing.T) {
+	jsonData := []byte(`{
+		"symbol": "IBM",
+		"estimates": [
+			{"date": "2026-12-31", "horizon": "next fiscal year", "eps_estimate_average": "11.8687",
+				"eps_estimate_high": "12.7400", "eps_estimate_low": null, "eps_estimate_analyst_count": "21.0000"},
+			{"date": "2025-12-31", "horizon": "current fiscal year", "eps_estimate_average": null,
+				"eps_estimate_high": null, "eps_estimate_low": null, "eps_estimate_analyst_count": null}
+		]
+	}`)
+
+	expected := []types.EarningsEstimateRecord{
+		{Ticker: "IBM", Date: "2026-12-31", Horizon: types.EstimateHorizonNextYear, AverageEPS: 11.8687,
+			HighEPS: 12.74, LowEPS: 11.8687, AnalystCount: 21},
+	}
+
+	records, err := ParseEarningsEstimatesToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("Expected no error when skipping bad records, but got: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseEarningsEstimatesToFlat() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ParseEarningsEstimatesToFlat(jsonData, false); err == nil {
+		t.Error("Expected an error for an estimate without an average when not skipping errors")
+	}
+}
@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cibo/internal/types"
+)
+
+/*
+Parsing of a locally supplied consumer price index series. Alpha Vantage's CPI endpoint is a
+separate call on the daily budget and only monthly, so the series is read from a CSV file instead,
+in the shape FRED exports it (https://fred.stlouisfed.org/series/CPIAUCSL):
+
+	observation_date,CPIAUCSL
+	1947-01-01,21.48
+	1947-02-01,21.62
+
+The first column is the date and the second the index value, whatever the header calls them.
+FRED marks missing observations with ".", those rows are skipped.
+*/
+
+var ErrNoCPIData = errors.New("no CPI observations found")
+
+// Parses a CPI series CSV, returned oldest first.
+func ParseCPISeriesCSV(csvData []byte) ([]types.CPIRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(csvData))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CPI csv: %w", err)
+	}
+
+	records := make([]types.CPIRecord, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("CPI csv line %d has %d columns, expected a date and a value", i+1, len(row))
+		}
+		date := strings.TrimSpace(row[0])
+		rawValue := strings.TrimSpace(row[1])
+
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			// The header is the only row allowed to not start with a date.
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid date '%s' on CPI csv line %d: %w", date, i+1, err)
+		}
+		if rawValue == "." || rawValue == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(rawValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse CPI value '%s' on line %d: %w", rawValue, i+1, err)
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("CPI value %v on line %d must be positive", value, i+1)
+		}
+
+		records = append(records, types.CPIRecord{Date: date, Value: value})
+	}
+
+	if len(records) == 0 {
+		return nil, ErrNoCPIData
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Date < records[j].Date })
+	return records, nil
+}
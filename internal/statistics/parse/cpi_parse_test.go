@@ -0,0 +1,46 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Given a FRED export out of order with a missing observation, verify the header and the "." row
+// are skipped and the records come back oldest first.
+func TestParseCPISeriesCSV(t *testing.T) {
+	csvData := []byte("observation_date,CPIAUCSL\n" +
+		"2024-03-01,312.230\n" +
+		"2024-01-01,309.685\n" +
+		"2024-02-01,.\n")
+
+	expected := []types.CPIRecord{
+		{Date: "2024-01-01", Value: 309.685},
+		{Date: "2024-03-01", Value: 312.230},
+	}
+
+	records, err := ParseCPISeriesCSV(csvData)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseCPISeriesCSV() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given files with no usable observations or a bad row, verify each is rejected.
+func TestParseCPISeriesCSVErrors(t *testing.T) {
+	if _, err := ParseCPISeriesCSV([]byte("observation_date,CPIAUCSL\n")); !errors.Is(err, ErrNoCPIData) {
+		t.Errorf("Expected ErrNoCPIData for a header only file, but got: %v", err)
+	}
+	if _, err := ParseCPISeriesCSV([]byte("observation_date,CPIAUCSL\nJanuary,309.685\n")); err == nil {
+		t.Error("Expected an error for a row without a date, but got none")
+	}
+	if _, err := ParseCPISeriesCSV([]byte("observation_date,CPIAUCSL\n2024-01-01,abc\n")); err == nil {
+		t.Error("Expected an error for a non numeric value, but got none")
+	}
+}
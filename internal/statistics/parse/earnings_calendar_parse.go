@@ -0,0 +1,87 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"cibo/internal/types"
+)
+
+/*
+Parsing of the EARNINGS_CALENDAR endpoint. Unlike the rest of Alpha Vantage it answers with CSV
+instead of JSON, one row per scheduled report:
+
+	symbol,name,reportDate,fiscalDateEnding,estimate,currency,timeOfTheDay
+	IBM,International Business Machines Corp,2025-10-22,2025-09-30,2.45,USD,post-market
+
+Columns are looked up by header name since older responses don't have timeOfTheDay. An unknown
+symbol comes back as just the header row, so no rows isn't treated as an error.
+*/
+
+var earningsCalendarRequiredColumns = []string{"symbol", "reportDate"}
+
+func ParseEarningsCalendarToFlat(csvData []byte, skipErrors bool) ([]types.EarningsEventRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(csvData))
+	// Extra columns get added to the endpoint now and then, don't fail on rows that have them.
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading earnings calendar csv: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("earnings calendar csv is empty")
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range earningsCalendarRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("earnings calendar csv is missing the '%s' column", name)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	records := make([]types.EarningsEventRecord, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		ticker := field(row, "symbol")
+		reportDate := field(row, "reportDate")
+
+		estimatedEPS := 0.0
+		if raw := field(row, "estimate"); raw != "" {
+			estimatedEPS, err = strconv.ParseFloat(raw, 64)
+			if err != nil {
+				if skipErrors {
+					log.Printf("Warning: could not parse EPS estimate for %s on %s, skipping record. Error: %v", ticker, reportDate, err)
+					continue
+				}
+				return nil, fmt.Errorf("could not parse EPS estimate for %s on %s: %w", ticker, reportDate, err)
+			}
+		}
+
+		records = append(records, types.EarningsEventRecord{
+			Ticker:           ticker,
+			CompanyName:      field(row, "name"),
+			ReportDate:       reportDate,
+			FiscalDateEnding: field(row, "fiscalDateEnding"),
+			ReportTime:       field(row, "timeOfTheDay"),
+			EstimatedEPS:     estimatedEPS,
+			Source:           types.EarningsSourceCalendar,
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package parse
+
+import (
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Given a calendar with the time of day column and a row without an estimate, verify every column
+// is mapped and the missing estimate is left as zero.
+func TestParseEarningsCalendarToFlat(t *testing.T) {
+	csvData := []byte("symbol,name,reportDate,fiscalDateEnding,estimate,currency,timeOfTheDay\r\n" +
+		"IBM,International Business Machines Corp,2025-10-22,2025-09-30,2.45,USD,post-market\r\n" +
+		"IBM,International Business Machines Corp,2026-01-28,2025-12-31,,USD,\r\n")
+
+	expected := []types.EarningsEventRecord{
+		{Ticker: "IBM", CompanyName: "International Business Machines Corp", ReportDate: "2025-10-22",
+			FiscalDateEnding: "2025-09-30", ReportTime: "post-market", EstimatedEPS: 2.45, Source: types.EarningsSourceCalendar},
+		{Ticker: "IBM", CompanyName: "International Business Machines Corp", ReportDate: "2026-01-28",
+			FiscalDateEnding: "2025-12-31", Source: types.EarningsSourceCalendar},
+	}
+
+	records, err := ParseEarningsCalendarToFlat(csvData, false)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseEarningsCalendarToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given only a header row, which is how unknown symbols come back, verify no records and no error.
+func TestParseEarningsCalendarToFlatHeaderOnly(t *testing.T) {
+	records, err := ParseEarningsCalendarToFlat([]byte("symbol,name,reportDate,fiscalDateEnding,estimate,currency\n"), false)
+	if err != nil {
+		t.Fatalf("Expected no error for a header only calendar, but got: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected 0 records, but got %d", len(records))
+	}
+}
+
+// Given a body that isn't an earnings calendar, verify the missing columns are reported.
+func TestParseEarningsCalendarToFlatMissingColumns(t *testing.T) {
+	if _, err := ParseEarningsCalendarToFlat([]byte(`{"symbol": "IBM"}`), true); err == nil {
+		t.Error("Expected an error for a body without the calendar columns")
+	}
+}
+
+// Given a malformed estimate, verify the row is skipped when skipping errors and fails otherwise.
+func TestParseEarningsCalendarToFlatBadEstimate(t *testing.T) {
+	csvData := []byte("symbol,reportDate,estimate\nIBM,2025-10-22,n/a\nIBM,2026-01-28,2.50\n")
+
+	records, err := ParseEarningsCalendarToFlat(csvData, true)
+	if err != nil {
+		t.Fatalf("Expected no error when skipping bad records, but got: %v", err)
+	}
+	if len(records) != 1 || records[0].EstimatedEPS != 2.5 {
+		t.Errorf("Expected only the 2026-01-28 record, got %+v", records)
+	}
+
+	if _, err := ParseEarningsCalendarToFlat(csvData, false); err == nil {
+		t.Error("Expected an error for a bad estimate when not skipping errors")
+	}
+}
@@ -0,0 +1,228 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"cibo/internal/types"
+)
+
+/*
+Parsing of the company fundamentals endpoints (INCOME_STATEMENT, BALANCE_SHEET, CASH_FLOW). All return
+a large set of line items per report, only the ones a pipeline actually uses are mapped here.
+Missing values come back as the string "None" rather than being omitted.
+*/
+
+type IncomeStatementResponse struct {
+	Symbol        string                  `json:"symbol"`
+	AnnualReports []IncomeStatementReport `json:"annualReports"`
+}
+
+type IncomeStatementReport struct {
+	FiscalDateEnding string `json:"fiscalDateEnding"`
+	TotalRevenue     string `json:"totalRevenue"`
+}
+
+type BalanceSheetResponse struct {
+	Symbol        string               `json:"symbol"`
+	AnnualReports []BalanceSheetReport `json:"annualReports"`
+}
+
+type BalanceSheetReport struct {
+	FiscalDateEnding             string `json:"fiscalDateEnding"`
+	CommonStockSharesOutstanding string `json:"commonStockSharesOutstanding"`
+	TotalShareholderEquity       string `json:"totalShareholderEquity"`
+}
+
+type CashFlowResponse struct {
+	Symbol        string           `json:"symbol"`
+	AnnualReports []CashFlowReport `json:"annualReports"`
+}
+
+type CashFlowReport struct {
+	FiscalDateEnding    string `json:"fiscalDateEnding"`
+	OperatingCashflow   string `json:"operatingCashflow"`
+	CapitalExpenditures string `json:"capitalExpenditures"`
+}
+
+/*
+Function to take json data of income statements and parse it into a collection of annual
+revenue data points.
+*/
+func ParseAnnualRevenueToFlat(jsonData []byte, skipErrors bool) ([]types.AnnualRevenueRecord, error) {
+	var response IncomeStatementResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling income statement json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing income statement", ErrUnknownTicker)
+	}
+
+	records := make([]types.AnnualRevenueRecord, 0, len(response.AnnualReports))
+	for _, report := range response.AnnualReports {
+		revenue, err := strconv.ParseFloat(report.TotalRevenue, 64)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse total revenue for date %s, skipping record. Error: %v",
+					report.FiscalDateEnding, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse total revenue for date %s: %w", report.FiscalDateEnding, err)
+		}
+
+		records = append(records, types.AnnualRevenueRecord{
+			Ticker:           ticker,
+			FiscalDateEnding: report.FiscalDateEnding,
+			TotalRevenue:     revenue,
+		})
+	}
+
+	return records, nil
+}
+
+/*
+Function to take json data of balance sheets and parse it into a collection of annual shares
+outstanding data points.
+*/
+func ParseSharesOutstandingToFlat(jsonData []byte, skipErrors bool) ([]types.SharesOutstandingRecord, error) {
+	var response BalanceSheetResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling balance sheet json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing balance sheet", ErrUnknownTicker)
+	}
+
+	records := make([]types.SharesOutstandingRecord, 0, len(response.AnnualReports))
+	for _, report := range response.AnnualReports {
+		shares, err := strconv.ParseFloat(report.CommonStockSharesOutstanding, 64)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse shares outstanding for date %s, skipping record. Error: %v",
+					report.FiscalDateEnding, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse shares outstanding for date %s: %w", report.FiscalDateEnding, err)
+		}
+
+		records = append(records, types.SharesOutstandingRecord{
+			Ticker:            ticker,
+			FiscalDateEnding:  report.FiscalDateEnding,
+			SharesOutstanding: shares,
+		})
+	}
+
+	return records, nil
+}
+
+/*
+Function to take json data of balance sheets and parse it into a collection of annual book value
+per share data points, total shareholder equity over common shares outstanding. Reports without
+shares outstanding can't be put per share and are treated like unparseable ones.
+*/
+func ParseBookValuePerShareToFlat(jsonData []byte, skipErrors bool) ([]types.BookValuePerShareRecord, error) {
+	var response BalanceSheetResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling balance sheet json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing balance sheet", ErrUnknownTicker)
+	}
+
+	records := make([]types.BookValuePerShareRecord, 0, len(response.AnnualReports))
+	for _, report := range response.AnnualReports {
+		bookValuePerShare, err := bookValuePerShare(report)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse book value per share for date %s, skipping record. Error: %v",
+					report.FiscalDateEnding, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse book value per share for date %s: %w", report.FiscalDateEnding, err)
+		}
+
+		records = append(records, types.BookValuePerShareRecord{
+			Ticker:            ticker,
+			FiscalDateEnding:  report.FiscalDateEnding,
+			BookValuePerShare: bookValuePerShare,
+		})
+	}
+
+	return records, nil
+}
+
+func bookValuePerShare(report BalanceSheetReport) (float64, error) {
+	equity, err := strconv.ParseFloat(report.TotalShareholderEquity, 64)
+	if err != nil {
+		return 0, fmt.Errorf("total shareholder equity: %w", err)
+	}
+	shares, err := strconv.ParseFloat(report.CommonStockSharesOutstanding, 64)
+	if err != nil {
+		return 0, fmt.Errorf("shares outstanding: %w", err)
+	}
+	if shares <= 0 {
+		return 0, fmt.Errorf("shares outstanding must be positive, got %v", shares)
+	}
+	return equity / shares, nil
+}
+
+/*
+Function to take json data of cash flow statements and parse it into a collection of annual free
+cash flow data points, operating cash flow minus capital expenditures. Alpha Vantage reports
+capital expenditures as a positive outflow, but the sign isn't relied on since some filings flip it.
+*/
+func ParseFreeCashFlowToFlat(jsonData []byte, skipErrors bool) ([]types.FreeCashFlowRecord, error) {
+	var response CashFlowResponse
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling cash flow json: %w", err)
+	}
+
+	ticker := response.Symbol
+	if ticker == "" {
+		return nil, fmt.Errorf("%w: ticker not found in JSON when parsing cash flow", ErrUnknownTicker)
+	}
+
+	records := make([]types.FreeCashFlowRecord, 0, len(response.AnnualReports))
+	for _, report := range response.AnnualReports {
+		record, err := freeCashFlow(ticker, report)
+		if err != nil {
+			if skipErrors {
+				log.Printf("Warning: could not parse free cash flow for date %s, skipping record. Error: %v",
+					report.FiscalDateEnding, err)
+				continue
+			}
+			return nil, fmt.Errorf("could not parse free cash flow for date %s: %w", report.FiscalDateEnding, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func freeCashFlow(ticker string, report CashFlowReport) (types.FreeCashFlowRecord, error) {
+	operating, err := strconv.ParseFloat(report.OperatingCashflow, 64)
+	if err != nil {
+		return types.FreeCashFlowRecord{}, fmt.Errorf("operating cash flow: %w", err)
+	}
+	capex, err := strconv.ParseFloat(report.CapitalExpenditures, 64)
+	if err != nil {
+		return types.FreeCashFlowRecord{}, fmt.Errorf("capital expenditures: %w", err)
+	}
+	capex = math.Abs(capex)
+	return types.FreeCashFlowRecord{
+		Ticker:              ticker,
+		FiscalDateEnding:    report.FiscalDateEnding,
+		OperatingCashFlow:   operating,
+		CapitalExpenditures: capex,
+		FreeCashFlow:        operating - capex,
+	}, nil
+}
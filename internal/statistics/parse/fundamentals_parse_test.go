@@ -0,0 +1,158 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+
+	"cibo/internal/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Given a valid income statement, verify that annual revenue is parsed for every report.
+func TestParseAnnualRevenueHappyPath(t *testing.T) {
+	jsonData := []byte(`{
+			"symbol": "IBM",
+			"annualReports": [
+				{ "fiscalDateEnding": "2024-12-31", "totalRevenue": "62753000000", "grossProfit": "35551000000" },
+				{ "fiscalDateEnding": "2023-12-31", "totalRevenue": "61860000000", "grossProfit": "34300000000" }
+			],
+			"quarterlyReports": []
+		}`)
+
+	expected := []types.AnnualRevenueRecord{
+		{Ticker: "IBM", FiscalDateEnding: "2024-12-31", TotalRevenue: 62753000000},
+		{Ticker: "IBM", FiscalDateEnding: "2023-12-31", TotalRevenue: 61860000000},
+	}
+
+	records, err := ParseAnnualRevenueToFlat(jsonData, false)
+	if err != nil {
+		t.Fatalf("ParseAnnualRevenueToFlat() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseAnnualRevenueToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a "None" revenue, verify strict mode errors and permissive mode skips the report.
+func TestParseAnnualRevenueNone(t *testing.T) {
+	jsonData := []byte(`{
+			"symbol": "IBM",
+			"annualReports": [
+				{ "fiscalDateEnding": "2024-12-31", "totalRevenue": "62753000000" },
+				{ "fiscalDateEnding": "2023-12-31", "totalRevenue": "None" }
+			]
+		}`)
+
+	if _, err := ParseAnnualRevenueToFlat(jsonData, false); err == nil {
+		t.Fatal("Expected an error for a 'None' revenue in strict mode, but got nil")
+	}
+
+	records, err := ParseAnnualRevenueToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("ParseAnnualRevenueToFlat() returned an unexpected error: %v", err)
+	}
+	expected := []types.AnnualRevenueRecord{
+		{Ticker: "IBM", FiscalDateEnding: "2024-12-31", TotalRevenue: 62753000000},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseAnnualRevenueToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a response without a symbol, verify an unknown ticker error is returned.
+func TestParseAnnualRevenueMissingTicker(t *testing.T) {
+	_, err := ParseAnnualRevenueToFlat([]byte(`{"annualReports": []}`), true)
+	if !errors.Is(err, ErrUnknownTicker) {
+		t.Errorf("Expected an unknown ticker error, but got: %v", err)
+	}
+}
+
+// Given a valid balance sheet, verify that shares outstanding are parsed for every report.
+func TestParseSharesOutstandingHappyPath(t *testing.T) {
+	jsonData := []byte(`{
+			"symbol": "IBM",
+			"annualReports": [
+				{ "fiscalDateEnding": "2024-12-31", "totalAssets": "137175000000", "commonStockSharesOutstanding": "937200000" },
+				{ "fiscalDateEnding": "2023-12-31", "totalAssets": "135241000000", "commonStockSharesOutstanding": "None" }
+			]
+		}`)
+
+	expected := []types.SharesOutstandingRecord{
+		{Ticker: "IBM", FiscalDateEnding: "2024-12-31", SharesOutstanding: 937200000},
+	}
+
+	records, err := ParseSharesOutstandingToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("ParseSharesOutstandingToFlat() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseSharesOutstandingToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given malformed json, should return an unmarshaling error
+func TestParseSharesOutstandingMalformedJson(t *testing.T) {
+	if _, err := ParseSharesOutstandingToFlat([]byte(`{"symbol": "IBM", "annualReports": [`), true); err == nil {
+		t.Fatal("Expected an error for malformed JSON, but got nil")
+	}
+}
+
+// Given a balance sheet with one usable report, one "None" equity and one zero share count, verify
+// book value per share is only parsed for the usable report in permissive mode.
+func TestParseBookValuePerShare(t *testing.T) {
+	jsonData := []byte(`{
+			"symbol": "IBM",
+			"annualReports": [
+				{ "fiscalDateEnding": "2024-12-31", "totalShareholderEquity": "27307000000", "commonStockSharesOutstanding": "937200000" },
+				{ "fiscalDateEnding": "2023-12-31", "totalShareholderEquity": "None", "commonStockSharesOutstanding": "920000000" },
+				{ "fiscalDateEnding": "2022-12-31", "totalShareholderEquity": "21944000000", "commonStockSharesOutstanding": "0" }
+			]
+		}`)
+
+	if _, err := ParseBookValuePerShareToFlat(jsonData, false); err == nil {
+		t.Fatal("Expected an error for a 'None' equity in strict mode, but got nil")
+	}
+
+	expected := []types.BookValuePerShareRecord{
+		{Ticker: "IBM", FiscalDateEnding: "2024-12-31", BookValuePerShare: 27307000000.0 / 937200000},
+	}
+
+	records, err := ParseBookValuePerShareToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("ParseBookValuePerShareToFlat() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseBookValuePerShareToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a report with a negative capex sign and one with a missing value, verify free cash flow
+// subtracts the amount spent either way and the missing year is only skipped when asked to.
+func TestParseFreeCashFlow(t *testing.T) {
+	jsonData := []byte(`{
+			"symbol": "IBM",
+			"annualReports": [
+				{ "fiscalDateEnding": "2024-12-31", "operatingCashflow": "13445000000", "capitalExpenditures": "1685000000" },
+				{ "fiscalDateEnding": "2023-12-31", "operatingCashflow": "13931000000", "capitalExpenditures": "-1813000000" },
+				{ "fiscalDateEnding": "2022-12-31", "operatingCashflow": "None", "capitalExpenditures": "1346000000" }
+			]
+		}`)
+
+	if _, err := ParseFreeCashFlowToFlat(jsonData, false); err == nil {
+		t.Fatal("Expected an error for a 'None' operating cash flow in strict mode, but got nil")
+	}
+
+	expected := []types.FreeCashFlowRecord{
+		{Ticker: "IBM", FiscalDateEnding: "2024-12-31", OperatingCashFlow: 13445000000, CapitalExpenditures: 1685000000, FreeCashFlow: 11760000000},
+		{Ticker: "IBM", FiscalDateEnding: "2023-12-31", OperatingCashFlow: 13931000000, CapitalExpenditures: 1813000000, FreeCashFlow: 12118000000},
+	}
+
+	records, err := ParseFreeCashFlowToFlat(jsonData, true)
+	if err != nil {
+		t.Fatalf("ParseFreeCashFlowToFlat() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("ParseFreeCashFlowToFlat() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -2,74 +2,97 @@ package utils
 
 import (
 	"cibo/internal/types"
+	"errors"
 	"fmt"
 	"time"
 )
 
 const layout = "2006-01-02" // The reference layout for YYYY-MM-DD in Go.
 
-// Filters a slice of DailyStockRecord based on a start and end date and returns the data WITHIN those dates.
-// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
-func FilterDailyPricesWithinDateRange(records []types.DailyStockRecord, startDateStr, endDateStr string) ([]types.DailyStockRecord, error) {
-	var startDate, endDate time.Time
-	var err error
+var ErrInvalidDateRange = errors.New("invalid date range")
 
+// Checks that the optional start and end dates are YYYY-MM-DD and in order, so bad user input can
+// be rejected before any API calls are spent on it.
+func ValidateDateRange(startDateStr, endDateStr string) error {
+	_, _, err := parseDateRange(startDateStr, endDateStr)
+	return err
+}
+
+// Parses an optional date range, empty strings come back as zero times.
+func parseDateRange(startDateStr, endDateStr string) (startDate time.Time, endDate time.Time, err error) {
 	if startDateStr != "" {
 		startDate, err = time.Parse(layout, startDateStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid start date format: %w", err)
+			return startDate, endDate, fmt.Errorf("%w: invalid start date format: %w", ErrInvalidDateRange, err)
 		}
 	}
 
 	if endDateStr != "" {
 		endDate, err = time.Parse(layout, endDateStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end date format: %w", err)
+			return startDate, endDate, fmt.Errorf("%w: invalid end date format: %w", ErrInvalidDateRange, err)
 		}
 	}
 
-	filteredRecords := []types.DailyStockRecord{}
-
-	for _, record := range records {
-		recordDate, err := time.Parse(layout, record.Date)
-		if err != nil {
-			continue // Skip records with un-parse-able dates
-		}
-
-		isAfterStartDate := startDate.IsZero() || !recordDate.Before(startDate)
-		isBeforeEndDate := endDate.IsZero() || !recordDate.After(endDate)
-
-		if isAfterStartDate && isBeforeEndDate {
-			filteredRecords = append(filteredRecords, record)
-		}
+	if !startDate.IsZero() && !endDate.IsZero() && startDate.After(endDate) {
+		return startDate, endDate, fmt.Errorf("%w: start date %s is after end date %s", ErrInvalidDateRange, startDateStr, endDateStr)
 	}
 
-	return filteredRecords, nil
+	return startDate, endDate, nil
+}
+
+// Filters a slice of DailyStockRecord based on a start and end date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterDailyPricesWithinDateRange(records []types.DailyStockRecord, startDateStr, endDateStr string) ([]types.DailyStockRecord, error) {
+	return filterWithinDateRange(records, func(record types.DailyStockRecord) string { return record.Date }, startDateStr, endDateStr)
 }
 
 // Filters a slice of AnnualEarningRecord based on a start and end date and returns the data WITHIN those dates.
 // If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
 func FilterAnnualEarningsWithinDateRange(records []types.AnnualEarningRecord, startDateStr, endDateStr string) ([]types.AnnualEarningRecord, error) {
-	var startDate, endDate time.Time
-	var err error
+	return filterWithinDateRange(records, func(record types.AnnualEarningRecord) string { return record.FiscalDateEnding }, startDateStr, endDateStr)
+}
 
-	if startDateStr != "" {
-		startDate, err = time.Parse(layout, startDateStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid start date format: %w", err)
-		}
-	}
+// Filters a slice of RevenuePerShareRecord based on a start and end date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterRevenuePerShareWithinDateRange(records []types.RevenuePerShareRecord, startDateStr, endDateStr string) ([]types.RevenuePerShareRecord, error) {
+	return filterWithinDateRange(records, func(record types.RevenuePerShareRecord) string { return record.FiscalDateEnding }, startDateStr, endDateStr)
+}
 
-	if endDateStr != "" {
-		endDate, err = time.Parse(layout, endDateStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid end date format: %w", err)
-		}
+// Filters a slice of FreeCashFlowPerShareRecord based on a start and end date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterFreeCashFlowPerShareWithinDateRange(records []types.FreeCashFlowPerShareRecord, startDateStr, endDateStr string) ([]types.FreeCashFlowPerShareRecord, error) {
+	return filterWithinDateRange(records, func(record types.FreeCashFlowPerShareRecord) string { return record.FiscalDateEnding }, startDateStr, endDateStr)
+}
+
+// Filters a slice of FairValuePriceRecord based on a start and end date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterFairValuesWithinDateRange(records []types.FairValuePriceRecord, startDateStr, endDateStr string) ([]types.FairValuePriceRecord, error) {
+	return filterWithinDateRange(records, func(record types.FairValuePriceRecord) string { return record.Date }, startDateStr, endDateStr)
+}
+
+// Filters a slice of DividendRecord on ex-dividend date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterDividendsWithinDateRange(records []types.DividendRecord, startDateStr, endDateStr string) ([]types.DividendRecord, error) {
+	return filterWithinDateRange(records, func(record types.DividendRecord) string { return record.ExDividendDate }, startDateStr, endDateStr)
+}
+
+// Filters a slice of EarningsSurpriseRecord on report date and returns the data WITHIN those dates.
+// If startDateStr or endDateStr are empty, they are ignored and all data returned beyonds those values.
+func FilterEarningsSurprisesWithinDateRange(records []types.EarningsSurpriseRecord, startDateStr, endDateStr string) ([]types.EarningsSurpriseRecord, error) {
+	return filterWithinDateRange(records, func(record types.EarningsSurpriseRecord) string { return record.ReportedDate }, startDateStr, endDateStr)
+}
+
+// Shared implementation of the date range filters, dateOf picks which field of a record holds its date.
+func filterWithinDateRange[T any](records []T, dateOf func(T) string, startDateStr, endDateStr string) ([]T, error) {
+	startDate, endDate, err := parseDateRange(startDateStr, endDateStr)
+	if err != nil {
+		return nil, err
 	}
 
-	filteredRecords := []types.AnnualEarningRecord{}
+	filteredRecords := []T{}
 	for _, record := range records {
-		recordDate, err := time.Parse(layout, record.FiscalDateEnding)
+		recordDate, err := time.Parse(layout, dateOf(record))
 		if err != nil {
 			continue // Skip records with un-parse-able dates
 		}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"cibo/internal/types"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -116,6 +117,14 @@ func TestFilterDailyPricesInvalidEndDate(t *testing.T) {
 	}
 }
 
+// Given a start date after the end date, verify that an invalid date range error is returned.
+func TestFilterDailyPricesStartAfterEnd(t *testing.T) {
+	_, err := FilterDailyPricesWithinDateRange(testDailyPrices, "2024-12-31", "2024-01-01")
+	if !errors.Is(err, ErrInvalidDateRange) {
+		t.Fatalf("Expected an invalid date range error, but got: %v", err)
+	}
+}
+
 // Given an empty slice of records, verify that an empty slice is returned without error.
 func TestFilterDailyPricesEmptyInput(t *testing.T) {
 	emptyRecords := []types.DailyStockRecord{}
@@ -2,9 +2,12 @@ package utils
 
 import (
 	"cibo/internal/types"
+	"math"
 )
 
-// AdjustForStockSplits adjusts historical stock prices for stock splits. Assumes data is pre sorted by date
+// AdjustForStockSplits adjusts historical stock prices for stock splits. Assumes data is pre sorted by date.
+// Every price field is divided by the cumulative split factor and volume multiplied by it, so a
+// 4:1 split turns 100 shares at $400 into 400 shares at $100.
 func AdjustForStockSplits(dailyPrices []types.DailyStockRecord, splits []types.StockSplitRecord) []types.DailyStockRecord {
 	adjustedPrices := make([]types.DailyStockRecord, len(dailyPrices))
 	copy(adjustedPrices, dailyPrices)
@@ -23,9 +26,67 @@ func AdjustForStockSplits(dailyPrices []types.DailyStockRecord, splits []types.S
 		}
 
 		if cumulativeFactor != 1.0 {
+			adjustedPrices[i].OpenPrice /= cumulativeFactor
+			adjustedPrices[i].HighPrice /= cumulativeFactor
+			adjustedPrices[i].LowPrice /= cumulativeFactor
 			adjustedPrices[i].ClosingPrice /= cumulativeFactor
+			adjustedPrices[i].Volume = int64(math.Round(float64(adjustedPrices[i].Volume) * cumulativeFactor))
 		}
 	}
 
 	return adjustedPrices
 }
+
+// AdjustSharesForStockSplits restates historical share counts in today's shares, so per share
+// figures line up with split adjusted prices. Every split that took effect after a report's fiscal
+// date multiplies its share count by the split factor.
+func AdjustSharesForStockSplits(shares []types.SharesOutstandingRecord, splits []types.StockSplitRecord) []types.SharesOutstandingRecord {
+	adjustedShares := make([]types.SharesOutstandingRecord, len(shares))
+	copy(adjustedShares, shares)
+
+	for i := range adjustedShares {
+		for _, split := range splits {
+			if adjustedShares[i].FiscalDateEnding < split.EffectiveDate {
+				adjustedShares[i].SharesOutstanding *= split.SplitFactor
+			}
+		}
+	}
+
+	return adjustedShares
+}
+
+// AdjustDividendsForStockSplits restates historical dividend amounts per today's shares, so they
+// line up with split adjusted prices. Every split that took effect after a dividend's ex-dividend
+// date divides its amount by the split factor.
+func AdjustDividendsForStockSplits(dividends []types.DividendRecord, splits []types.StockSplitRecord) []types.DividendRecord {
+	adjustedDividends := make([]types.DividendRecord, len(dividends))
+	copy(adjustedDividends, dividends)
+
+	for i := range adjustedDividends {
+		for _, split := range splits {
+			if adjustedDividends[i].ExDividendDate < split.EffectiveDate {
+				adjustedDividends[i].Amount /= split.SplitFactor
+			}
+		}
+	}
+
+	return adjustedDividends
+}
+
+// AdjustBookValueForStockSplits restates historical book value per share per today's shares, so it
+// lines up with split adjusted prices and EPS. Every split that took effect after a report's fiscal
+// date divides its book value per share by the split factor.
+func AdjustBookValueForStockSplits(bookValues []types.BookValuePerShareRecord, splits []types.StockSplitRecord) []types.BookValuePerShareRecord {
+	adjustedBookValues := make([]types.BookValuePerShareRecord, len(bookValues))
+	copy(adjustedBookValues, bookValues)
+
+	for i := range adjustedBookValues {
+		for _, split := range splits {
+			if adjustedBookValues[i].FiscalDateEnding < split.EffectiveDate {
+				adjustedBookValues[i].BookValuePerShare /= split.SplitFactor
+			}
+		}
+	}
+
+	return adjustedBookValues
+}
@@ -41,6 +41,29 @@ func TestAdjustForStockSplits_SingleSplit(t *testing.T) {
 	}
 }
 
+// Given full OHLCV records, verify every price field is divided by the split factor and volume is
+// multiplied by it.
+func TestAdjustForStockSplits_OHLCV(t *testing.T) {
+	prices := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2024-07-03", OpenPrice: 100, HighPrice: 110, LowPrice: 90, ClosingPrice: 105, Volume: 2000},
+		{Ticker: "TEST", Date: "2024-07-02", OpenPrice: 400, HighPrice: 420, LowPrice: 380, ClosingPrice: 404, Volume: 500},
+	}
+	splits := []types.StockSplitRecord{
+		{Ticker: "TEST", EffectiveDate: "2024-07-03", SplitFactor: 4.0},
+	}
+
+	expected := []types.DailyStockRecord{
+		{Ticker: "TEST", Date: "2024-07-03", OpenPrice: 100, HighPrice: 110, LowPrice: 90, ClosingPrice: 105, Volume: 2000},
+		{Ticker: "TEST", Date: "2024-07-02", OpenPrice: 100, HighPrice: 105, LowPrice: 95, ClosingPrice: 101, Volume: 2000},
+	}
+
+	result := AdjustForStockSplits(prices, splits)
+
+	if diff := cmp.Diff(expected, result, cmpopts.EquateApprox(0.001, 0)); diff != "" {
+		t.Errorf("Adjusted OHLCV mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // Given multiple stock splits, verify that prices are adjusted by the cumulative factor.
 func TestAdjustForStockSplits_MultipleSplits(t *testing.T) {
 	prices := []types.DailyStockRecord{
@@ -141,3 +164,84 @@ func TestAdjustForStockSplits_SplitOnTradingDay(t *testing.T) {
 		t.Errorf("Adjusted prices mismatch (-want +got):\n%s", diff)
 	}
 }
+
+// Given share counts reported before and after a split, verify only the earlier counts are
+// restated in post split shares.
+func TestAdjustSharesForStockSplits(t *testing.T) {
+	shares := []types.SharesOutstandingRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", SharesOutstanding: 2000},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", SharesOutstanding: 1000},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", SharesOutstanding: 250},
+	}
+	splits := []types.StockSplitRecord{
+		{Ticker: "TEST", EffectiveDate: "2024-06-01", SplitFactor: 2.0},
+		{Ticker: "TEST", EffectiveDate: "2023-06-01", SplitFactor: 2.0},
+	}
+
+	expected := []types.SharesOutstandingRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", SharesOutstanding: 2000},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", SharesOutstanding: 2000},
+		{Ticker: "TEST", FiscalDateEnding: "2022-12-31", SharesOutstanding: 1000},
+	}
+
+	result := AdjustSharesForStockSplits(shares, splits)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Adjusted shares mismatch (-want +got):\n%s", diff)
+	}
+	if shares[1].SharesOutstanding != 1000 {
+		t.Error("AdjustSharesForStockSplits() modified its input")
+	}
+}
+
+// Given dividends paid before and after a split, verify only the earlier amounts are restated in
+// post split shares.
+func TestAdjustDividendsForStockSplits(t *testing.T) {
+	dividends := []types.DividendRecord{
+		{Ticker: "TEST", ExDividendDate: "2024-08-09", Amount: 0.5},
+		{Ticker: "TEST", ExDividendDate: "2024-05-10", Amount: 1.0},
+		{Ticker: "TEST", ExDividendDate: "2023-05-10", Amount: 2.0},
+	}
+	splits := []types.StockSplitRecord{
+		{Ticker: "TEST", EffectiveDate: "2024-06-01", SplitFactor: 2.0},
+		{Ticker: "TEST", EffectiveDate: "2023-06-01", SplitFactor: 2.0},
+	}
+
+	expected := []types.DividendRecord{
+		{Ticker: "TEST", ExDividendDate: "2024-08-09", Amount: 0.5},
+		{Ticker: "TEST", ExDividendDate: "2024-05-10", Amount: 0.5},
+		{Ticker: "TEST", ExDividendDate: "2023-05-10", Amount: 0.5},
+	}
+
+	result := AdjustDividendsForStockSplits(dividends, splits)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Adjusted dividends mismatch (-want +got):\n%s", diff)
+	}
+	if dividends[1].Amount != 1.0 {
+		t.Error("AdjustDividendsForStockSplits() modified its input")
+	}
+}
+
+// Given book values reported before and after a split, verify only the earlier ones are restated.
+func TestAdjustBookValueForStockSplits(t *testing.T) {
+	bookValues := []types.BookValuePerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", BookValuePerShare: 10},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", BookValuePerShare: 18},
+	}
+	splits := []types.StockSplitRecord{{Ticker: "TEST", EffectiveDate: "2024-06-10", SplitFactor: 2.0}}
+
+	expected := []types.BookValuePerShareRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2024-12-31", BookValuePerShare: 10},
+		{Ticker: "TEST", FiscalDateEnding: "2023-12-31", BookValuePerShare: 9},
+	}
+
+	result := AdjustBookValueForStockSplits(bookValues, splits)
+
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Adjusted book values mismatch (-want +got):\n%s", diff)
+	}
+	if bookValues[1].BookValuePerShare != 18 {
+		t.Error("AdjustBookValueForStockSplits() modified its input")
+	}
+}
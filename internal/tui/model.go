@@ -2,8 +2,13 @@ package tui
 
 import (
 	"cibo/internal/pipelines"
+	"cibo/internal/statistics/cache"
+	"cibo/internal/types"
 	"cibo/internal/web"
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -23,10 +28,14 @@ Docs on Bubble Tea can be found here: github.com/charmbracelet/bubbletea
 
 const maxLogMessages = 20
 
+// Most recent valuation signals flagged in the log pane, the rest are in the signals file.
+const maxSignalLogs = 5
+
 const (
 	InfoLog LogType = iota
 	SuccessLog
 	ErrorLog
+	SignalLog
 )
 
 var (
@@ -37,12 +46,19 @@ var (
 	helpStyle           = blurredStyle
 	successMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Bold(true)
 	errorMessageStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	signalMessageStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
 )
 
 type processSuccessMsg struct {
 	recordCount int
 	filePath    string
 	logs        []string
+	// The latest crossings into the over or undervalued band, oldest first.
+	signals []string
+	// The growth rate and P/E the fair value was calculated with.
+	modelSummary string
+	// How many responses came from the on disk cache vs. the network, empty when uncached.
+	cacheSummary string
 }
 
 type processErrorMsg struct {
@@ -74,6 +90,8 @@ type model struct {
 	logs               []LogEntry
 	width              int
 	height             int
+	// Cancels the in flight pipeline run, nil when nothing is running.
+	cancelRun context.CancelFunc
 }
 
 func (m model) reset() model {
@@ -94,7 +112,7 @@ func (m model) reset() model {
 // Defines the initial state of the TUI
 func NewModel(pipelines *pipelines.Pipelines, initialLogs []string) model {
 	m := model{
-		inputs:    make([]textinput.Model, 3),
+		inputs:    make([]textinput.Model, 8),
 		logs:      make([]LogEntry, 0),
 		pipelines: pipelines,
 	}
@@ -131,6 +149,32 @@ func NewModel(pipelines *pipelines.Pipelines, initialLogs []string) model {
 			t.Prompt = "End Date:     "
 			t.CharLimit = 10
 			t.Width = 10
+		// Optional Lynch model parameters, left empty for the plain model.
+		case 3:
+			t.Placeholder = "all"
+			t.Prompt = "CAGR Years:   "
+			t.CharLimit = 2
+			t.Width = 3
+		case 4:
+			t.Placeholder = "from CAGR"
+			t.Prompt = "Fair P/E:     "
+			t.CharLimit = 6
+			t.Width = 9
+		case 5:
+			t.Placeholder = "none"
+			t.Prompt = "Min P/E:      "
+			t.CharLimit = 6
+			t.Width = 6
+		case 6:
+			t.Placeholder = "none"
+			t.Prompt = "Max P/E:      "
+			t.CharLimit = 6
+			t.Width = 6
+		case 7:
+			t.Placeholder = "endpoints"
+			t.Prompt = "Growth Fit:   "
+			t.CharLimit = 10
+			t.Width = 10
 		}
 		m.inputs[i] = t
 	}
@@ -164,6 +208,21 @@ func (m *model) logError(msg string) {
 	m.log(LogEntry{Type: ErrorLog, Message: msg})
 }
 
+func (m *model) logSignal(msg string) {
+	m.log(LogEntry{Type: SignalLog, Message: msg})
+}
+
+// e.g. "TEST overvalued on 2025-02-03, closed at 195.00 against a fair value of 150.00 (+30.00%)"
+func signalMessages(signals []types.ValuationSignalRecord) []string {
+	signals = signals[max(len(signals)-maxSignalLogs, 0):]
+	messages := make([]string, len(signals))
+	for i, signal := range signals {
+		messages[i] = fmt.Sprintf("%s %s on %s, closed at %.2f against a fair value of %.2f (%+.2f%%)",
+			signal.Ticker, signal.Signal, signal.Date, signal.Price, signal.FairValuePrice, signal.PremiumPct)
+	}
+	return messages
+}
+
 func (m model) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -177,26 +236,81 @@ func (m model) launchWebUICmd() tea.Msg {
 	return webUILaunchedMsg{url: url}
 }
 
-func (m model) processDataCmd() tea.Msg {
+func (m model) processDataCmd(ctx context.Context) tea.Cmd {
 	ticker := m.inputs[0].Value()
 	startDate := m.inputs[1].Value()
 	endDate := m.inputs[2].Value()
-	m.logInfo(fmt.Sprintf("Fetching data for %s...", ticker))
+	modelParams, err := m.modelParams()
+	if err != nil {
+		return func() tea.Msg { return processErrorMsg{err: err} }
+	}
 	lynchFairValueInputs := pipelines.LynchFairValueInputs{
 		Ticker:    ticker,
 		StartDate: startDate,
 		EndDate:   endDate,
+		// No extra API calls, and the markers show up on the chart the TUI launches.
+		IncludeSignals: true,
+		Model:          modelParams,
 	}
 
-	lynchFairValueOutputs, err := m.pipelines.LynchFairValue.RunPipeline(lynchFairValueInputs)
-	if err != nil {
-		return processErrorMsg{err: err}
+	return func() tea.Msg {
+		ctx, fetchReport := cache.WithFetchReport(ctx)
+		lynchFairValueOutputs, err := m.pipelines.LynchFairValue.RunPipeline(ctx, lynchFairValueInputs)
+		if err != nil {
+			return processErrorMsg{err: err}
+		}
+
+		return processSuccessMsg{
+			recordCount:  lynchFairValueOutputs.RecordCount,
+			filePath:     lynchFairValueOutputs.FilePath,
+			logs:         lynchFairValueOutputs.Logs,
+			signals:      signalMessages(lynchFairValueOutputs.Signals),
+			modelSummary: lynchFairValueOutputs.Model.String(),
+			cacheSummary: fetchReport.Summary(),
+		}
 	}
+}
 
-	return processSuccessMsg{
-		recordCount: lynchFairValueOutputs.RecordCount,
-		filePath:    lynchFairValueOutputs.FilePath,
-		logs:        lynchFairValueOutputs.Logs,
+// Reads the optional model parameter inputs, an empty input leaves that parameter at its default.
+func (m model) modelParams() (pipelines.LynchModelParams, error) {
+	var params pipelines.LynchModelParams
+	if value := strings.TrimSpace(m.inputs[3].Value()); value != "" {
+		years, err := strconv.Atoi(value)
+		if err != nil {
+			return params, fmt.Errorf("CAGR years must be a whole number, got %q", value)
+		}
+		params.CAGRYears = years
+	}
+	peInputs := []struct {
+		input  textinput.Model
+		target *float64
+		name   string
+	}{
+		{m.inputs[4], &params.PEOverride, "fair P/E"},
+		{m.inputs[5], &params.MinPE, "min P/E"},
+		{m.inputs[6], &params.MaxPE, "max P/E"},
+	}
+	for _, pe := range peInputs {
+		value := strings.TrimSpace(pe.input.Value())
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return params, fmt.Errorf("%s must be a number, got %q", pe.name, value)
+		}
+		*pe.target = parsed
+	}
+	// Checked against the known methods by the pipeline, like the other parameters.
+	params.GrowthMethod = strings.ToLower(strings.TrimSpace(m.inputs[7].Value()))
+	return params, nil
+}
+
+// Cancels any in flight pipeline run so quitting doesn't leave API requests hanging.
+func (m *model) cancelInFlightRun() {
+	if m.cancelRun != nil {
+		m.cancelRun()
+		m.cancelRun = nil
 	}
 }
 
@@ -218,19 +332,37 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case processSuccessMsg:
+		m.cancelInFlightRun()
 		m.loading = false
 		m.processingComplete = true
 		m.resultFilePath = msg.filePath
+		if msg.cacheSummary != "" {
+			m.logInfo(msg.cacheSummary)
+		}
+		if msg.modelSummary != "" {
+			m.logInfo(msg.modelSummary)
+		}
 		for _, log := range msg.logs {
 			m.logSuccess(log)
 		}
+		for _, signal := range msg.signals {
+			m.logSignal(signal)
+		}
 		cmd = m.launchUIPrompt.Focus()
 		return m, cmd
 
 	case processErrorMsg:
+		m.cancelInFlightRun()
 		m.loading = false
 		m.err = msg.err
+		if errors.Is(msg.err, context.Canceled) {
+			m.logInfo("Run cancelled.")
+			return m, nil
+		}
 		m.logError(fmt.Sprintf("Error: %v", msg.err))
+		if guidance := pipelines.Guidance(msg.err); guidance != "" {
+			m.logInfo(guidance)
+		}
 
 		return m, nil
 
@@ -256,12 +388,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// --- Handle main form ---
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
+			m.cancelInFlightRun()
 			return m, tea.Quit
 		case "tab", "shift+tab", "enter", "up", "down":
 			s := msg.String()
 			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if m.loading {
+					return m, nil
+				}
 				m.loading = true
-				return m, tea.Batch(m.spinner.Tick, m.processDataCmd)
+				m.logInfo(fmt.Sprintf("Fetching data for %s...", m.inputs[0].Value()))
+				ctx, cancel := context.WithCancel(context.Background())
+				m.cancelRun = cancel
+				return m, tea.Batch(m.spinner.Tick, m.processDataCmd(ctx))
 			}
 			if s == "up" || s == "shift+tab" {
 				m.focusIndex--
@@ -347,6 +486,8 @@ func (m model) View() string {
 			styledLogs = append(styledLogs, successMessageStyle.Render(logEntry.Message))
 		case ErrorLog:
 			styledLogs = append(styledLogs, errorMessageStyle.Render(logEntry.Message))
+		case SignalLog:
+			styledLogs = append(styledLogs, signalMessageStyle.Render(logEntry.Message))
 		default:
 			styledLogs = append(styledLogs, logEntry.Message)
 		}
@@ -2,7 +2,10 @@ package tui
 
 import (
 	"cibo/internal/pipelines"
+	"cibo/internal/types"
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -14,11 +17,17 @@ type mockFairValuePipeline struct {
 	outputToReturn  *pipelines.LynchFairValueOutputs
 	wasCalled       bool
 	receivedTicker  string
+	receivedModel   pipelines.LynchModelParams
+	receivedSignals bool
+	receivedCtx     context.Context
 }
 
-func (m *mockFairValuePipeline) RunPipeline(input pipelines.LynchFairValueInputs) (*pipelines.LynchFairValueOutputs, error) {
+func (m *mockFairValuePipeline) RunPipeline(ctx context.Context, input pipelines.LynchFairValueInputs) (*pipelines.LynchFairValueOutputs, error) {
 	m.wasCalled = true
 	m.receivedTicker = input.Ticker
+	m.receivedModel = input.Model
+	m.receivedSignals = input.IncludeSignals
+	m.receivedCtx = ctx
 	if m.shouldReturnErr {
 		return nil, errors.New("mock pipeline error")
 	}
@@ -80,7 +89,7 @@ func TestTUI_HappyPath_Success(t *testing.T) {
 	}
 
 	// Simulate navigating to the "Submit" button.
-	for i := 0; i < 3; i++ {
+	for i := 0; i < len(m.inputs); i++ {
 		m, _ = dispatch(m, tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -161,6 +170,108 @@ func TestTUI_PipelineError(t *testing.T) {
 	}
 }
 
+// Given a user who fills in the optional model parameters,
+// verify that they reach the pipeline and the chosen P/E is logged.
+func TestTUI_ModelParams(t *testing.T) {
+	mockPipeline := &mockFairValuePipeline{
+		outputToReturn: &pipelines.LynchFairValueOutputs{
+			Model: pipelines.LynchModelSummary{
+				LynchModelParams: pipelines.LynchModelParams{PEOverride: 18},
+				FairValuePE:      18,
+			},
+		},
+	}
+	rootPipelines := &pipelines.Pipelines{LynchFairValue: mockPipeline}
+	m := NewModel(rootPipelines, nil)
+	var cmd tea.Cmd
+
+	m.inputs[0].SetValue("TSLA")
+	m.inputs[3].SetValue("5")
+	m.inputs[4].SetValue("18")
+	m.inputs[6].SetValue("40.5")
+	m.inputs[7].SetValue("Regression")
+	m.focusIndex = len(m.inputs)
+	m, cmd = dispatch(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = processCmd(m, cmd)
+
+	expected := pipelines.LynchModelParams{CAGRYears: 5, PEOverride: 18, MaxPE: 40.5, GrowthMethod: "regression"}
+	if mockPipeline.receivedModel != expected {
+		t.Errorf("Expected pipeline to receive model %+v, got %+v", expected, mockPipeline.receivedModel)
+	}
+	if !containsLog(m.logs, "Fair value P/E 18.00, set by hand") {
+		t.Errorf("Expected logs to contain the model summary, but they did not. Logs: %v", m.logs)
+	}
+}
+
+// Given a model parameter that isn't a number,
+// verify that the pipeline is never run and the bad input is reported.
+func TestTUI_InvalidModelParams(t *testing.T) {
+	mockPipeline := &mockFairValuePipeline{outputToReturn: &pipelines.LynchFairValueOutputs{}}
+	rootPipelines := &pipelines.Pipelines{LynchFairValue: mockPipeline}
+	m := NewModel(rootPipelines, nil)
+	var cmd tea.Cmd
+
+	m.inputs[0].SetValue("TSLA")
+	m.inputs[5].SetValue("ten")
+	m.focusIndex = len(m.inputs)
+	m, cmd = dispatch(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = processCmd(m, cmd)
+
+	if mockPipeline.wasCalled {
+		t.Error("Expected pipeline not to be called with an invalid model parameter")
+	}
+	if m.processingComplete {
+		t.Error("Expected model.processingComplete to be false after an error")
+	}
+	expectedErrorText := `min P/E must be a number, got "ten"`
+	if !containsLog(m.logs, expectedErrorText) {
+		t.Errorf("Expected logs to contain '%s', but they did not. Logs: %v", expectedErrorText, m.logs)
+	}
+}
+
+// Given a run that crossed into the valuation bands more times than the log pane flags,
+// verify that signals were requested and only the latest are flagged as signals.
+func TestTUI_ValuationSignals(t *testing.T) {
+	var signals []types.ValuationSignalRecord
+	for day := 1; day <= maxSignalLogs+2; day++ {
+		signals = append(signals, types.ValuationSignalRecord{
+			Ticker:         "NVDA",
+			Date:           fmt.Sprintf("2025-01-%02d", day),
+			Price:          130,
+			FairValuePrice: 100,
+			PremiumPct:     30,
+			Signal:         types.SignalOvervalued,
+		})
+	}
+	mockPipeline := &mockFairValuePipeline{
+		outputToReturn: &pipelines.LynchFairValueOutputs{FilePath: "NVDA.parquet", Signals: signals},
+	}
+	rootPipelines := &pipelines.Pipelines{LynchFairValue: mockPipeline}
+	m := NewModel(rootPipelines, nil)
+
+	m.inputs[0].SetValue("NVDA")
+	m.focusIndex = len(m.inputs)
+	m, cmd := dispatch(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = processCmd(m, cmd)
+
+	if !mockPipeline.receivedSignals {
+		t.Error("Expected the pipeline to be asked for valuation signals")
+	}
+	var signalLogs []string
+	for _, log := range m.logs {
+		if log.Type == SignalLog {
+			signalLogs = append(signalLogs, log.Message)
+		}
+	}
+	if len(signalLogs) != maxSignalLogs {
+		t.Fatalf("Expected %d signal logs, got %d: %v", maxSignalLogs, len(signalLogs), signalLogs)
+	}
+	expected := "NVDA overvalued on 2025-01-07, closed at 130.00 against a fair value of 100.00 (+30.00%)"
+	if signalLogs[len(signalLogs)-1] != expected {
+		t.Errorf("Expected the last signal log to be %q, got %q", expected, signalLogs[len(signalLogs)-1])
+	}
+}
+
 // Given a set of initial logs passed to the constructor,
 // verify that they are present in the model's state immediately.
 func TestTUI_InitialLogs(t *testing.T) {
@@ -183,4 +294,51 @@ func TestTUI_InitialLogs(t *testing.T) {
 	}
 }
 
+// Given a user who quits while a pipeline run is in flight,
+// verify that the run's context is cancelled so no requests are left hanging.
+func TestTUI_QuitCancelsInFlightRun(t *testing.T) {
+	mockPipeline := &mockFairValuePipeline{outputToReturn: &pipelines.LynchFairValueOutputs{}}
+	rootPipelines := &pipelines.Pipelines{LynchFairValue: mockPipeline}
+	m := NewModel(rootPipelines, nil)
+
+	m.inputs[0].SetValue("TSLA")
+	m.focusIndex = len(m.inputs)
+	m, runCmd := dispatch(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.cancelRun == nil {
+		t.Fatal("Expected a cancel function to be stored for the in flight run")
+	}
+
+	// Run the pipeline command to capture the context it was given, then quit before its result arrives.
+	for _, subCmd := range runCmd().(tea.BatchMsg) {
+		subCmd()
+	}
+	m, _ = dispatch(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	if mockPipeline.receivedCtx == nil {
+		t.Fatal("Expected pipeline to receive a context")
+	}
+	if !errors.Is(mockPipeline.receivedCtx.Err(), context.Canceled) {
+		t.Errorf("Expected the run context to be cancelled on quit, got: %v", mockPipeline.receivedCtx.Err())
+	}
+}
+
+// Given a pipeline run that ends because it was cancelled,
+// verify that it is logged as a cancellation instead of an error.
+func TestTUI_CancelledRunIsNotAnError(t *testing.T) {
+	m := NewModel(nil, nil)
+	m.loading = true
+
+	m, _ = dispatch(m, processErrorMsg{err: context.Canceled})
+
+	if m.loading {
+		t.Error("Expected model.loading to be false after cancellation")
+	}
+	if !containsLog(m.logs, "Run cancelled.") {
+		t.Errorf("Expected logs to contain 'Run cancelled.', but they did not. Logs: %v", m.logs)
+	}
+	if containsLog(m.logs, "Error:") {
+		t.Errorf("Expected no error log for a cancelled run. Logs: %v", m.logs)
+	}
+}
+
 // todo test log styles in the Logs pane
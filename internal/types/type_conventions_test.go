@@ -53,6 +53,12 @@ func TestParquetTagConventions(t *testing.T) {
 	}{
 		{"AnnualEarningRecordParquet", AnnualEarningRecordParquet{}},
 		{"CombinedPriceRecordParquet", CombinedPriceRecordParquet{}},
+		{"DailyStockRecordParquet", DailyStockRecordParquet{}},
+		{"DividendRecordParquet", DividendRecordParquet{}},
+		{"EarningsEventRecordParquet", EarningsEventRecordParquet{}},
+		{"MetadataRecordParquet", MetadataRecordParquet{}},
+		{"DCFSensitivityRecordParquet", DCFSensitivityRecordParquet{}},
+		{"ValuationSignalRecordParquet", ValuationSignalRecordParquet{}},
 		//! Add other Parquet structs here in the future
 	}
 
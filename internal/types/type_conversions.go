@@ -28,6 +28,66 @@ func CombinedPricesToParquet(
 	return parquetRecords
 }
 
+// Converts a slice of daily OHLCV records for Parquet writing.
+func DailyStocksToParquet(
+	records []DailyStockRecord) []DailyStockRecordParquet {
+	parquetRecords := make([]DailyStockRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = DailyStockRecordParquet(record)
+	}
+	return parquetRecords
+}
+
+// Converts a slice of dividends for Parquet writing.
+func DividendsToParquet(
+	records []DividendRecord) []DividendRecordParquet {
+	parquetRecords := make([]DividendRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = DividendRecordParquet(record)
+	}
+	return parquetRecords
+}
+
+// Converts a slice of run metadata entries for Parquet writing.
+func MetadataToParquet(
+	records []MetadataRecord) []MetadataRecordParquet {
+	parquetRecords := make([]MetadataRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = MetadataRecordParquet(record)
+	}
+	return parquetRecords
+}
+
+// Converts a DCF sensitivity grid for Parquet writing.
+func DCFSensitivityToParquet(
+	records []DCFSensitivityRecord) []DCFSensitivityRecordParquet {
+	parquetRecords := make([]DCFSensitivityRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = DCFSensitivityRecordParquet(record)
+	}
+	return parquetRecords
+}
+
+// Converts a slice of valuation signals for Parquet writing.
+func ValuationSignalsToParquet(
+	records []ValuationSignalRecord) []ValuationSignalRecordParquet {
+	parquetRecords := make([]ValuationSignalRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = ValuationSignalRecordParquet(record)
+	}
+	return parquetRecords
+}
+
+// Converts a slice of earnings events for Parquet writing.
+func EarningsEventsToParquet(
+	records []EarningsEventRecord) []EarningsEventRecordParquet {
+	parquetRecords := make([]EarningsEventRecordParquet, len(records))
+	for i, record := range records {
+		parquetRecords[i] = EarningsEventRecordParquet(record)
+	}
+	return parquetRecords
+}
+
 func DailyAndFairPriceToCombined(
 	dailyPrices []DailyStockRecord,
 	fairValuePrices []FairValuePriceRecord) []CombinedPriceRecord {
@@ -39,18 +99,219 @@ func DailyAndFairPriceToCombined(
 			Ticker: record.Ticker,
 			Date:   record.Date,
 			Price:  record.ClosingPrice,
-			Series: "daily_price",
+			Series: SeriesDailyPrice,
 		})
 	}
 
+	return append(combinedData, FairValueToCombined(fairValuePrices, SeriesFairValue)...)
+}
+
+// Converts fair value prices to combined records under the given series name, for pipelines
+// whose fair value needs to be told apart from the Lynch one on the same chart.
+func FairValueToCombined(fairValuePrices []FairValuePriceRecord, series string) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, len(fairValuePrices))
 	for _, record := range fairValuePrices {
 		combinedData = append(combinedData, CombinedPriceRecord{
 			Ticker: record.Ticker,
 			Date:   record.Date,
 			Price:  record.FairValuePrice,
-			Series: "fair_value",
+			Series: series,
 		})
 	}
+	return combinedData
+}
 
+// Converts dividend yields to combined records so they can be charted against the daily price.
+func DividendYieldToCombined(yields []DividendYieldRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, len(yields))
+	for _, record := range yields {
+		combinedData = append(combinedData, CombinedPriceRecord{
+			Ticker: record.Ticker,
+			Date:   record.Date,
+			Price:  record.Yield,
+			Series: SeriesDividendYield,
+		})
+	}
+	return combinedData
+}
+
+// Converts trailing twelve month EPS to combined records, dated at each fiscal quarter end.
+func TTMEPSToCombined(earnings []AnnualEarningRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, len(earnings))
+	for _, record := range earnings {
+		combinedData = append(combinedData, CombinedPriceRecord{
+			Ticker: record.Ticker,
+			Date:   record.FiscalDateEnding,
+			Price:  record.ReportedEPS,
+			Series: SeriesTTMEPS,
+		})
+	}
+	return combinedData
+}
+
+// Converts CAPE records to combined records, one cape_ratio and one cape_fair_value per day.
+func CAPEToCombined(records []CAPERecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, 2*len(records))
+	for _, record := range records {
+		combinedData = append(combinedData,
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.Ratio,
+				Series: SeriesCAPERatio,
+			},
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.FairValuePrice,
+				Series: SeriesCAPEFairValue,
+			})
+	}
+	return combinedData
+}
+
+// Converts earnings surprises to combined records, with the beat, miss or inline result as the
+// series so the chart can give each its own marker.
+func EarningsSurprisesToCombined(surprises []EarningsSurpriseRecord) []CombinedPriceRecord {
+	seriesFor := map[string]string{
+		EarningsBeat:   SeriesEarningsBeat,
+		EarningsMiss:   SeriesEarningsMiss,
+		EarningsInline: SeriesEarningsInline,
+	}
+
+	combinedData := make([]CombinedPriceRecord, 0, len(surprises))
+	for _, record := range surprises {
+		combinedData = append(combinedData, CombinedPriceRecord{
+			Ticker: record.Ticker,
+			Date:   record.ReportedDate,
+			Price:  record.SurprisePercentage,
+			Series: seriesFor[record.Result],
+		})
+	}
+	return combinedData
+}
+
+// Converts projected fair values to combined records, one point per estimate in each of the
+// projected, high and low series.
+func ProjectedFairValueToCombined(projections []ProjectedFairValueRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, 3*len(projections))
+	for _, record := range projections {
+		combinedData = append(combinedData,
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.FairValuePrice,
+				Series: SeriesFairValueProjected,
+			},
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.HighFairValuePrice,
+				Series: SeriesFairValueProjectedHigh,
+			},
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.LowFairValuePrice,
+				Series: SeriesFairValueProjectedLow,
+			})
+	}
+	return combinedData
+}
+
+// Converts daily P/E records to combined records.
+func PERatioToCombined(records []PERatioRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, len(records))
+	for _, record := range records {
+		combinedData = append(combinedData, CombinedPriceRecord{
+			Ticker: record.Ticker,
+			Date:   record.Date,
+			Price:  record.Ratio,
+			Series: SeriesPERatio,
+		})
+	}
+	return combinedData
+}
+
+// Converts P/E bands to combined records, a point at each end of the band's range so it charts as
+// a flat line.
+func PERatioBandsToCombined(bands []PERatioBandRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, 2*len(bands))
+	for _, band := range bands {
+		combinedData = append(combinedData,
+			CombinedPriceRecord{
+				Ticker: band.Ticker,
+				Date:   band.StartDate,
+				Price:  band.Ratio,
+				Series: band.Series,
+			},
+			CombinedPriceRecord{
+				Ticker: band.Ticker,
+				Date:   band.EndDate,
+				Price:  band.Ratio,
+				Series: band.Series,
+			})
+	}
+	return combinedData
+}
+
+// Converts PEG records to combined records, a peg_ratio and a pegy_ratio record for each day.
+func PEGToCombined(records []PEGRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, 2*len(records))
+	for _, record := range records {
+		combinedData = append(combinedData,
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.PEG,
+				Series: SeriesPEGRatio,
+			},
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.PEGY,
+				Series: SeriesPEGYRatio,
+			})
+	}
+	return combinedData
+}
+
+// Converts daily fair values to combined records, one fair_value_daily and one premium_pct per day.
+func DailyFairValueToCombined(records []DailyFairValueRecord) []CombinedPriceRecord {
+	combinedData := make([]CombinedPriceRecord, 0, 2*len(records))
+	for _, record := range records {
+		combinedData = append(combinedData,
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.FairValuePrice,
+				Series: SeriesDailyFairValue,
+			},
+			CombinedPriceRecord{
+				Ticker: record.Ticker,
+				Date:   record.Date,
+				Price:  record.PremiumPct,
+				Series: SeriesPremiumPct,
+			})
+	}
+	return combinedData
+}
+
+// Converts valuation signals to combined records, one marker per signal at the day's close.
+func ValuationSignalsToCombined(signals []ValuationSignalRecord) []CombinedPriceRecord {
+	seriesFor := map[string]string{
+		SignalOvervalued:  SeriesSignalOvervalued,
+		SignalUndervalued: SeriesSignalUndervalued,
+	}
+
+	combinedData := make([]CombinedPriceRecord, 0, len(signals))
+	for _, record := range signals {
+		combinedData = append(combinedData, CombinedPriceRecord{
+			Ticker: record.Ticker,
+			Date:   record.Date,
+			Price:  record.Price,
+			Series: seriesFor[record.Signal],
+		})
+	}
 	return combinedData
 }
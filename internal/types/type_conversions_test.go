@@ -214,3 +214,168 @@ func TestDailyAndFairPriceToCombined_BothNil(t *testing.T) {
 		t.Errorf("DailyAndFairPriceToCombined() expected zero-length slice for nil inputs, got %d", len(result))
 	}
 }
+
+// Given a slice of DailyStockRecords, verify every OHLCV field survives conversion.
+func TestDailyStocksToParquet_Success(t *testing.T) {
+	inputRecords := []DailyStockRecord{
+		{Ticker: "TEST", Date: "2025-01-01", OpenPrice: 99.0, HighPrice: 101.0, LowPrice: 98.5, ClosingPrice: 100.0, Volume: 1500},
+	}
+
+	expectedOutput := []DailyStockRecordParquet{
+		{Ticker: "TEST", Date: "2025-01-01", OpenPrice: 99.0, HighPrice: 101.0, LowPrice: 98.5, ClosingPrice: 100.0, Volume: 1500},
+	}
+
+	result := DailyStocksToParquet(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("DailyStocksToParquet() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given dividend yields, verify they're converted to the dividend_yield series with the yield in
+// the price column.
+func TestDividendYieldToCombined_Success(t *testing.T) {
+	inputRecords := []DividendYieldRecord{
+		{Ticker: "TEST", Date: "2025-01-01", Yield: 0.025},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-01", Price: 0.025, Series: SeriesDividendYield},
+	}
+
+	result := DividendYieldToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("DividendYieldToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a CAPE record, verify it's split into a ratio and a fair value series for the same day.
+func TestCAPEToCombined_Success(t *testing.T) {
+	inputRecords := []CAPERecord{
+		{Ticker: "TEST", Date: "2025-01-01", Ratio: 25, AverageRealEPS: 4, FairValuePrice: 80},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-01", Price: 25, Series: SeriesCAPERatio},
+		{Ticker: "TEST", Date: "2025-01-01", Price: 80, Series: SeriesCAPEFairValue},
+	}
+
+	result := CAPEToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("CAPEToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given TTM EPS records, verify each becomes a ttm_eps point on its fiscal quarter end.
+func TestTTMEPSToCombined_Success(t *testing.T) {
+	inputRecords := []AnnualEarningRecord{
+		{Ticker: "TEST", FiscalDateEnding: "2025-03-31", ReportedEPS: 6.5},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-03-31", Price: 6.5, Series: SeriesTTMEPS},
+	}
+
+	result := TTMEPSToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("TTMEPSToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given one report of each result, verify each lands in its own series with the surprise percentage.
+func TestEarningsSurprisesToCombined_Success(t *testing.T) {
+	inputRecords := []EarningsSurpriseRecord{
+		{Ticker: "TEST", ReportedDate: "2025-01-28", SurprisePercentage: 12.5, Result: EarningsBeat},
+		{Ticker: "TEST", ReportedDate: "2025-04-24", SurprisePercentage: -4, Result: EarningsMiss},
+		{Ticker: "TEST", ReportedDate: "2025-07-23", SurprisePercentage: 0, Result: EarningsInline},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-28", Price: 12.5, Series: SeriesEarningsBeat},
+		{Ticker: "TEST", Date: "2025-04-24", Price: -4, Series: SeriesEarningsMiss},
+		{Ticker: "TEST", Date: "2025-07-23", Price: 0, Series: SeriesEarningsInline},
+	}
+
+	result := EarningsSurprisesToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("EarningsSurprisesToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a projection, verify it's split into the projected, high and low series for the same day.
+func TestProjectedFairValueToCombined_Success(t *testing.T) {
+	inputRecords := []ProjectedFairValueRecord{
+		{Ticker: "TEST", Date: "2026-12-31", FairValuePrice: 120, HighFairValuePrice: 130, LowFairValuePrice: 110},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2026-12-31", Price: 120, Series: SeriesFairValueProjected},
+		{Ticker: "TEST", Date: "2026-12-31", Price: 130, Series: SeriesFairValueProjectedHigh},
+		{Ticker: "TEST", Date: "2026-12-31", Price: 110, Series: SeriesFairValueProjectedLow},
+	}
+
+	result := ProjectedFairValueToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("ProjectedFairValueToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given a P/E band, verify it becomes two points of its own series at the ends of its range.
+func TestPERatioBandsToCombined_Success(t *testing.T) {
+	inputRecords := []PERatioBandRecord{
+		{Ticker: "TEST", Series: SeriesPERatioMedian, StartDate: "2020-01-02", EndDate: "2024-12-31", Ratio: 21.5},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2020-01-02", Price: 21.5, Series: SeriesPERatioMedian},
+		{Ticker: "TEST", Date: "2024-12-31", Price: 21.5, Series: SeriesPERatioMedian},
+	}
+
+	result := PERatioBandsToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("PERatioBandsToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given daily fair value records, verify each day becomes a fair_value_daily and a premium_pct point.
+func TestDailyFairValueToCombined_Success(t *testing.T) {
+	inputRecords := []DailyFairValueRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 110, FairValuePrice: 100, PremiumPct: 10},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 100, Series: SeriesDailyFairValue},
+		{Ticker: "TEST", Date: "2025-01-02", Price: 10, Series: SeriesPremiumPct},
+	}
+
+	result := DailyFairValueToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("DailyFairValueToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Given one signal of each kind, verify each lands in its own series at the day's close.
+func TestValuationSignalsToCombined_Success(t *testing.T) {
+	inputRecords := []ValuationSignalRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 125, FairValuePrice: 100, PremiumPct: 25, Signal: SignalOvervalued},
+		{Ticker: "TEST", Date: "2025-03-03", Price: 75, FairValuePrice: 100, PremiumPct: -25, Signal: SignalUndervalued},
+	}
+
+	expectedOutput := []CombinedPriceRecord{
+		{Ticker: "TEST", Date: "2025-01-02", Price: 125, Series: SeriesSignalOvervalued},
+		{Ticker: "TEST", Date: "2025-03-03", Price: 75, Series: SeriesSignalUndervalued},
+	}
+
+	result := ValuationSignalsToCombined(inputRecords)
+
+	if diff := cmp.Diff(expectedOutput, result); diff != "" {
+		t.Errorf("ValuationSignalsToCombined() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -6,7 +6,11 @@ package types
 type DailyStockRecord struct {
 	Ticker       string
 	Date         string
+	OpenPrice    float64
+	HighPrice    float64
+	LowPrice     float64
 	ClosingPrice float64
+	Volume       int64
 }
 
 type AnnualEarningRecord struct {
@@ -21,12 +25,232 @@ type FairValuePriceRecord struct {
 	Date           string
 }
 
+type AnnualRevenueRecord struct {
+	Ticker           string
+	FiscalDateEnding string
+	TotalRevenue     float64
+}
+
+type SharesOutstandingRecord struct {
+	Ticker            string
+	FiscalDateEnding  string
+	SharesOutstanding float64
+}
+
+type RevenuePerShareRecord struct {
+	Ticker           string
+	FiscalDateEnding string
+	RevenuePerShare  float64
+}
+
+// Total shareholder equity over common shares outstanding, negative for companies with more
+// liabilities than assets.
+type BookValuePerShareRecord struct {
+	Ticker            string
+	FiscalDateEnding  string
+	BookValuePerShare float64
+}
+
+// Operating cash flow minus capital expenditures for a fiscal year, in dollars.
+type FreeCashFlowRecord struct {
+	Ticker              string
+	FiscalDateEnding    string
+	OperatingCashFlow   float64
+	CapitalExpenditures float64 // Always positive, the amount spent
+	FreeCashFlow        float64
+}
+
+type FreeCashFlowPerShareRecord struct {
+	Ticker               string
+	FiscalDateEnding     string
+	FreeCashFlowPerShare float64
+}
+
 type StockSplitRecord struct {
 	Ticker        string
 	EffectiveDate string
 	SplitFactor   float64
 }
 
+// One dividend payment as declared. Alpha Vantage has no declaration or record date for a lot of
+// older dividends, those are left empty.
+type DividendRecord struct {
+	Ticker          string
+	ExDividendDate  string
+	DeclarationDate string
+	RecordDate      string
+	PaymentDate     string
+	Amount          float64
+}
+
+type DividendYieldRecord struct {
+	Ticker string
+	Date   string
+	Yield  float64 // Fraction of the closing price, 0.025 is 2.5%
+}
+
+// One quarterly earnings report and when it was released.
+type QuarterlyEarningRecord struct {
+	Ticker           string
+	FiscalDateEnding string
+	ReportedDate     string
+	ReportTime       string // "pre-market", "post-market" or empty when not known
+	ReportedEPS      float64
+	// Analyst consensus going into the report and how far the reported EPS landed from it. All
+	// zero when Alpha Vantage has no estimate for the quarter, which is common before the 2000s.
+	EstimatedEPS       float64
+	Surprise           float64
+	SurprisePercentage float64
+}
+
+// Where an EarningsEventRecord's date came from.
+const (
+	EarningsSourceCalendar  = "calendar"  // Scheduled, from the Alpha Vantage earnings calendar
+	EarningsSourceEstimated = "estimated" // Projected from the company's past report dates
+)
+
+// An upcoming earnings report for a calendar.
+type EarningsEventRecord struct {
+	Ticker           string
+	CompanyName      string // Only known for calendar events
+	ReportDate       string
+	FiscalDateEnding string
+	ReportTime       string
+	EstimatedEPS     float64 // Zero when analysts haven't published an estimate
+	Source           string
+}
+
+// Estimate horizons used for projecting fair value, as Alpha Vantage names them.
+const (
+	EstimateHorizonCurrentYear = "current fiscal year"
+	EstimateHorizonNextYear    = "next fiscal year"
+)
+
+// Analyst consensus EPS for a fiscal period that hasn't been reported yet.
+type EarningsEstimateRecord struct {
+	Ticker       string
+	Date         string // Fiscal period end the estimate is for
+	Horizon      string
+	AverageEPS   float64
+	HighEPS      float64
+	LowEPS       float64
+	AnalystCount float64
+}
+
+// Fair value projected from estimated EPS, with the band given by the high and low estimates.
+type ProjectedFairValueRecord struct {
+	Ticker             string
+	Date               string
+	FairValuePrice     float64
+	HighFairValuePrice float64
+	LowFairValuePrice  float64
+}
+
+// How a quarterly report landed against the analyst estimate.
+const (
+	EarningsBeat   = "beat"
+	EarningsMiss   = "miss"
+	EarningsInline = "inline"
+)
+
+// A past quarterly report compared to its estimate, dated on the day the market heard about it.
+type EarningsSurpriseRecord struct {
+	Ticker             string
+	ReportedDate       string
+	FiscalDateEnding   string
+	ReportedEPS        float64
+	EstimatedEPS       float64
+	Surprise           float64
+	SurprisePercentage float64
+	Result             string // EarningsBeat, EarningsMiss or EarningsInline
+}
+
+// One reading of the consumer price index, e.g. a month of FRED's CPIAUCSL series.
+type CPIRecord struct {
+	Date  string
+	Value float64
+}
+
+// Shiller's cyclically adjusted P/E for one trading day and the price that CAPE implies.
+type CAPERecord struct {
+	Ticker string
+	Date   string
+	Ratio  float64
+	// Ten year average of inflation adjusted annual EPS, in the trading day's dollars.
+	AverageRealEPS float64
+	FairValuePrice float64
+}
+
+// The P/E the market paid on one trading day, on the EPS that was known that day.
+type PERatioRecord struct {
+	Ticker string
+	Date   string
+	Ratio  float64
+	EPS    float64
+}
+
+// A P/E level the daily P/E is compared against over a date range, e.g. its median.
+type PERatioBandRecord struct {
+	Ticker    string
+	Series    string // One of the SeriesPERatio band series
+	StartDate string
+	EndDate   string
+	Ratio     float64
+}
+
+// A day's P/E set against the EPS growth known that day, without and with the dividend yield.
+type PEGRecord struct {
+	Ticker        string
+	Date          string
+	PERatio       float64
+	Growth        float64 // Trailing EPS CAGR as a fraction
+	DividendYield float64 // Trailing twelve month yield as a fraction, 0 for non payers
+	PEG           float64
+	PEGY          float64
+}
+
+// The fair value carried onto one trading day, and how far that day's close was above (positive) or
+// below (negative) it in percent.
+type DailyFairValueRecord struct {
+	Ticker         string
+	Date           string
+	Price          float64
+	FairValuePrice float64
+	PremiumPct     float64
+}
+
+const (
+	SignalOvervalued  = "overvalued"
+	SignalUndervalued = "undervalued"
+)
+
+// A trading day the close crossed into the overvalued or undervalued band around the daily fair value.
+type ValuationSignalRecord struct {
+	Ticker         string
+	Date           string
+	Price          float64
+	FairValuePrice float64
+	PremiumPct     float64
+	Signal         string // SignalOvervalued or SignalUndervalued
+}
+
+// The DCF fair value of one fiscal year at one discount rate and terminal growth, both in percent.
+// One cell of a sensitivity grid.
+type DCFSensitivityRecord struct {
+	Ticker           string
+	FiscalDateEnding string
+	DiscountRate     float64
+	TerminalGrowth   float64
+	FairValuePrice   float64
+}
+
+// One entry of a run's metadata, flattened to a key/value pair so runs with different inputs share
+// a table layout.
+type MetadataRecord struct {
+	Key   string
+	Value string
+}
+
 /*
 Intention of the CombinedPriceRecord type is to allow "long" writing of price data.
 Example:
@@ -44,6 +268,54 @@ type CombinedPriceRecord struct {
 	Series string // fair value estimate, daily, etc
 }
 
+// Series names used in CombinedPriceRecord. The web UI keys its chart traces off these, so keep
+// react_ui/src/types in sync when adding one.
+const (
+	SeriesDailyPrice  = "daily_price"
+	SeriesFairValue   = "fair_value"
+	SeriesFairValuePS = "fair_value_ps"
+	// Not a price, the Price column holds the trailing twelve month yield as a fraction.
+	SeriesDividendYield = "dividend_yield"
+	// Not a price, the Price column holds the CAPE ratio itself.
+	SeriesCAPERatio     = "cape_ratio"
+	SeriesCAPEFairValue = "cape_fair_value"
+	// Not a price, the Price column holds trailing twelve month EPS as of each fiscal quarter end.
+	SeriesTTMEPS = "ttm_eps"
+	// Earnings report markers, one per report with an estimate. Not a price, the Price column holds
+	// the surprise percentage.
+	SeriesEarningsBeat   = "earnings_beat"
+	SeriesEarningsMiss   = "earnings_miss"
+	SeriesEarningsInline = "earnings_inline"
+	// Lynch fair value carried forward onto analyst EPS estimates.
+	SeriesFairValueProjected     = "fair_value_projected"
+	SeriesFairValueProjectedHigh = "fair_value_projected_high"
+	SeriesFairValueProjectedLow  = "fair_value_projected_low"
+	// Not prices, the Price column holds the P/E the market paid each day and the bands it is
+	// compared against. Each band has one point at the start and one at the end of the range.
+	SeriesPERatio       = "pe_ratio"
+	SeriesPERatioMean   = "pe_ratio_mean"
+	SeriesPERatioMedian = "pe_ratio_median"
+	SeriesPERatioP10    = "pe_ratio_p10"
+	SeriesPERatioP25    = "pe_ratio_p25"
+	SeriesPERatioP75    = "pe_ratio_p75"
+	SeriesPERatioP90    = "pe_ratio_p90"
+	// Not prices, the Price column holds the PEG and dividend adjusted PEGY ratios. 1 is fair value
+	// by Lynch's rule of thumb.
+	SeriesPEGRatio  = "peg_ratio"
+	SeriesPEGYRatio = "pegy_ratio"
+	// Benjamin Graham's fair values, next to the Lynch one.
+	SeriesGrahamNumber = "graham_number"
+	SeriesGrahamValue  = "graham_value"
+	// Discounted free cash flow fair value, from its own pipeline.
+	SeriesDCFFairValue = "dcf_fair_value"
+	// The fair value carried onto every trading day, and the close's premium over it in percent.
+	SeriesDailyFairValue = "fair_value_daily"
+	SeriesPremiumPct     = "premium_pct"
+	// Valuation signal markers, one per crossing into a band, at the day's close.
+	SeriesSignalOvervalued  = "signal_overvalued"
+	SeriesSignalUndervalued = "signal_undervalued"
+)
+
 // ---- Parquet types
 //! New parquet types must be added to type_test.go for convention testing
 
@@ -59,3 +331,56 @@ type AnnualEarningRecordParquet struct {
 	FiscalDateEnding string  `parquet:"name=fiscal_date_ending,type=BYTE_ARRAY,convertedtype=UTF8"`
 	ReportedEPS      float64 `parquet:"name=reported_eps,type=DOUBLE"`
 }
+
+// Full OHLCV history, "wide" unlike CombinedPriceRecordParquet, for candle charts and volume analysis.
+type DailyStockRecordParquet struct {
+	Ticker       string  `parquet:"name=ticker,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Date         string  `parquet:"name=date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	OpenPrice    float64 `parquet:"name=open_price,type=DOUBLE"`
+	HighPrice    float64 `parquet:"name=high_price,type=DOUBLE"`
+	LowPrice     float64 `parquet:"name=low_price,type=DOUBLE"`
+	ClosingPrice float64 `parquet:"name=closing_price,type=DOUBLE"`
+	Volume       int64   `parquet:"name=volume,type=INT64"`
+}
+
+type DividendRecordParquet struct {
+	Ticker          string  `parquet:"name=ticker,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ExDividendDate  string  `parquet:"name=ex_dividend_date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	DeclarationDate string  `parquet:"name=declaration_date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	RecordDate      string  `parquet:"name=record_date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	PaymentDate     string  `parquet:"name=payment_date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Amount          float64 `parquet:"name=amount,type=DOUBLE"`
+}
+
+type EarningsEventRecordParquet struct {
+	Ticker           string  `parquet:"name=ticker,type=BYTE_ARRAY,convertedtype=UTF8"`
+	CompanyName      string  `parquet:"name=company_name,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ReportDate       string  `parquet:"name=report_date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	FiscalDateEnding string  `parquet:"name=fiscal_date_ending,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ReportTime       string  `parquet:"name=report_time,type=BYTE_ARRAY,convertedtype=UTF8"`
+	EstimatedEPS     float64 `parquet:"name=estimated_eps,type=DOUBLE"`
+	Source           string  `parquet:"name=source,type=BYTE_ARRAY,convertedtype=UTF8"`
+}
+
+// Run metadata as key/value rows. Values are JSON encoded so numbers and strings can share a column.
+type MetadataRecordParquet struct {
+	Key   string `parquet:"name=key,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Value string `parquet:"name=value,type=BYTE_ARRAY,convertedtype=UTF8"`
+}
+
+type ValuationSignalRecordParquet struct {
+	Ticker         string  `parquet:"name=ticker,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Date           string  `parquet:"name=date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Price          float64 `parquet:"name=price,type=DOUBLE"`
+	FairValuePrice float64 `parquet:"name=fair_value_price,type=DOUBLE"`
+	PremiumPct     float64 `parquet:"name=premium_pct,type=DOUBLE"`
+	Signal         string  `parquet:"name=signal,type=BYTE_ARRAY,convertedtype=UTF8"`
+}
+
+type DCFSensitivityRecordParquet struct {
+	Ticker           string  `parquet:"name=ticker,type=BYTE_ARRAY,convertedtype=UTF8"`
+	FiscalDateEnding string  `parquet:"name=fiscal_date_ending,type=BYTE_ARRAY,convertedtype=UTF8"`
+	DiscountRate     float64 `parquet:"name=discount_rate,type=DOUBLE"`
+	TerminalGrowth   float64 `parquet:"name=terminal_growth,type=DOUBLE"`
+	FairValuePrice   float64 `parquet:"name=fair_value_price,type=DOUBLE"`
+}
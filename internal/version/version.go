@@ -0,0 +1,28 @@
+package version
+
+import "runtime/debug"
+
+// Set at build time, e.g. go build -ldflags "-X cibo/internal/version.Version=v1.2.0". The Makefile
+// sets it from git describe.
+var Version = ""
+
+// The cibo version, falling back to the module version and VCS revision Go stamps into the binary,
+// and "dev" when neither is known, e.g. under go run or go test.
+func String() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return "dev-" + setting.Value
+		}
+	}
+	return "dev"
+}
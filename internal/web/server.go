@@ -67,6 +67,10 @@ func newServerHandler(filePath string) http.Handler {
 			http.Error(w, "Could not read data file", http.StatusInternalServerError)
 			return
 		}
+		// The client went away or the server is shutting down, nobody is left to send the data to.
+		if r.Context().Err() != nil {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(records)
 	})
@@ -89,8 +93,15 @@ func StartNonBlocking(listener net.Listener, filePath string) {
 }
 
 func StartServer(listener net.Listener, filePath string) {
+	// Request contexts derive from the signal context so in flight requests see Ctrl+C right away.
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	handler := newServerHandler(filePath)
-	server := &http.Server{Handler: handler}
+	server := &http.Server{
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return signalCtx },
+	}
 
 	go func() {
 		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
@@ -98,9 +109,7 @@ func StartServer(listener net.Listener, filePath string) {
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-signalCtx.Done()
 	log.Println("Shutdown signal received, shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -1,6 +1,6 @@
 import Plot from 'react-plotly.js';
 import { PriceRecord } from '../types';
-import { Data, Layout } from 'plotly.js';
+import { Dash, Data, Layout } from 'plotly.js';
 
 interface PriceChartProps {
     data: PriceRecord[];
@@ -23,6 +23,176 @@ function PriceChart({ data }: PriceChartProps) {
         line: { color: '#ff7f0e' },
     };
 
+    // The fair value on every trading day, with the close's premium over it in the hover text.
+    const dailyFairValue: Partial<Data> = {
+        x: [],
+        y: [],
+        text: [],
+        mode: 'lines',
+        name: 'Daily Fair Value',
+        line: { color: '#ff7f0e', width: 1 },
+        hovertemplate: '%{y:$.2f}<br>%{text}<extra>Daily Fair Value</extra>',
+    };
+    const premiumByDate: Record<string, number> = {};
+
+    const psFairValue: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines+markers',
+        name: 'P/S Fair Value',
+        line: { color: '#2ca02c' },
+    };
+
+    const dcfFairValue: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines+markers',
+        name: 'DCF Fair Value',
+        line: { color: '#8c564b' },
+    };
+
+    // Daily, so drawn as a line. The CAPE ratio itself isn't a price and stays off this chart.
+    const capeFairValue: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: 'CAPE Fair Value',
+        line: { color: '#9467bd' },
+    };
+
+    // Graham's values, one point per fiscal year like the Lynch fair value.
+    const grahamNumber: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines+markers',
+        name: 'Graham Number',
+        line: { color: '#17becf' },
+    };
+    const grahamValue: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines+markers',
+        name: 'Graham Value',
+        line: { color: '#17becf', dash: 'dot' },
+    };
+
+    // Forward projection from analyst estimates. The band is filled between the low and high
+    // traces, which relies on high coming right after low in the trace list.
+    const projectedFairValue: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines+markers',
+        name: 'Projected Fair Value',
+        line: { color: '#ff7f0e', dash: 'dash' },
+    };
+    const projectedLow: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: 'Projected Low',
+        line: { color: 'rgba(255, 127, 14, 0.4)', width: 1 },
+        showlegend: false,
+    };
+    const projectedHigh: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: 'Projected Range',
+        line: { color: 'rgba(255, 127, 14, 0.4)', width: 1 },
+        fill: 'tonexty',
+        fillcolor: 'rgba(255, 127, 14, 0.15)',
+    };
+
+    // P/E isn't a price, so it goes on its own axis on the right with its bands as flat lines.
+    const peTrace = (name: string, dash: Dash, width: number): Partial<Data> => ({
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: name,
+        yaxis: 'y2',
+        line: { color: '#8c564b', dash: dash, width: width },
+    });
+    const peRatio = peTrace('P/E', 'solid', 1.5);
+    const peTraces = [
+        peRatio,
+        peTrace('P/E Mean', 'dashdot', 1),
+        peTrace('P/E Median', 'dash', 1),
+        peTrace('P/E 10th Percentile', 'dot', 1),
+        peTrace('P/E 25th Percentile', 'dot', 1),
+        peTrace('P/E 75th Percentile', 'dot', 1),
+        peTrace('P/E 90th Percentile', 'dot', 1),
+    ];
+
+    // PEG sits around 1 while P/E sits in the tens, so it gets a third axis with a line at 1.
+    const pegRatio: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: 'PEG',
+        yaxis: 'y3',
+        line: { color: '#e377c2', width: 1.5 },
+    };
+    const pegyRatio: Partial<Data> = {
+        x: [],
+        y: [],
+        mode: 'lines',
+        name: 'PEGY',
+        yaxis: 'y3',
+        line: { color: '#e377c2', dash: 'dash', width: 1 },
+    };
+
+    // Crossings into the over and undervalued bands around the daily fair value, at the day's close.
+    const signalMarker = (name: string, color: string): Partial<Data> => ({
+        x: [],
+        y: [],
+        mode: 'markers',
+        name: name,
+        hovertemplate: `%{x}<br>Closed %{y:$.2f}<extra>${name}</extra>`,
+        marker: { symbol: 'diamond', color: color, size: 11, line: { color: '#ffffff', width: 1 } },
+    });
+    const signalOvervalued = signalMarker('Overvalued', '#d62728');
+    const signalUndervalued = signalMarker('Undervalued', '#2ca02c');
+
+    const seriesTraces: Record<string, Partial<Data>> = {
+        fair_value_projected: projectedFairValue,
+        fair_value_projected_high: projectedHigh,
+        fair_value_projected_low: projectedLow,
+        graham_number: grahamNumber,
+        graham_value: grahamValue,
+        dcf_fair_value: dcfFairValue,
+        fair_value_daily: dailyFairValue,
+        pe_ratio: peTraces[0],
+        pe_ratio_mean: peTraces[1],
+        pe_ratio_median: peTraces[2],
+        pe_ratio_p10: peTraces[3],
+        pe_ratio_p25: peTraces[4],
+        pe_ratio_p75: peTraces[5],
+        pe_ratio_p90: peTraces[6],
+        peg_ratio: pegRatio,
+        pegy_ratio: pegyRatio,
+        signal_overvalued: signalOvervalued,
+        signal_undervalued: signalUndervalued,
+    };
+
+    // Earnings reports, drawn on the price line. Price holds the surprise percentage for these.
+    const earningsMarker = (name: string, symbol: string, color: string): Partial<Data> => ({
+        x: [],
+        y: [],
+        text: [],
+        mode: 'markers',
+        name: name,
+        hovertemplate: '%{x}<br>%{text}<extra></extra>',
+        marker: { symbol: symbol, color: color, size: 11, line: { color: '#ffffff', width: 1 } },
+    });
+    const earningsBeat = earningsMarker('Earnings Beat', 'triangle-up', '#2ca02c');
+    const earningsMiss = earningsMarker('Earnings Miss', 'triangle-down', '#d62728');
+    const earningsInline = earningsMarker('Earnings Inline', 'circle', '#7f7f7f');
+    const markerFor: Record<string, [Partial<Data>, string]> = {
+        earnings_beat: [earningsBeat, 'Beat'],
+        earnings_miss: [earningsMiss, 'Missed'],
+        earnings_inline: [earningsInline, 'In line'],
+    };
+
     data.forEach((d) => {
         if (d.Series === 'daily_price') {
             (actualPrices.x as string[]).push(d.Date);
@@ -30,9 +200,49 @@ function PriceChart({ data }: PriceChartProps) {
         } else if (d.Series === 'fair_value') {
             (fairValue.x as string[]).push(d.Date);
             (fairValue.y as number[]).push(d.Price);
+        } else if (d.Series === 'fair_value_ps') {
+            (psFairValue.x as string[]).push(d.Date);
+            (psFairValue.y as number[]).push(d.Price);
+        } else if (d.Series === 'premium_pct') {
+            premiumByDate[d.Date] = d.Price;
+        } else if (d.Series === 'cape_fair_value') {
+            (capeFairValue.x as string[]).push(d.Date);
+            (capeFairValue.y as number[]).push(d.Price);
+        } else if (seriesTraces[d.Series]) {
+            (seriesTraces[d.Series].x as string[]).push(d.Date);
+            (seriesTraces[d.Series].y as number[]).push(d.Price);
         }
     });
 
+    dailyFairValue.text = (dailyFairValue.x as string[]).map((date) => {
+        const premium = premiumByDate[date];
+        if (premium === undefined) {
+            return '';
+        }
+        return `Close ${Math.abs(premium).toFixed(1)}% ${premium < 0 ? 'below' : 'above'}`;
+    });
+
+    // Reports land after the close or on days the file has no price for, so each marker sits on
+    // the first closing price on or after its report date.
+    const priceDates = actualPrices.x as string[];
+    const closes = actualPrices.y as number[];
+    const order = priceDates.map((_, i) => i).sort((a, b) => priceDates[a].localeCompare(priceDates[b]));
+    data.forEach((d) => {
+        const marker = markerFor[d.Series];
+        if (!marker) {
+            return;
+        }
+        const next = order.find((i) => priceDates[i] >= d.Date);
+        if (next === undefined) {
+            return;
+        }
+        const [trace, label] = marker;
+        (trace.x as string[]).push(d.Date);
+        (trace.y as number[]).push(closes[next]);
+        const surprise = d.Series === 'earnings_inline' ? '' : ` by ${Math.abs(d.Price).toFixed(1)}%`;
+        (trace.text as string[]).push(`${label} estimates${surprise}`);
+    });
+
     const layout: Partial<Layout> = {
         xaxis: {
             title: { text: 'Date' }
@@ -45,11 +255,53 @@ function PriceChart({ data }: PriceChartProps) {
         legend: { orientation: 'h', y: 1.1 },
         autosize: true,
     };
+    if ((peRatio.x as string[]).length > 0) {
+        layout.yaxis2 = {
+            title: { text: 'P/E' },
+            overlaying: 'y',
+            side: 'right',
+            showgrid: false,
+        };
+        layout.margin = { ...layout.margin, r: 60 };
+    }
+    if ((pegRatio.x as string[]).length > 0) {
+        const withPE = layout.yaxis2 !== undefined;
+        layout.yaxis3 = {
+            title: { text: 'PEG' },
+            overlaying: 'y',
+            side: 'right',
+            anchor: withPE ? 'free' : 'x',
+            position: 1,
+            showgrid: false,
+            rangemode: 'tozero',
+        };
+        if (withPE) {
+            // Make room for both right hand axes.
+            layout.xaxis = { ...layout.xaxis, domain: [0, 0.92] };
+        }
+        layout.margin = { ...layout.margin, r: 60 };
+        layout.shapes = [{
+            type: 'line',
+            xref: 'paper',
+            x0: 0,
+            x1: 1,
+            yref: 'y3',
+            y0: 1,
+            y1: 1,
+            line: { color: '#e377c2', dash: 'dot', width: 1 },
+        }];
+    }
 
+    // Each pipeline writes its own file, so only draw the series this one actually contains.
+    const traces = [
+        actualPrices, fairValue, dailyFairValue, projectedLow, projectedHigh, projectedFairValue, psFairValue,
+        dcfFairValue, capeFairValue, grahamNumber, grahamValue, earningsBeat, earningsMiss, earningsInline,
+        signalOvervalued, signalUndervalued, ...peTraces, pegRatio, pegyRatio,
+    ].filter((trace) => (trace.x as string[]).length > 0);
 
     return (
         <Plot
-            data={[actualPrices as Data, fairValue as Data]}
+            data={traces as Data[]}
             layout={layout}
             useResizeHandler={true}
             style={{ width: '100%', height: '75vh' }}
@@ -2,5 +2,13 @@ export interface PriceRecord {
   Ticker: string;
   Date: string;
   Price: number;
-  Series: 'daily_price' | 'fair_value';
+  Series: 'daily_price' | 'fair_value' | 'fair_value_ps' | 'dividend_yield' | 'cape_ratio' | 'cape_fair_value' | 'ttm_eps'
+    | 'earnings_beat' | 'earnings_miss' | 'earnings_inline'
+    | 'fair_value_projected' | 'fair_value_projected_high' | 'fair_value_projected_low'
+    | 'pe_ratio' | 'pe_ratio_mean' | 'pe_ratio_median' | 'pe_ratio_p10' | 'pe_ratio_p25' | 'pe_ratio_p75' | 'pe_ratio_p90'
+    | 'peg_ratio' | 'pegy_ratio'
+    | 'graham_number' | 'graham_value'
+    | 'dcf_fair_value'
+    | 'fair_value_daily' | 'premium_pct'
+    | 'signal_overvalued' | 'signal_undervalued';
 }